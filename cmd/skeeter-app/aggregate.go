@@ -0,0 +1,249 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// AllRepos is the pseudo repo that shows every saved repo on one board.
+const AllRepos = "All repos"
+
+// repoColors are handed out to repos without a color of their own.
+var repoColors = []string{"#60a5fa", "#f472b6", "#34d399", "#fbbf24", "#a78bfa", "#f87171", "#22d3ee", "#a3e635"}
+
+// BoardTask is a task as shown on the board. On the aggregated board the ID
+// is qualified with the repo name (web:US-004) and the status is the board
+// column it maps to.
+type BoardTask struct {
+	task.Task
+	Repo  string `json:"repo,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+// repoBoard is one repo's store while the aggregated board is shown.
+type repoBoard struct {
+	entry RepoEntry
+	color string
+	store store.Store
+}
+
+// openAll opens every repo concurrently. Repos that fail to open are
+// reported in errs rather than failing the whole board.
+func openAll(entries []RepoEntry) (repos []*repoBoard, errs []string) {
+	opened := make([]*repoBoard, len(entries))
+	failed := make([]error, len(entries))
+
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if entry.Problem != "" {
+				failed[i] = errors.New(entry.Problem)
+				return
+			}
+			s, err := openStoreFromEntry(entry)
+			if err != nil {
+				failed[i] = err
+				return
+			}
+			opened[i] = &repoBoard{entry: entry, color: repoColor(entry), store: s}
+		}()
+	}
+	wg.Wait()
+
+	for i, r := range opened {
+		if r != nil {
+			repos = append(repos, r)
+		} else {
+			errs = append(errs, fmt.Sprintf("%s: %v", entries[i].Name, failed[i]))
+		}
+	}
+	return repos, errs
+}
+
+// repoColor is the entry's own color or, failing that, one picked from the
+// palette by name so it stays the same as repos are reordered.
+func repoColor(entry RepoEntry) string {
+	if entry.Color != "" {
+		return entry.Color
+	}
+	h := fnv.New32a()
+	h.Write([]byte(entry.Name))
+	return repoColors[h.Sum32()%uint32(len(repoColors))]
+}
+
+func qualify(repo, id string) string {
+	return repo + ":" + id
+}
+
+// splitQualified splits web:US-004 into its repo and task ID. Repo names
+// may contain colons; task IDs never do.
+func splitQualified(id string) (repo, local string, ok bool) {
+	i := strings.LastIndex(id, ":")
+	if i < 0 {
+		return "", id, false
+	}
+	return id[:i], id[i+1:], true
+}
+
+// boardConfig is the config the aggregated board presents: the first repo's
+// workflow and priorities, which every other repo is mapped onto.
+func boardConfig(repos []*repoBoard) *config.Config {
+	cfg := *repos[0].store.GetConfig()
+	cfg.Project.Name = AllRepos
+	return &cfg
+}
+
+// toColumn maps a repo's status onto a board column: through the repo's
+// explicit status map, then by name, then by relative position in the
+// workflow so that first maps to first and last to last.
+func toColumn(r *repoBoard, status string, columns []string) string {
+	if col, ok := r.entry.StatusMap[status]; ok && slices.Contains(columns, col) {
+		return col
+	}
+	if slices.Contains(columns, status) {
+		return status
+	}
+	statuses := r.store.GetConfig().Statuses
+	i := slices.Index(statuses, status)
+	if i < 0 || len(columns) == 0 {
+		return status
+	}
+	return columns[scaleIndex(i, len(statuses), len(columns))]
+}
+
+// fromColumn is the inverse of toColumn: the repo status a task dropped in
+// column should get.
+func fromColumn(r *repoBoard, column string, columns []string) string {
+	statuses := r.store.GetConfig().Statuses
+	for _, status := range statuses {
+		if r.entry.StatusMap[status] == column {
+			return status
+		}
+	}
+	if slices.Contains(statuses, column) {
+		return column
+	}
+	i := slices.Index(columns, column)
+	if i < 0 || len(statuses) == 0 {
+		return column
+	}
+	return statuses[scaleIndex(i, len(columns), len(statuses))]
+}
+
+// scaleIndex maps position i in a list of length from onto a list of length
+// to, keeping the ends aligned.
+func scaleIndex(i, from, to int) int {
+	if from <= 1 || to <= 1 {
+		return 0
+	}
+	return (i*(to-1)*2 + (from - 1)) / ((from - 1) * 2)
+}
+
+// aggregateBoard lists every repo concurrently and merges the results into
+// the first repo's columns. A column counts every repo's tasks in it; its
+// limit is the sum of the repos' limits for the statuses it shows, and it
+// is over its limit when any repo is over its own.
+func aggregateBoard(repos []*repoBoard, filter store.Filter) (*BoardData, error) {
+	cfg := boardConfig(repos)
+
+	lists := make([][]task.Task, len(repos))
+	alls := make([][]task.Task, len(repos))
+	errs := make([]error, len(repos))
+	var wg sync.WaitGroup
+	for i, r := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lists[i], errs[i] = r.store.List(filter)
+			// WIP limits count every task in a column, not just the ones shown
+			alls[i] = lists[i]
+			if errs[i] == nil && filter != (store.Filter{}) {
+				alls[i], errs[i] = r.store.List(store.Filter{})
+			}
+		}()
+	}
+	wg.Wait()
+
+	board := &BoardData{Config: cfg, RepoName: AllRepos}
+	grouped := make(map[string][]BoardTask)
+	counts := make(map[string]int)
+	limits := make(map[string]int)
+	over := make(map[string]bool)
+	for i, r := range repos {
+		if errs[i] != nil {
+			board.Errors = append(board.Errors, fmt.Sprintf("%s: %v", r.entry.Name, errs[i]))
+			continue
+		}
+		for _, t := range lists[i] {
+			bt := presentTask(r, t, cfg.Statuses)
+			grouped[bt.Status] = append(grouped[bt.Status], bt)
+		}
+
+		repoCounts := make(map[string]int)
+		for _, t := range alls[i] {
+			repoCounts[t.Status]++
+			counts[toColumn(r, t.Status, cfg.Statuses)]++
+		}
+		for status, limit := range r.store.GetConfig().WIPLimits.Statuses {
+			if limit <= 0 {
+				continue
+			}
+			column := toColumn(r, status, cfg.Statuses)
+			limits[column] += limit
+			if repoCounts[status] > limit {
+				over[column] = true
+			}
+		}
+
+		if reporter, ok := r.store.(store.ProblemReporter); ok {
+			for _, p := range reporter.Problems() {
+				board.Skipped = append(board.Skipped, qualify(r.entry.Name, p.Name))
+			}
+		}
+	}
+
+	for _, status := range cfg.Statuses {
+		g := grouped[status]
+		sortBoardTasks(g, cfg)
+		board.Columns = append(board.Columns, ColumnData{
+			Status:    status,
+			Tasks:     g,
+			Count:     counts[status],
+			Limit:     limits[status],
+			OverLimit: over[status],
+		})
+	}
+	return board, nil
+}
+
+// sortBoardTasks orders a column the way store.SortTasks orders tasks.
+func sortBoardTasks(tasks []BoardTask, cfg *config.Config) {
+	plain := make([]task.Task, len(tasks))
+	byID := make(map[string]BoardTask, len(tasks))
+	for i, bt := range tasks {
+		plain[i] = bt.Task
+		byID[bt.ID] = bt
+	}
+	store.SortTasks(plain, cfg)
+	for i, t := range plain {
+		tasks[i] = byID[t.ID]
+	}
+}
+
+// presentTask qualifies a repo's task for the aggregated board.
+func presentTask(r *repoBoard, t task.Task, columns []string) BoardTask {
+	t.Version = task.Version(&t)
+	t.ID = qualify(r.entry.Name, t.ID)
+	t.Status = toColumn(r, t.Status, columns)
+	return BoardTask{Task: t, Repo: r.entry.Name, Color: r.color}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+func TestAggregateBoardLimits(t *testing.T) {
+	// api allows one task in progress and has two; web allows three and
+	// has one.
+	var repos []*repoBoard
+	for _, r := range []struct {
+		name       string
+		limit      int
+		inProgress int
+	}{{"api", 1, 2}, {"web", 3, 1}} {
+		s := &store.FilesystemStore{Dir: filepath.Join(t.TempDir(), ".skeeter")}
+		if err := s.Init(r.name); err != nil {
+			t.Fatalf("Init: %v", err)
+		}
+		for i := 1; i <= r.inProgress; i++ {
+			id := fmt.Sprintf("US-%03d", i)
+			if err := s.Create(&task.Task{ID: id, Title: "Task", Status: "backlog", Priority: "high"}); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			tk, _ := s.Get(id)
+			if err := store.ChangeStatus(s, tk, "in-progress", true); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Update(tk); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+		}
+		s.Config.WIPLimits.Statuses = map[string]int{"in-progress": r.limit}
+		repos = append(repos, &repoBoard{entry: RepoEntry{Name: r.name}, store: s})
+	}
+
+	// The counts cover tasks the filter hides.
+	board, err := aggregateBoard(repos, store.Filter{Priority: "low"})
+	if err != nil {
+		t.Fatalf("aggregateBoard: %v", err)
+	}
+	for _, col := range board.Columns {
+		if col.Status != "in-progress" {
+			continue
+		}
+		if len(col.Tasks) != 0 || col.Count != 3 || col.Limit != 4 || !col.OverLimit {
+			t.Errorf("in-progress column = %d shown, %d/%d, over %v; want 0 shown, 3/4 and over",
+				len(col.Tasks), col.Count, col.Limit, col.OverLimit)
+		}
+		return
+	}
+	t.Error("no in-progress column")
+}
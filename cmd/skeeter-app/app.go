@@ -3,14 +3,20 @@ package main
 import (
 	"context"
 	"fmt"
-	"sort"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/andybarilla/skeeter/internal/agent"
 	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/gitinfo"
 	"github.com/andybarilla/skeeter/internal/llm"
 	"github.com/andybarilla/skeeter/internal/store"
 	"github.com/andybarilla/skeeter/internal/task"
+	"github.com/andybarilla/skeeter/internal/watch"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
@@ -18,11 +24,19 @@ type BoardData struct {
 	Columns  []ColumnData   `json:"columns"`
 	Config   *config.Config `json:"config"`
 	RepoName string         `json:"repoName"`
+	Skipped  []string       `json:"skipped"`
+	// Errors lists repos the aggregated board could not load.
+	Errors []string `json:"errors"`
 }
 
 type ColumnData struct {
 	Status string      `json:"status"`
-	Tasks  []task.Task `json:"tasks"`
+	Tasks  []BoardTask `json:"tasks"`
+	// Count is the number of tasks in the column before filtering, which is
+	// what Limit, the column's WIP limit if it has one, applies to.
+	Count     int  `json:"count"`
+	Limit     int  `json:"limit,omitempty"`
+	OverLimit bool `json:"overLimit"`
 }
 
 type BoardFilter struct {
@@ -32,33 +46,102 @@ type BoardFilter struct {
 }
 
 type CreateTaskInput struct {
-	Title    string   `json:"title"`
-	Priority string   `json:"priority"`
-	Assignee string   `json:"assignee"`
-	Tags     []string `json:"tags"`
-	Body     string   `json:"body"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Priority  string   `json:"priority"`
+	Assignee  string   `json:"assignee"`
+	Tags      []string `json:"tags"`
+	Links     []string `json:"links"`
+	DependsOn []string `json:"depends_on"`
+	Due       string   `json:"due"`
+	Body      string   `json:"body"`
+	// Repo picks the repo to create in on the aggregated board; empty
+	// means the first one.
+	Repo string `json:"repo"`
+	// Force overrides the workflow rules for starting past the first
+	// status.
+	Force bool `json:"force"`
 }
 
 type UpdateTaskInput struct {
-	ID       string   `json:"id"`
-	Title    string   `json:"title"`
-	Status   string   `json:"status"`
-	Priority string   `json:"priority"`
-	Assignee string   `json:"assignee"`
-	Tags     []string `json:"tags"`
-	Body     string   `json:"body"`
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Priority  string   `json:"priority"`
+	Assignee  string   `json:"assignee"`
+	Tags      []string `json:"tags"`
+	Links     []string `json:"links"`
+	DependsOn []string `json:"depends_on"`
+	Due       string   `json:"due"`
+	Body      string   `json:"body"`
+	// Version is the version of the task the edit started from. A stale
+	// version is rejected with a ConflictError; empty skips the check.
+	Version string `json:"version"`
+	// Base is the task as the edit started from, as the frontend was shown
+	// it. With it a stale edit is merged field by field, and only fields
+	// changed on both sides conflict.
+	Base *task.Task `json:"base,omitempty"`
+	// Force overrides the workflow rules for a status change and the
+	// per-assignee WIP limit for a new assignee.
+	Force bool `json:"force"`
 }
 
+// TaskDetail is a task along with where it stands among its dependencies
+// and in git.
+type TaskDetail struct {
+	Task *task.Task `json:"task"`
+	// BlockedBy lists dependencies that are not done yet.
+	BlockedBy []string `json:"blockedBy"`
+	// Blocking lists tasks that depend on this one.
+	Blocking []string `json:"blocking"`
+	Blocked  bool     `json:"blocked"`
+	// Git is what the local repository knows about the task; nil when the
+	// board isn't in a git work tree.
+	Git *gitinfo.Status `json:"git,omitempty"`
+}
+
+// eventsEmit sends events to the frontend. Tests replace it, since the Wails
+// runtime needs a running app.
+var eventsEmit = runtime.EventsEmit
+
 type App struct {
 	ctx       context.Context
 	mu        sync.RWMutex
 	store     store.Store
 	repoStore *RepoStore
 	repoName  string
+
+	// repos holds every repo's store while the aggregated board is shown,
+	// in which case store is nil. repoErrors lists repos that failed to open.
+	repos      []*repoBoard
+	repoErrors []string
+
+	// stopWatch cancels the watcher for the active store.
+	stopWatch context.CancelFunc
+
+	// runner is the work loop started from the app, if any. It keeps
+	// running when the active repo changes.
+	runner *agent.Runner
+
+	// activity follows the watched repos for notifications and the counts
+	// in the window title.
+	activity *activity
+	// quitting is set by Quit so closing isn't turned into minimising in
+	// background mode.
+	quitting atomic.Bool
+
+	// startupErr is why the saved repo list couldn't be read at startup.
+	// openProblems records saved repos that failed to open, by name.
+	startupErr   error
+	openProblems map[string]string
 }
 
+// remotePollInterval is how often remote stores are checked for changes
+// made elsewhere.
+const remotePollInterval = 30 * time.Second
+
 func NewApp() *App {
-	return &App{}
+	return &App{activity: newActivity(), openProblems: make(map[string]string)}
 }
 
 func (a *App) startup(ctx context.Context) {
@@ -66,28 +149,191 @@ func (a *App) startup(ctx context.Context) {
 
 	rs, err := NewRepoStore()
 	if err != nil {
+		a.startupErr = err
 		return
 	}
 	a.repoStore = rs
 
-	// Auto-open the first (MRU) repo
+	// Auto-open the most recently used repo that opens, flagging the ones
+	// that don't so the sidebar can show why.
 	repos, err := rs.Load()
-	if err != nil || len(repos) == 0 {
+	if err != nil {
+		a.startupErr = err
 		return
 	}
-	s, err := openStoreFromEntry(repos[0])
-	if err != nil {
+	for _, entry := range repos {
+		if entry.Problem != "" {
+			continue
+		}
+		s, err := openStoreFromEntry(entry)
+		if err != nil {
+			a.openFailed(entry.Name, err)
+			continue
+		}
+		a.setStore(s, entry.Name)
 		return
 	}
+}
+
+// openFailed records that the saved repo name could not be opened.
+func (a *App) openFailed(name string, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err == nil {
+		delete(a.openProblems, name)
+		return
+	}
+	a.openProblems[name] = err.Error()
+}
+
+// setStore makes s the active store and starts watching it for changes made
+// outside the app, such as an agent running skeeter work in a terminal.
+func (a *App) setStore(s store.Store, name string) {
 	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.stopWatch != nil {
+		a.stopWatch()
+		a.stopWatch = nil
+	}
 	a.store = s
-	a.repoName = repos[0].Name
-	a.mu.Unlock()
+	a.repoName = name
+	a.repos = nil
+	a.repoErrors = nil
+	a.activity.reset()
+	if s == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.stopWatch = cancel
+	a.watchStore(ctx, name, s, func(ids []string) {
+		eventsEmit(ctx, "tasks:changed", ids)
+	})
+}
+
+// setAggregate shows every repo on one board, watching each of them and
+// reporting changes with repo-qualified IDs.
+func (a *App) setAggregate(repos []*repoBoard, errs []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.stopWatch != nil {
+		a.stopWatch()
+	}
+	if repos == nil {
+		// Keep the board aggregated even when no repo could be opened.
+		repos = []*repoBoard{}
+	}
+	a.store = nil
+	a.repoName = AllRepos
+	a.repos = repos
+	a.repoErrors = errs
+	a.activity.reset()
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.stopWatch = cancel
+	for _, r := range repos {
+		a.watchStore(ctx, r.entry.Name, r.store, func(ids []string) {
+			qualified := make([]string, len(ids))
+			for i, id := range ids {
+				qualified[i] = qualify(r.entry.Name, id)
+			}
+			eventsEmit(ctx, "tasks:changed", qualified)
+		})
+	}
+}
+
+// watchStore notifies about task changes in s until ctx is cancelled,
+// observing repo's activity after each batch.
+func (a *App) watchStore(ctx context.Context, repo string, s store.Store, notify func(ids []string)) {
+	go a.watchActivity(ctx, repo, s)
+	changed := notify
+	notify = func(ids []string) {
+		changed(ids)
+		a.observe(ctx, repo, s)
+	}
+	if fs, ok := s.(*store.FilesystemStore); ok {
+		go watch.Dir(ctx, filepath.Join(fs.Dir, "tasks"), notify)
+		return
+	}
+	go watch.Poll(ctx, remotePollInterval, func() (watch.Snapshot, error) {
+		return a.snapshot(s)
+	}, notify)
+}
+
+// resolveTask finds the store that owns id. On the aggregated board id is
+// qualified with the repo name and the owning repo is returned too;
+// otherwise repo is nil and id is returned as is.
+func (a *App) resolveTask(id string) (s store.Store, localID string, repo *repoBoard, err error) {
+	if a.repos == nil {
+		if a.store == nil {
+			return nil, "", nil, fmt.Errorf("no repo selected")
+		}
+		return a.store, id, nil, nil
+	}
+	name, localID, ok := splitQualified(id)
+	if !ok {
+		return nil, "", nil, fmt.Errorf("task %q is not qualified with a repo", id)
+	}
+	repo = a.findRepo(name)
+	if repo == nil {
+		return nil, "", nil, fmt.Errorf("repo %q not found", name)
+	}
+	return repo.store, localID, repo, nil
+}
+
+// findRepo returns the aggregated repo called name, or nil.
+func (a *App) findRepo(name string) *repoBoard {
+	for _, r := range a.repos {
+		if r.entry.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// defaultStore is the active store, or the first repo's on the aggregated
+// board. It serves requests that aren't about any one task.
+func (a *App) defaultStore() store.Store {
+	if len(a.repos) > 0 {
+		return a.repos[0].store
+	}
+	return a.store
+}
+
+// present returns t as the frontend should see it: versioned, and qualified
+// and mapped onto the board's columns when it belongs to an aggregated repo.
+func (a *App) present(repo *repoBoard, t *task.Task) *task.Task {
+	if repo == nil {
+		t.Version = task.Version(t)
+		return t
+	}
+	bt := presentTask(repo, *t, a.repos[0].store.GetConfig().Statuses)
+	return &bt.Task
+}
+
+// repoStatus translates a board column into repo's own status.
+func (a *App) repoStatus(repo *repoBoard, status string) string {
+	if repo == nil {
+		return status
+	}
+	return fromColumn(repo, status, a.repos[0].store.GetConfig().Statuses)
+}
+
+// snapshot fingerprints every task in a remote store. It runs without the
+// app lock: the stores guard their own state, and the request can be slow.
+func (a *App) snapshot(s store.Store) (watch.Snapshot, error) {
+	prints, err := store.Fingerprint(s)
+	if err != nil {
+		return nil, err
+	}
+	return watch.Snapshot(prints), nil
 }
 
 func openStoreFromEntry(entry RepoEntry) (store.Store, error) {
 	if entry.Remote != "" {
-		return store.NewGitHub(entry.Remote, entry.Dir)
+		return store.NewRemote(entry.Remote, entry.Dir)
 	}
 	return store.NewFilesystem(entry.Path)
 }
@@ -97,56 +343,85 @@ func (a *App) GetBoard(filter BoardFilter) (*BoardData, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
-	if a.store == nil {
-		return &BoardData{}, nil
-	}
-
-	cfg := a.store.GetConfig()
-
 	// List all tasks (no status filter — we need all columns)
 	f := store.Filter{
 		Priority: filter.Priority,
 		Assignee: filter.Assignee,
 		Tag:      filter.Tag,
 	}
+
+	if a.repos != nil {
+		if len(a.repos) == 0 {
+			return &BoardData{RepoName: AllRepos, Errors: a.repoErrors}, nil
+		}
+		board, err := aggregateBoard(a.repos, f)
+		if err != nil {
+			return nil, err
+		}
+		board.Errors = append(append([]string(nil), a.repoErrors...), board.Errors...)
+		return board, nil
+	}
+
+	if a.store == nil {
+		return &BoardData{}, nil
+	}
+
+	cfg := a.store.GetConfig()
 	tasks, err := a.store.List(f)
 	if err != nil {
 		return nil, err
 	}
 
 	// Group by status
-	grouped := make(map[string][]task.Task)
+	grouped := make(map[string][]BoardTask)
 	for _, t := range tasks {
-		grouped[t.Status] = append(grouped[t.Status], t)
+		t.Version = task.Version(&t)
+		grouped[t.Status] = append(grouped[t.Status], BoardTask{Task: t})
 	}
 
-	// Sort each group by priority rank then ID
+	// Sort each group by priority, manual rank, then ID
 	for status := range grouped {
-		g := grouped[status]
-		sort.Slice(g, func(i, j int) bool {
-			ri := cfg.PriorityRank(g[i].Priority)
-			rj := cfg.PriorityRank(g[j].Priority)
-			if ri != rj {
-				return ri < rj
-			}
-			return g[i].ID < g[j].ID
-		})
-		grouped[status] = g
+		sortBoardTasks(grouped[status], cfg)
+	}
+
+	// WIP limits count every task in a column, not just the ones shown
+	all := tasks
+	if f != (store.Filter{}) {
+		if all, err = a.store.List(store.Filter{}); err != nil {
+			return nil, err
+		}
+	}
+	counts := make(map[string]int)
+	for _, t := range all {
+		counts[t.Status]++
 	}
 
 	// Build columns in configured order
 	var columns []ColumnData
 	for _, status := range cfg.Statuses {
+		limit := cfg.WIPLimits.Statuses[status]
 		columns = append(columns, ColumnData{
-			Status: status,
-			Tasks:  grouped[status],
+			Status:    status,
+			Tasks:     grouped[status],
+			Count:     counts[status],
+			Limit:     limit,
+			OverLimit: limit > 0 && counts[status] > limit,
 		})
 	}
 
+	// Surface malformed files instead of letting them vanish from the board
+	var skipped []string
+	if reporter, ok := a.store.(store.ProblemReporter); ok {
+		for _, p := range reporter.Problems() {
+			skipped = append(skipped, p.Name)
+		}
+	}
+
 	return &BoardData{
 		Columns:  columns,
 		Config:   cfg,
 		RepoName: a.repoName,
+		Skipped:  skipped,
 	}, nil
 }
 
@@ -155,10 +430,63 @@ func (a *App) GetTask(id string) (*task.Task, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
-	if a.store == nil {
-		return nil, fmt.Errorf("no repo selected")
+	s, localID, repo, err := a.resolveTask(id)
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.Get(localID)
+	if err != nil {
+		return nil, err
+	}
+	return a.present(repo, t), nil
+}
+
+// GetTaskDetail returns a task with its dependency and git status.
+// Dependency IDs are the owning repo's own, as in depends_on.
+func (a *App) GetTaskDetail(id string) (*TaskDetail, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	s, localID, repo, err := a.resolveTask(id)
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.Get(localID)
+	if err != nil {
+		return nil, err
 	}
-	return a.store.Get(id)
+	deps, _, err := store.GetLinks(s, t)
+	if err != nil {
+		return nil, err
+	}
+	detail := &TaskDetail{
+		Task:      a.present(repo, t),
+		BlockedBy: deps.BlockedBy,
+		Blocking:  deps.Blocking,
+		Blocked:   !deps.AllDependenciesMet,
+	}
+	// Git state is extra; a board whose repository can't be read still
+	// shows the task.
+	if fs, ok := s.(*store.FilesystemStore); ok {
+		cfg := s.GetConfig()
+		detail.Git, _ = gitinfo.Inspect(filepath.Dir(fs.Dir), t, cfg.Project.Prefix, cfg.Branches.Main, 5)
+	}
+	return detail, nil
+}
+
+// localIDs normalizes dependency IDs entered in the app. On the aggregated
+// board they may carry the owning repo's prefix, which is dropped.
+func localIDs(repo *repoBoard, ids []string) task.FlowSlice {
+	cleaned := cleanList(ids, true)
+	if repo == nil {
+		return cleaned
+	}
+	for i, id := range cleaned {
+		if name, localID, ok := splitQualified(id); ok && strings.EqualFold(name, repo.entry.Name) {
+			cleaned[i] = localID
+		}
+	}
+	return cleaned
 }
 
 // CreateTask creates a new task.
@@ -166,38 +494,79 @@ func (a *App) CreateTask(input CreateTaskInput) (*task.Task, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if a.store == nil {
-		return nil, fmt.Errorf("no repo selected")
+	s, repo := a.store, (*repoBoard)(nil)
+	if a.repos != nil {
+		if len(a.repos) == 0 {
+			return nil, fmt.Errorf("no repo selected")
+		}
+		repo = a.repos[0]
+		if input.Repo != "" {
+			if repo = a.findRepo(input.Repo); repo == nil {
+				return nil, fmt.Errorf("repo %q not found", input.Repo)
+			}
+		}
+		s = repo.store
 	}
-
-	cfg := a.store.GetConfig()
-	id, err := a.store.NextID()
-	if err != nil {
-		return nil, err
+	if s == nil {
+		return nil, fmt.Errorf("no repo selected")
 	}
 
+	cfg := s.GetConfig()
 	now := time.Now().Format("2006-01-02")
 	priority := input.Priority
 	if priority == "" {
 		priority = cfg.Priorities[len(cfg.Priorities)-1] // lowest
 	}
-
 	t := &task.Task{
-		ID:       id,
-		Title:    input.Title,
-		Status:   cfg.Statuses[0], // first status (backlog)
-		Priority: priority,
-		Assignee: input.Assignee,
-		Tags:     input.Tags,
-		Created:  now,
-		Updated:  now,
-		Body:     input.Body,
-	}
-
-	if err := a.store.Create(t); err != nil {
+		Title:     input.Title,
+		Status:    cfg.Statuses[0], // first status (backlog)
+		Priority:  priority,
+		Assignee:  input.Assignee,
+		Tags:      cleanList(input.Tags, false),
+		Links:     cleanList(input.Links, false),
+		DependsOn: localIDs(repo, input.DependsOn),
+		Due:       input.Due,
+		Created:   now,
+		Updated:   now,
+		Body:      input.Body,
+	}
+
+	// Starting past the first status is a move from it, so it follows the
+	// workflow like any other. Validate before taking an ID so a rejected
+	// task doesn't use one up.
+	if input.Status != "" {
+		status := a.repoStatus(repo, input.Status)
+		if s.GetConfig().ValidStatus(status) {
+			if err := store.ChangeStatus(s, t, status, input.Force); err != nil {
+				return nil, err
+			}
+		} else {
+			t.Status = status
+		}
+	}
+	if err := validateTask(s, t); err != nil {
 		return nil, err
 	}
-	return t, nil
+	id, err := s.NextID()
+	if err != nil {
+		return nil, err
+	}
+	t.ID = id
+
+	if err := s.Create(t); err != nil {
+		return nil, err
+	}
+	return a.saved(s, t.ID, repo)
+}
+
+// saved re-reads a task after writing it, so the version handed back is the
+// one the next read will see.
+func (a *App) saved(s store.Store, id string, repo *repoBoard) (*task.Task, error) {
+	t, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return a.present(repo, t), nil
 }
 
 // UpdateTask performs a full task update.
@@ -205,83 +574,168 @@ func (a *App) UpdateTask(input UpdateTaskInput) (*task.Task, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if a.store == nil {
-		return nil, fmt.Errorf("no repo selected")
+	s, localID, repo, err := a.resolveTask(input.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	t, err := a.store.Get(input.ID)
+	t, err := s.Get(localID)
 	if err != nil {
 		return nil, err
 	}
+	if input.Version != "" && input.Version != task.Version(t) {
+		current := *t
+		if conflict := updateConflict(repo, a.present(repo, &current), &input); conflict != nil {
+			return nil, conflict
+		}
+	}
 
 	t.Title = input.Title
-	t.Status = input.Status
 	t.Priority = input.Priority
-	t.Assignee = input.Assignee
-	t.Tags = input.Tags
+	if err := store.ChangeAssignee(s, t, input.Assignee, input.Force); err != nil {
+		return nil, err
+	}
+	t.Tags = cleanList(input.Tags, false)
+	t.Links = cleanList(input.Links, false)
+	t.DependsOn = localIDs(repo, input.DependsOn)
+	t.Due = input.Due
 	t.Body = input.Body
 	t.Updated = time.Now().Format("2006-01-02")
 
-	if err := a.store.Update(t); err != nil {
+	// The workflow applies to valid statuses; validateTask reports the rest
+	// next to the field.
+	status := a.repoStatus(repo, input.Status)
+	if s.GetConfig().ValidStatus(status) {
+		if err := store.ChangeStatus(s, t, status, input.Force); err != nil {
+			return nil, err
+		}
+	} else {
+		t.Status = status
+	}
+
+	if err := validateTask(s, t); err != nil {
+		return nil, err
+	}
+	if err := s.Update(t); err != nil {
 		return nil, err
 	}
-	return t, nil
+	return a.saved(s, localID, repo)
 }
 
-// MoveTask changes only the status of a task (for drag-and-drop).
-func (a *App) MoveTask(id, status string) (*task.Task, error) {
+// MoveTask changes only the status of a task (for drag-and-drop). A
+// non-empty version must match the task's current one. The move must follow
+// the repo's workflow unless force is set.
+func (a *App) MoveTask(id, status, version string, force bool) (*task.Task, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if a.store == nil {
-		return nil, fmt.Errorf("no repo selected")
-	}
-
-	cfg := a.store.GetConfig()
-	if !cfg.ValidStatus(status) {
-		return nil, fmt.Errorf("invalid status %q", status)
+	s, localID, repo, err := a.resolveTask(id)
+	if err != nil {
+		return nil, err
 	}
 
-	t, err := a.store.Get(id)
+	// On the aggregated board status is a board column; the owning repo
+	// may call it something else.
+	column := status
+	status = a.repoStatus(repo, status)
+	t, err := s.Get(localID)
 	if err != nil {
 		return nil, err
 	}
+	if version != "" && version != task.Version(t) {
+		return nil, moveConflict(a.present(repo, t), column)
+	}
 
-	t.Status = status
+	if err := store.ChangeStatus(s, t, status, force); err != nil {
+		return nil, err
+	}
 	t.Updated = time.Now().Format("2006-01-02")
 
-	if err := a.store.Update(t); err != nil {
+	if err := s.Update(t); err != nil {
 		return nil, err
 	}
-	return t, nil
+	return a.saved(s, localID, repo)
 }
 
-// AssignTask changes only the assignee of a task.
+// AssignTask changes only the assignee of a task, within the per-assignee
+// WIP limit.
 func (a *App) AssignTask(id, assignee string) (*task.Task, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if a.store == nil {
-		return nil, fmt.Errorf("no repo selected")
+	s, localID, repo, err := a.resolveTask(id)
+	if err != nil {
+		return nil, err
 	}
 
-	t, err := a.store.Get(id)
+	t, err := s.Get(localID)
 	if err != nil {
 		return nil, err
 	}
 
-	t.Assignee = assignee
+	if err := store.ChangeAssignee(s, t, assignee, false); err != nil {
+		return nil, err
+	}
 	t.Updated = time.Now().Format("2006-01-02")
 
-	if err := a.store.Update(t); err != nil {
+	if err := s.Update(t); err != nil {
 		return nil, err
 	}
-	return t, nil
+	return a.saved(s, localID, repo)
 }
 
-// GetRepos returns the saved repo list.
+// ReorderTask moves a task just before beforeID or just after afterID within
+// its column and priority (for drag-and-drop). Either neighbor may be empty.
+func (a *App) ReorderTask(id, beforeID, afterID string) (*task.Task, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, localID, repo, err := a.resolveTask(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// On the aggregated board only neighbors from the same repo share a
+	// rank order.
+	local := func(neighbor string) string {
+		if neighbor == "" || repo == nil {
+			return neighbor
+		}
+		name, neighborID, ok := splitQualified(neighbor)
+		if !ok || name != repo.entry.Name {
+			return ""
+		}
+		return neighborID
+	}
+	beforeID, afterID = local(beforeID), local(afterID)
+	if beforeID == "" && afterID == "" {
+		return nil, fmt.Errorf("tasks can only be ordered among tasks of the same repo")
+	}
+
+	if _, err := store.Reorder(s, localID, beforeID, afterID); err != nil {
+		return nil, err
+	}
+	return a.saved(s, localID, repo)
+}
+
+// GetRepos returns the saved repo list, with Problem set on repos that are
+// invalid or failed to open.
 func (a *App) GetRepos() ([]RepoEntry, error) {
-	return a.repoStore.Load()
+	if a.repoStore == nil {
+		return nil, fmt.Errorf("cannot read the saved repos: %w", a.startupErr)
+	}
+	repos, err := a.repoStore.Load()
+	if err != nil {
+		return nil, err
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for i := range repos {
+		if repos[i].Problem == "" {
+			repos[i].Problem = a.openProblems[repos[i].Name]
+		}
+	}
+	return repos, nil
 }
 
 // AddRepo validates and saves a new repo.
@@ -301,6 +755,10 @@ func (a *App) AddRepo(entry RepoEntry) error {
 		}
 	}
 
+	if entry.Name == AllRepos {
+		return fmt.Errorf("%q is reserved for the combined board", AllRepos)
+	}
+
 	if err := a.repoStore.Add(entry); err != nil {
 		return err
 	}
@@ -308,37 +766,82 @@ func (a *App) AddRepo(entry RepoEntry) error {
 	// If no active store, switch to the new one
 	a.mu.RLock()
 	hasStore := a.store != nil
+	aggregated := a.repos != nil
 	a.mu.RUnlock()
 
+	if aggregated {
+		_, err := a.SwitchRepo(AllRepos)
+		return err
+	}
 	if !hasStore {
-		a.mu.Lock()
-		a.store = s
-		a.repoName = entry.Name
-		a.mu.Unlock()
-		runtime.WindowSetTitle(a.ctx, "Skeeter — "+entry.Name)
+		a.setStore(s, entry.Name)
+		a.updateTitle()
 	}
 
 	return nil
 }
 
+// InitRepo initializes skeeter in a local directory or remote repository,
+// then saves it like AddRepo.
+func (a *App) InitRepo(entry RepoEntry, projectName string) error {
+	if projectName == "" {
+		projectName = entry.Name
+	}
+
+	var s store.Store
+	if entry.Remote != "" {
+		rs, err := store.NewRemoteForInit(entry.Remote, entry.Dir)
+		if err != nil {
+			return fmt.Errorf("cannot open repo: %w", err)
+		}
+		s = rs
+	} else {
+		if _, err := os.Stat(filepath.Join(entry.Path, "config.yaml")); err == nil {
+			return fmt.Errorf("skeeter already initialized at %s", entry.Path)
+		}
+		s = &store.FilesystemStore{Dir: entry.Path}
+	}
+
+	if err := s.Init(projectName); err != nil {
+		return err
+	}
+	return a.AddRepo(entry)
+}
+
 // RemoveRepo removes a saved repo.
 func (a *App) RemoveRepo(name string) error {
 	// If removing the active repo, clear the store
-	a.mu.Lock()
-	if a.repoName == name {
-		a.store = nil
-		a.repoName = ""
+	a.mu.RLock()
+	active := a.repoName == name
+	aggregated := a.repos != nil
+	a.mu.RUnlock()
+	if active {
+		a.setStore(nil, "")
 	}
-	a.mu.Unlock()
 
-	return a.repoStore.Remove(name)
+	if err := a.repoStore.Remove(name); err != nil {
+		return err
+	}
+	a.openFailed(name, nil)
+	if aggregated {
+		_, err := a.SwitchRepo(AllRepos)
+		return err
+	}
+	return nil
 }
 
-// SwitchRepo switches the active store.
-func (a *App) SwitchRepo(name string) error {
+// SwitchRepo switches the active store and returns the board state last
+// left on it, if any.
+func (a *App) SwitchRepo(name string) (*RepoUIState, error) {
 	repos, err := a.repoStore.Load()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if name == AllRepos {
+		a.setAggregate(openAll(repos))
+		a.updateTitle()
+		return a.repoStore.UIState(AllRepos)
 	}
 
 	var entry *RepoEntry
@@ -350,34 +853,36 @@ func (a *App) SwitchRepo(name string) error {
 		}
 	}
 	if entry == nil {
-		return fmt.Errorf("repo %q not found", name)
+		return nil, fmt.Errorf("repo %q not found", name)
+	}
+	if entry.Problem != "" {
+		return nil, fmt.Errorf("cannot open repo: %s", entry.Problem)
 	}
 
 	s, err := openStoreFromEntry(*entry)
+	a.openFailed(name, err)
 	if err != nil {
-		return fmt.Errorf("cannot open repo: %w", err)
+		return nil, fmt.Errorf("cannot open repo: %w", err)
 	}
 
-	a.mu.Lock()
-	a.store = s
-	a.repoName = name
-	a.mu.Unlock()
+	a.setStore(s, name)
 
 	// Move to front (MRU)
 	_ = a.repoStore.MoveToFront(name)
 
-	runtime.WindowSetTitle(a.ctx, "Skeeter — "+name)
-	return nil
+	a.updateTitle()
+	return entry.UI, nil
 }
 
 // GetTemplate returns the contents of a named template (e.g. "default").
 func (a *App) GetTemplate(name string) (string, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
-	if a.store == nil {
+	s := a.defaultStore()
+	if s == nil {
 		return "", fmt.Errorf("no repo selected")
 	}
-	return a.store.LoadTemplate(name)
+	return s.LoadTemplate(name)
 }
 
 // BrowseDirectory opens a native directory picker and returns the selected path.
@@ -399,17 +904,18 @@ func (a *App) EnhanceTask(id string) (string, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if a.store == nil {
-		return "", fmt.Errorf("no repo selected")
+	s, localID, _, err := a.resolveTask(id)
+	if err != nil {
+		return "", err
 	}
 
-	t, err := a.store.Get(id)
+	t, err := s.Get(localID)
 	if err != nil {
 		return "", err
 	}
 
-	cfg := a.store.GetConfig()
-	template, _ := a.store.LoadTemplate("default")
+	cfg := s.GetConfig()
+	template, _ := s.LoadTemplate("default")
 
 	enhanced, err := llm.EnhanceTask(a.ctx, cfg, t, template)
 	if err != nil {
@@ -418,7 +924,7 @@ func (a *App) EnhanceTask(id string) (string, error) {
 
 	t.Body = enhanced
 	t.Updated = time.Now().Format("2006-01-02")
-	if err := a.store.Update(t); err != nil {
+	if err := s.Update(t); err != nil {
 		return "", fmt.Errorf("saving task: %w", err)
 	}
 
@@ -430,12 +936,13 @@ func (a *App) EnhanceDraft(title, body string) (string, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
-	if a.store == nil {
+	s := a.defaultStore()
+	if s == nil {
 		return "", fmt.Errorf("no repo selected")
 	}
 
-	cfg := a.store.GetConfig()
-	template, _ := a.store.LoadTemplate("default")
+	cfg := s.GetConfig()
+	template, _ := s.LoadTemplate("default")
 
 	enhanced, err := llm.EnhanceDraft(a.ctx, cfg, title, body, template)
 	if err != nil {
@@ -444,3 +951,62 @@ func (a *App) EnhanceDraft(title, body string) (string, error) {
 
 	return enhanced, nil
 }
+
+// StartWork launches the autonomous work loop on the active repo in the
+// background. Progress is emitted as "work:event" events.
+func (a *App) StartWork(opts agent.Options) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.runner != nil && a.runner.Status().Running {
+		return agent.ErrRunning
+	}
+	fs, ok := a.store.(*store.FilesystemStore)
+	if !ok {
+		return fmt.Errorf("the work loop needs a local repo to work in")
+	}
+	if opts.Assign == "" {
+		opts.Assign = "ralph"
+	}
+	opts.WorkDir = filepath.Dir(fs.Dir)
+	repo := a.repoName
+
+	// The runner writes from its own goroutine, so it gets a store of its
+	// own rather than sharing the UI's index and problem list.
+	runnerStore, err := store.NewFilesystem(fs.Dir)
+	if err != nil {
+		return err
+	}
+	runner := agent.NewRunner(runnerStore, fs.Dir)
+	if err := runner.Start(a.ctx, opts, func(e agent.Event) {
+		eventsEmit(a.ctx, "work:event", e)
+		a.notifyWork(repo, e)
+	}); err != nil {
+		return err
+	}
+	a.runner = runner
+	return nil
+}
+
+// StopWork stops the work loop, returning the task in progress to the ready
+// column.
+func (a *App) StopWork() {
+	a.mu.RLock()
+	runner := a.runner
+	a.mu.RUnlock()
+	if runner != nil {
+		runner.Stop()
+	}
+}
+
+// GetWorkStatus reports whether the work loop is running and what it is
+// working on.
+func (a *App) GetWorkStatus() agent.Status {
+	a.mu.RLock()
+	runner := a.runner
+	a.mu.RUnlock()
+	if runner == nil {
+		return agent.Status{}
+	}
+	return runner.Status()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/agent"
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// TestStartWorkWhileBoardLoads runs the work loop while the board is being
+// read, so that go test -race catches the two sharing store state.
+func TestStartWorkWhileBoardLoads(t *testing.T) {
+	eventsEmit = func(context.Context, string, ...interface{}) {}
+
+	dir := filepath.Join(t.TempDir(), ".skeeter")
+	s := &store.FilesystemStore{Dir: dir}
+	if err := s.Init("test"); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := s.UpdateConfig(func(cfg *config.Config) error {
+		cfg.LLM.Tool = "fake"
+		cfg.LLM.Tools = map[string]config.LLMToolDef{"fake": {Command: "cat", PrintFlag: "-"}}
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	for i := 1; i <= 5; i++ {
+		id := fmt.Sprintf("US-%03d", i)
+		if err := s.Create(&task.Task{ID: id, Title: "Task " + id, Status: "ready-for-development"}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	a := NewApp()
+	a.ctx = context.Background()
+	a.store = s
+	a.repoName = "test"
+
+	if err := a.StartWork(agent.Options{}); err != nil {
+		t.Fatalf("StartWork: %v", err)
+	}
+	deadline := time.Now().Add(30 * time.Second)
+	for a.GetWorkStatus().Running {
+		if time.Now().After(deadline) {
+			a.StopWork()
+			t.Fatal("work loop did not finish")
+		}
+		if _, err := a.GetBoard(BoardFilter{}); err != nil {
+			t.Fatalf("GetBoard: %v", err)
+		}
+	}
+
+	board, err := a.GetBoard(BoardFilter{})
+	if err != nil {
+		t.Fatalf("GetBoard: %v", err)
+	}
+	for _, col := range board.Columns {
+		if col.Status == "done" && col.Count != 5 {
+			t.Errorf("done column has %d tasks, want 5", col.Count)
+		}
+	}
+}
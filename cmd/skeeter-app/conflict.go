@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// FieldChange is one field where the submitted task differs from the
+// stored one.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Yours  string `json:"yours"`
+	Theirs string `json:"theirs"`
+}
+
+// ConflictError rejects a write based on a stale read: the task changed
+// since the version the frontend was editing. Current is the task as it is
+// now, so the frontend can merge or overwrite by resubmitting with its
+// version.
+type ConflictError struct {
+	ID      string        `json:"id"`
+	Current *task.Task    `json:"current"`
+	Changes []FieldChange `json:"changes"`
+}
+
+func (e *ConflictError) Error() string {
+	fields := make([]string, len(e.Changes))
+	for i, c := range e.Changes {
+		fields[i] = c.Field
+	}
+	msg := fmt.Sprintf("%s was changed by someone else while you were editing", e.ID)
+	if len(fields) > 0 {
+		msg += " (" + strings.Join(fields, ", ") + ")"
+	}
+	return msg
+}
+
+// updateConflict settles an edit made against an older version of current.
+// With the edit's base, the task as the edit started from, it is a
+// three-way merge: fields only the other side changed are taken into input,
+// fields only the edit changed keep the edit's value, and only fields both
+// changed differently conflict. Without a base every field the edit sets
+// differently conflicts. It returns nil when nothing conflicts.
+func updateConflict(repo *repoBoard, current *task.Task, input *UpdateTaskInput) *ConflictError {
+	base := input.Base
+	var changes []FieldChange
+	merge := func(field, yours, theirs string, baseValue func(*task.Task) string, take func()) {
+		switch {
+		case yours == theirs:
+		case base != nil && yours == baseValue(base):
+			take()
+		case base != nil && theirs == baseValue(base):
+		default:
+			changes = append(changes, FieldChange{Field: field, Yours: yours, Theirs: theirs})
+		}
+	}
+	list := func(items []string) string {
+		return strings.Join(cleanList(items, false), ", ")
+	}
+	// On the aggregated board dependencies may be qualified with the repo
+	// name or not; both mean the same task.
+	deps := func(items []string) string {
+		return strings.Join(localIDs(repo, items), ", ")
+	}
+	body := func(b string) string {
+		return strings.TrimRight(b, "\n")
+	}
+
+	merge("title", input.Title, current.Title, func(t *task.Task) string { return t.Title },
+		func() { input.Title = current.Title })
+	merge("status", input.Status, current.Status, func(t *task.Task) string { return t.Status },
+		func() { input.Status = current.Status })
+	merge("priority", input.Priority, current.Priority, func(t *task.Task) string { return t.Priority },
+		func() { input.Priority = current.Priority })
+	merge("assignee", input.Assignee, current.Assignee, func(t *task.Task) string { return t.Assignee },
+		func() { input.Assignee = current.Assignee })
+	merge("tags", list(input.Tags), list(current.Tags), func(t *task.Task) string { return list(t.Tags) },
+		func() { input.Tags = current.Tags })
+	merge("links", list(input.Links), list(current.Links), func(t *task.Task) string { return list(t.Links) },
+		func() { input.Links = current.Links })
+	merge("depends_on", deps(input.DependsOn), deps(current.DependsOn), func(t *task.Task) string { return deps(t.DependsOn) },
+		func() { input.DependsOn = current.DependsOn })
+	merge("due", input.Due, current.Due, func(t *task.Task) string { return t.Due },
+		func() { input.Due = current.Due })
+	merge("body", body(input.Body), body(current.Body), func(t *task.Task) string { return body(t.Body) },
+		func() { input.Body = current.Body })
+
+	if len(changes) == 0 {
+		return nil
+	}
+	return &ConflictError{ID: current.ID, Current: current, Changes: changes}
+}
+
+// moveConflict rejects a move of a task that changed since it was shown.
+func moveConflict(current *task.Task, status string) *ConflictError {
+	e := &ConflictError{ID: current.ID, Current: current}
+	if status != current.Status {
+		e.Changes = append(e.Changes, FieldChange{Field: "status", Yours: status, Theirs: current.Status})
+	}
+	return e
+}
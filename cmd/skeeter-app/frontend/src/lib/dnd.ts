@@ -1,6 +1,7 @@
-import { MoveTask } from '../../wailsjs/go/main/App';
+import { MoveTask, ReorderTask } from '../../wailsjs/go/main/App';
 import { refreshBoard } from './stores/board';
-import { notify, notifyError } from './stores/notifications';
+import { notify, notifyError, conflictOf, transitionOf } from './stores/notifications';
+import { confirmTransition } from './stores/transition';
 
 const MIME = 'application/x-skeeter-task';
 
@@ -48,6 +49,38 @@ export function handleDragLeave(e: DragEvent) {
   }
 }
 
+function cardSelector(taskId: string): string {
+  return `[data-task-id="${CSS.escape(taskId)}"]`;
+}
+
+// dropNeighbors finds the cards of the dragged task's priority that the drop
+// landed between. Ranks only order tasks within a priority, so other cards
+// are ignored. Returns null when the task would stay where it is.
+function dropNeighbors(column: HTMLElement, y: number, taskId: string) {
+  const dragged = document.querySelector<HTMLElement>(cardSelector(taskId));
+  const priority = dragged?.dataset.priority;
+  const cards = Array.from(column.querySelectorAll<HTMLElement>('[data-task-id]'))
+    .filter(c => c.dataset.priority === priority);
+  const others = cards.filter(c => c.dataset.taskId !== taskId);
+  if (others.length === 0) return null;
+
+  let index = others.findIndex(c => {
+    const rect = c.getBoundingClientRect();
+    return y < rect.top + rect.height / 2;
+  });
+  if (index < 0) index = others.length;
+  const beforeId = others[index]?.dataset.taskId || '';
+  const afterId = others[index - 1]?.dataset.taskId || '';
+
+  const current = cards.findIndex(c => c.dataset.taskId === taskId);
+  if (current >= 0 &&
+      (cards[current + 1]?.dataset.taskId || '') === beforeId &&
+      (cards[current - 1]?.dataset.taskId || '') === afterId) {
+    return null;
+  }
+  return { beforeId, afterId };
+}
+
 export async function handleDrop(e: DragEvent, targetStatus: string) {
   e.preventDefault();
   const el = e.currentTarget as HTMLElement;
@@ -58,11 +91,36 @@ export async function handleDrop(e: DragEvent, targetStatus: string) {
   const taskId = e.dataTransfer.getData(MIME);
   if (!taskId) return;
 
-  try {
-    await MoveTask(taskId, targetStatus);
+  const inColumn = el.querySelector(cardSelector(taskId)) !== null;
+  const neighbors = dropNeighbors(el, e.clientY, taskId);
+  if (inColumn && !neighbors) return;
+
+  const version = document.querySelector<HTMLElement>(cardSelector(taskId))?.dataset.version || '';
+  const drop = async (force: boolean) => {
+    if (!inColumn) {
+      await MoveTask(taskId, targetStatus, version, force);
+    }
+    if (neighbors) {
+      await ReorderTask(taskId, neighbors.beforeId, neighbors.afterId);
+    }
     await refreshBoard();
-    notify('success', `Moved ${taskId} to ${targetStatus}`);
+    notify('success', inColumn ? `Reordered ${taskId}` : `Moved ${taskId} to ${targetStatus}`);
+  };
+
+  try {
+    await drop(false);
   } catch (err) {
+    const transition = transitionOf(err);
+    if (transition) {
+      // The workflow refused the move; let the user override it.
+      confirmTransition(transition, () => drop(true).catch(notifyError));
+      return;
+    }
+    if (conflictOf(err)) {
+      // Someone else changed the task since the board loaded; show them
+      // the current state before they try again.
+      await refreshBoard();
+    }
     notifyError(err);
   }
 }
@@ -1,5 +1,5 @@
 import { writable } from 'svelte/store';
-import type { Notification } from '../types';
+import type { Conflict, FieldErrors, Notification, TransitionError } from '../types';
 
 let nextId = 0;
 
@@ -13,7 +13,41 @@ export function notify(type: Notification['type'], message: string) {
   }, 4000);
 }
 
+// errorMessage extracts a message from a rejected backend call. Validation
+// errors arrive as {message, fields} objects, everything else as strings.
+function errorMessage(err: unknown): string {
+  if (err instanceof Error) return err.message;
+  if (err && typeof err === 'object' && 'message' in err) return String((err as { message: unknown }).message);
+  return String(err);
+}
+
+// fieldErrors returns the per-field messages of a validation error, or null
+// for any other error.
+export function fieldErrors(err: unknown): FieldErrors | null {
+  if (err && typeof err === 'object' && 'fields' in err) {
+    return (err as { fields: FieldErrors }).fields;
+  }
+  return null;
+}
+
+// conflictOf returns the conflict a write was rejected with, or null for any
+// other error.
+export function conflictOf(err: unknown): Conflict | null {
+  if (err && typeof err === 'object' && 'conflict' in err) {
+    return (err as { conflict: Conflict }).conflict;
+  }
+  return null;
+}
+
+// transitionOf returns the workflow violation a status change was rejected
+// with, or null for any other error.
+export function transitionOf(err: unknown): TransitionError | null {
+  if (err && typeof err === 'object' && 'transition' in err) {
+    return (err as { transition: TransitionError }).transition;
+  }
+  return null;
+}
+
 export function notifyError(err: unknown) {
-  const message = err instanceof Error ? err.message : String(err);
-  notify('error', message);
+  notify('error', errorMessage(err));
 }
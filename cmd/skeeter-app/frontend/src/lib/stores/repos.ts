@@ -3,6 +3,10 @@ import type { RepoEntry } from '../types';
 import { GetRepos, GetActiveRepoName } from '../../../wailsjs/go/main/App';
 import { notifyError } from './notifications';
 
+// ALL_REPOS is the sidebar entry for the aggregated board. It matches
+// AllRepos in the Go app.
+export const ALL_REPOS = 'All repos';
+
 export const repos = writable<RepoEntry[]>([]);
 export const activeRepoName = writable('');
 
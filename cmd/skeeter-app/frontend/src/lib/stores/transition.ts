@@ -0,0 +1,16 @@
+import { writable } from 'svelte/store';
+import type { TransitionError } from '../types';
+
+export interface PendingTransition {
+  transition: TransitionError;
+  // force repeats the rejected change with the workflow overridden.
+  force: () => Promise<void>;
+}
+
+// pendingTransition is a status change the workflow rejected, waiting for
+// the user to cancel it or move the task anyway.
+export const pendingTransition = writable<PendingTransition | null>(null);
+
+export function confirmTransition(transition: TransitionError, force: () => Promise<void>) {
+  pendingTransition.set({ transition, force });
+}
@@ -0,0 +1,82 @@
+import { writable, get } from 'svelte/store';
+import type { RepoUIState } from '../types';
+import { GetUIState, SaveUIState } from '../../../wailsjs/go/main/App';
+import { filters } from './filters';
+import { selectedTask, openDetail, closeDetail } from './taskDetail';
+import { activeRepoName } from './repos';
+import { board, refreshBoard } from './board';
+import { notifyError } from './notifications';
+
+// collapsed lists the columns folded away on the active board.
+export const collapsed = writable<string[]>([]);
+
+export function toggleCollapsed(status: string) {
+  collapsed.update(c => c.includes(status) ? c.filter(s => s !== status) : [...c, status]);
+}
+
+let restoring = false;
+let lastSaved = '';
+
+function current(): RepoUIState {
+  return {
+    filter: get(filters),
+    collapsed: get(collapsed),
+    openTask: get(selectedTask)?.id || '',
+  };
+}
+
+// restoreUI applies the board state saved for the repo just switched to,
+// loads its board and reopens the task that was last open.
+export async function restoreUI(state: RepoUIState | null) {
+  restoring = true;
+  try {
+    closeDetail();
+    filters.set({ priority: '', assignee: '', tag: '', ...state?.filter });
+    collapsed.set(state?.collapsed || []);
+    await refreshBoard();
+    if (state?.openTask) {
+      const open = (get(board).columns || [])
+        .flatMap(col => col.tasks || [])
+        .find(t => t.id === state.openTask);
+      if (open) openDetail(open);
+    }
+  } finally {
+    restoring = false;
+    lastSaved = JSON.stringify(current());
+  }
+}
+
+// restoreActiveUI restores the board state of the repo the app opened with.
+export async function restoreActiveUI() {
+  const name = get(activeRepoName);
+  let state: RepoUIState | null = null;
+  if (name) {
+    try {
+      state = await GetUIState(name);
+    } catch (e) {
+      notifyError(e);
+    }
+  }
+  await restoreUI(state);
+}
+
+async function save() {
+  const name = get(activeRepoName);
+  if (restoring || !name) return;
+  const state = current();
+  const json = JSON.stringify(state);
+  if (json === lastSaved) return;
+  lastSaved = json;
+  try {
+    await SaveUIState(name, state);
+  } catch (e) {
+    notifyError(e);
+  }
+}
+
+// persistUI saves the board state of the active repo whenever it changes.
+// It returns a function that stops saving.
+export function persistUI(): () => void {
+  const offs = [filters.subscribe(save), collapsed.subscribe(save), selectedTask.subscribe(save)];
+  return () => offs.forEach(off => off());
+}
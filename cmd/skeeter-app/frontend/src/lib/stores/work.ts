@@ -0,0 +1,77 @@
+import { writable } from 'svelte/store';
+import type { WorkEvent, WorkStatus } from '../types';
+import { GetWorkStatus, StartWork, StopWork } from '../../../wailsjs/go/main/App';
+import { EventsOn } from '../../../wailsjs/runtime/runtime';
+import { refreshBoard } from './board';
+import { notify, notifyError } from './notifications';
+
+// Output lines kept per task; older lines are dropped.
+const MAX_LOG_LINES = 500;
+
+export const workStatus = writable<WorkStatus>({ running: false, iteration: 0, taskId: '', completed: [], error: '' });
+export const workLogs = writable<Record<string, string[]>>({});
+
+export async function refreshWorkStatus() {
+  try {
+    workStatus.set(await GetWorkStatus());
+  } catch (e) {
+    notifyError(e);
+  }
+}
+
+function appendLog(taskId: string, line: string) {
+  workLogs.update(logs => {
+    const lines = [...(logs[taskId] || []), line].slice(-MAX_LOG_LINES);
+    return { ...logs, [taskId]: lines };
+  });
+}
+
+function handleEvent(e: WorkEvent) {
+  switch (e.kind) {
+    case 'iteration-start':
+      workLogs.update(logs => ({ ...logs, [e.taskId]: [] }));
+      refreshWorkStatus();
+      refreshBoard();
+      break;
+    case 'output':
+      appendLog(e.taskId, e.line);
+      break;
+    case 'task-done':
+      notify('success', `${e.taskId} done`);
+      break;
+    case 'task-failed':
+      appendLog(e.taskId, `Failed: ${e.message}`);
+      notify('error', `${e.taskId} failed: ${e.message}`);
+      break;
+    case 'stopped':
+      notify('info', `Work loop stopped: ${e.message}`);
+      refreshWorkStatus();
+      refreshBoard();
+      break;
+  }
+}
+
+// listenForWork subscribes to work loop events and returns the unsubscribe
+// function.
+export function listenForWork(): () => void {
+  refreshWorkStatus();
+  return EventsOn('work:event', handleEvent);
+}
+
+export async function startWork() {
+  try {
+    await StartWork({ max: 0, assign: '', workDir: '' });
+    await refreshWorkStatus();
+  } catch (e) {
+    notifyError(e);
+  }
+}
+
+export async function stopWork() {
+  try {
+    await StopWork();
+    await refreshWorkStatus();
+  } catch (e) {
+    notifyError(e);
+  }
+}
@@ -3,17 +3,42 @@ export interface Task {
   title: string;
   status: string;
   priority: string;
+  rank?: string;
   assignee: string;
+  type?: string;
   tags: string[];
   links: string[];
+  branch?: string;
+  depends_on?: string[];
+  relates_to?: string[];
+  duplicates?: string[];
+  split_from?: string[];
+  due?: string;
   created: string;
   updated: string;
+  history?: Transition[];
   body: string;
+  version?: string;
+  // Set on the aggregated board, where id is qualified as repo:ID.
+  repo?: string;
+  color?: string;
+}
+
+// Transition is one status change in a task's history. forced lists the
+// workflow rules it overrode.
+export interface Transition {
+  date: string;
+  from: string;
+  to: string;
+  forced?: string;
 }
 
 export interface ColumnData {
   status: string;
   tasks: Task[];
+  count: number;
+  limit?: number;
+  overLimit: boolean;
 }
 
 export interface ProjectConfig {
@@ -37,6 +62,8 @@ export interface BoardData {
   columns: ColumnData[];
   config: Config;
   repoName: string;
+  skipped?: string[];
+  errors?: string[];
 }
 
 export interface BoardFilter {
@@ -50,14 +77,42 @@ export interface RepoEntry {
   path: string;
   remote: string;
   dir: string;
+  color?: string;
+  statusMap?: Record<string, string>;
+  notify?: NotifyConfig;
+  ui?: RepoUIState;
+  problem?: string;
+}
+
+export interface RepoUIState {
+  filter: BoardFilter;
+  collapsed?: string[];
+  openTask?: string;
+}
+
+export interface ImportResult {
+  added: string[] | null;
+  skipped: string[] | null;
+}
+
+export interface NotifyConfig {
+  assignee: string;
+  agent: boolean;
+  overdue: boolean;
 }
 
 export interface CreateTaskInput {
   title: string;
+  status?: string;
   priority: string;
   assignee: string;
   tags: string[];
+  links?: string[];
+  depends_on?: string[];
+  due?: string;
   body: string;
+  repo?: string;
+  force?: boolean;
 }
 
 export interface UpdateTaskInput {
@@ -67,11 +122,91 @@ export interface UpdateTaskInput {
   priority: string;
   assignee: string;
   tags: string[];
+  links: string[];
+  depends_on: string[];
+  due: string;
   body: string;
+  version: string;
+  // The task as the edit started from, so a stale edit merges with changes
+  // made elsewhere instead of conflicting on every field.
+  base?: Task;
+  force?: boolean;
+}
+
+export interface TaskDetail {
+  task: Task;
+  blockedBy: string[] | null;
+  blocking: string[] | null;
+  blocked: boolean;
+  git?: GitStatus;
+}
+
+// GitStatus is what the board's git repository knows about a task. Ahead,
+// behind and merged compare branch with main and mean nothing unless
+// exists is set.
+export interface GitStatus {
+  branch?: string;
+  exists: boolean;
+  main?: string;
+  ahead: number;
+  behind: number;
+  merged: boolean;
+  commits: GitCommit[];
+}
+
+export interface GitCommit {
+  sha: string;
+  date: string;
+  author: string;
+  subject: string;
+}
+
+export interface FieldChange {
+  field: string;
+  yours: string;
+  theirs: string;
+}
+
+// Conflict is how the backend rejects a write based on a stale version.
+// current is the task as it is now.
+export interface Conflict {
+  id: string;
+  current: Task;
+  changes: FieldChange[] | null;
 }
 
+// TransitionError is how the backend rejects a status change the repo's
+// workflow doesn't allow. Retrying with force overrides it.
+export interface TransitionError {
+  id: string;
+  from: string;
+  to: string;
+  violations: string[];
+}
+
+// FieldErrors maps a task field's JSON name to why it was rejected.
+export type FieldErrors = Record<string, string>;
+
 export interface Notification {
   id: number;
   type: 'success' | 'error' | 'info';
   message: string;
 }
+
+export interface WorkStatus {
+  running: boolean;
+  iteration: number;
+  taskId: string;
+  completed: string[];
+  error: string;
+}
+
+export interface WorkEvent {
+  kind: 'iteration-start' | 'output' | 'task-done' | 'task-failed' | 'stopped';
+  iteration: number;
+  taskId: string;
+  title?: string;
+  stream?: 'stdout' | 'stderr';
+  line: string;
+  message: string;
+}
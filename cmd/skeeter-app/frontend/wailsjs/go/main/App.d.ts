@@ -1,5 +1,6 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {agent} from '../models';
 import {main} from '../models';
 import {task} from '../models';
 
@@ -15,20 +16,48 @@ export function EnhanceDraft(arg1:string,arg2:string):Promise<string>;
 
 export function EnhanceTask(arg1:string):Promise<string>;
 
+export function ExportRepos():Promise<string>;
+
 export function GetActiveRepoName():Promise<string>;
 
+export function GetBackgroundMode():Promise<boolean>;
+
 export function GetBoard(arg1:main.BoardFilter):Promise<main.BoardData>;
 
 export function GetRepos():Promise<Array<main.RepoEntry>>;
 
 export function GetTask(arg1:string):Promise<task.Task>;
 
+export function GetTaskDetail(arg1:string):Promise<main.TaskDetail>;
+
 export function GetTemplate(arg1:string):Promise<string>;
 
-export function MoveTask(arg1:string,arg2:string):Promise<task.Task>;
+export function GetUIState(arg1:string):Promise<main.RepoUIState>;
+
+export function GetWorkStatus():Promise<agent.Status>;
+
+export function ImportRepos():Promise<main.ImportResult>;
+
+export function InitRepo(arg1:main.RepoEntry,arg2:string):Promise<void>;
+
+export function MoveTask(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<task.Task>;
+
+export function Quit():Promise<void>;
 
 export function RemoveRepo(arg1:string):Promise<void>;
 
-export function SwitchRepo(arg1:string):Promise<void>;
+export function ReorderTask(arg1:string,arg2:string,arg3:string):Promise<task.Task>;
+
+export function SaveUIState(arg1:string,arg2:main.RepoUIState):Promise<void>;
+
+export function SetBackgroundMode(arg1:boolean):Promise<void>;
+
+export function SetRepoNotifications(arg1:string,arg2:main.NotifyConfig):Promise<void>;
+
+export function StartWork(arg1:agent.Options):Promise<void>;
+
+export function StopWork():Promise<void>;
+
+export function SwitchRepo(arg1:string):Promise<main.RepoUIState>;
 
 export function UpdateTask(arg1:main.UpdateTaskInput):Promise<task.Task>;
@@ -26,10 +26,18 @@ export function EnhanceTask(arg1) {
   return window['go']['main']['App']['EnhanceTask'](arg1);
 }
 
+export function ExportRepos() {
+  return window['go']['main']['App']['ExportRepos']();
+}
+
 export function GetActiveRepoName() {
   return window['go']['main']['App']['GetActiveRepoName']();
 }
 
+export function GetBackgroundMode() {
+  return window['go']['main']['App']['GetBackgroundMode']();
+}
+
 export function GetBoard(arg1) {
   return window['go']['main']['App']['GetBoard'](arg1);
 }
@@ -42,18 +50,66 @@ export function GetTask(arg1) {
   return window['go']['main']['App']['GetTask'](arg1);
 }
 
+export function GetTaskDetail(arg1) {
+  return window['go']['main']['App']['GetTaskDetail'](arg1);
+}
+
 export function GetTemplate(arg1) {
   return window['go']['main']['App']['GetTemplate'](arg1);
 }
 
-export function MoveTask(arg1, arg2) {
-  return window['go']['main']['App']['MoveTask'](arg1, arg2);
+export function GetUIState(arg1) {
+  return window['go']['main']['App']['GetUIState'](arg1);
+}
+
+export function GetWorkStatus() {
+  return window['go']['main']['App']['GetWorkStatus']();
+}
+
+export function ImportRepos() {
+  return window['go']['main']['App']['ImportRepos']();
+}
+
+export function InitRepo(arg1, arg2) {
+  return window['go']['main']['App']['InitRepo'](arg1, arg2);
+}
+
+export function MoveTask(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['MoveTask'](arg1, arg2, arg3, arg4);
+}
+
+export function Quit() {
+  return window['go']['main']['App']['Quit']();
 }
 
 export function RemoveRepo(arg1) {
   return window['go']['main']['App']['RemoveRepo'](arg1);
 }
 
+export function ReorderTask(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ReorderTask'](arg1, arg2, arg3);
+}
+
+export function SaveUIState(arg1, arg2) {
+  return window['go']['main']['App']['SaveUIState'](arg1, arg2);
+}
+
+export function SetBackgroundMode(arg1) {
+  return window['go']['main']['App']['SetBackgroundMode'](arg1);
+}
+
+export function SetRepoNotifications(arg1, arg2) {
+  return window['go']['main']['App']['SetRepoNotifications'](arg1, arg2);
+}
+
+export function StartWork(arg1) {
+  return window['go']['main']['App']['StartWork'](arg1);
+}
+
+export function StopWork() {
+  return window['go']['main']['App']['StopWork']();
+}
+
 export function SwitchRepo(arg1) {
   return window['go']['main']['App']['SwitchRepo'](arg1);
 }
@@ -1,3 +1,44 @@
+export namespace agent {
+	
+	export class Options {
+	    max: number;
+	    assign: string;
+	    workDir: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.max = source["max"];
+	        this.assign = source["assign"];
+	        this.workDir = source["workDir"];
+	    }
+	}
+	export class Status {
+	    running: boolean;
+	    iteration: number;
+	    taskId: string;
+	    completed: string[];
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Status(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.iteration = source["iteration"];
+	        this.taskId = source["taskId"];
+	        this.completed = source["completed"];
+	        this.error = source["error"];
+	    }
+	}
+
+}
+
 export namespace config {
 	
 	export class LLMConfig {
@@ -70,11 +111,125 @@ export namespace config {
 
 }
 
+export namespace gitinfo {
+	
+	export class Commit {
+	    sha: string;
+	    date: string;
+	    author: string;
+	    subject: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Commit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sha = source["sha"];
+	        this.date = source["date"];
+	        this.author = source["author"];
+	        this.subject = source["subject"];
+	    }
+	}
+	export class Status {
+	    branch?: string;
+	    exists: boolean;
+	    main?: string;
+	    ahead: number;
+	    behind: number;
+	    merged: boolean;
+	    commits: Commit[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Status(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.branch = source["branch"];
+	        this.exists = source["exists"];
+	        this.main = source["main"];
+	        this.ahead = source["ahead"];
+	        this.behind = source["behind"];
+	        this.merged = source["merged"];
+	        this.commits = this.convertValues(source["commits"], Commit);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace main {
 	
+	export class BoardTask {
+	    id: string;
+	    title: string;
+	    status: string;
+	    priority: string;
+	    rank: string;
+	    assignee: string;
+	    type?: string;
+	    tags: string[];
+	    links: string[];
+	    branch?: string;
+	    depends_on: string[];
+	    due: string;
+	    created: string;
+	    updated: string;
+	    body: string;
+	    version?: string;
+	    repo?: string;
+	    color?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BoardTask(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.title = source["title"];
+	        this.status = source["status"];
+	        this.priority = source["priority"];
+	        this.rank = source["rank"];
+	        this.assignee = source["assignee"];
+	        this.type = source["type"];
+	        this.tags = source["tags"];
+	        this.links = source["links"];
+	        this.branch = source["branch"];
+	        this.depends_on = source["depends_on"];
+	        this.due = source["due"];
+	        this.created = source["created"];
+	        this.updated = source["updated"];
+	        this.body = source["body"];
+	        this.version = source["version"];
+	        this.repo = source["repo"];
+	        this.color = source["color"];
+	    }
+	}
 	export class ColumnData {
 	    status: string;
-	    tasks: task.Task[];
+	    tasks: BoardTask[];
+	    count: number;
+	    limit?: number;
+	    overLimit: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new ColumnData(source);
@@ -83,7 +238,10 @@ export namespace main {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.status = source["status"];
-	        this.tasks = this.convertValues(source["tasks"], task.Task);
+	        this.tasks = this.convertValues(source["tasks"], BoardTask);
+	        this.count = source["count"];
+	        this.limit = source["limit"];
+	        this.overLimit = source["overLimit"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -108,6 +266,8 @@ export namespace main {
 	    columns: ColumnData[];
 	    config?: config.Config;
 	    repoName: string;
+	    skipped: string[];
+	    errors: string[];
 	
 	    static createFrom(source: any = {}) {
 	        return new BoardData(source);
@@ -118,6 +278,8 @@ export namespace main {
 	        this.columns = this.convertValues(source["columns"], ColumnData);
 	        this.config = this.convertValues(source["config"], config.Config);
 	        this.repoName = source["repoName"];
+	        this.skipped = source["skipped"];
+        this.errors = source["errors"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -157,10 +319,16 @@ export namespace main {
 	
 	export class CreateTaskInput {
 	    title: string;
+	    status: string;
 	    priority: string;
 	    assignee: string;
 	    tags: string[];
+	    links: string[];
+	    depends_on: string[];
+	    due: string;
 	    body: string;
+	    repo: string;
+	    force: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new CreateTaskInput(source);
@@ -169,10 +337,46 @@ export namespace main {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.title = source["title"];
+        this.status = source["status"];
 	        this.priority = source["priority"];
 	        this.assignee = source["assignee"];
 	        this.tags = source["tags"];
+        this.links = source["links"];
+        this.depends_on = source["depends_on"];
+        this.due = source["due"];
 	        this.body = source["body"];
+        this.repo = source["repo"];
+	        this.force = source["force"];
+	    }
+	}
+	export class ImportResult {
+	    added: string[];
+	    skipped: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.added = source["added"];
+	        this.skipped = source["skipped"];
+	    }
+	}
+	export class NotifyConfig {
+	    assignee: string;
+	    agent: boolean;
+	    overdue: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotifyConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.assignee = source["assignee"];
+	        this.agent = source["agent"];
+	        this.overdue = source["overdue"];
 	    }
 	}
 	export class RepoEntry {
@@ -180,6 +384,11 @@ export namespace main {
 	    path: string;
 	    remote: string;
 	    dir: string;
+	    color?: string;
+	    statusMap?: Record<string, string>;
+	    notify?: NotifyConfig;
+	    ui?: RepoUIState;
+	    problem?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new RepoEntry(source);
@@ -191,7 +400,102 @@ export namespace main {
 	        this.path = source["path"];
 	        this.remote = source["remote"];
 	        this.dir = source["dir"];
+	        this.color = source["color"];
+	        this.statusMap = source["statusMap"];
+	        this.notify = this.convertValues(source["notify"], NotifyConfig);
+	        this.ui = this.convertValues(source["ui"], RepoUIState);
+	        this.problem = source["problem"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RepoUIState {
+	    filter: BoardFilter;
+	    collapsed?: string[];
+	    openTask?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RepoUIState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filter = this.convertValues(source["filter"], BoardFilter);
+	        this.collapsed = source["collapsed"];
+	        this.openTask = source["openTask"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskDetail {
+	    task?: task.Task;
+	    blockedBy: string[];
+	    blocking: string[];
+	    blocked: boolean;
+	    git?: gitinfo.Status;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskDetail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task = this.convertValues(source["task"], task.Task);
+	        this.blockedBy = source["blockedBy"];
+	        this.blocking = source["blocking"];
+	        this.blocked = source["blocked"];
+	        this.git = this.convertValues(source["git"], gitinfo.Status);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class UpdateTaskInput {
 	    id: string;
@@ -200,7 +504,13 @@ export namespace main {
 	    priority: string;
 	    assignee: string;
 	    tags: string[];
+	    links: string[];
+	    depends_on: string[];
+	    due: string;
 	    body: string;
+	    version: string;
+	    base?: task.Task;
+	    force: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new UpdateTaskInput(source);
@@ -214,25 +524,75 @@ export namespace main {
 	        this.priority = source["priority"];
 	        this.assignee = source["assignee"];
 	        this.tags = source["tags"];
+        this.links = source["links"];
+        this.depends_on = source["depends_on"];
+        this.due = source["due"];
 	        this.body = source["body"];
+        this.version = source["version"];
+	        this.base = this.convertValues(source["base"], task.Task);
+	        this.force = source["force"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 
 }
 
 export namespace task {
 	
+	export class Transition {
+	    date: string;
+	    from: string;
+	    to: string;
+	    forced?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Transition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.forced = source["forced"];
+	    }
+	}
 	export class Task {
 	    id: string;
 	    title: string;
 	    status: string;
 	    priority: string;
+	    rank: string;
 	    assignee: string;
 	    tags: string[];
 	    links: string[];
+	    depends_on: string[];
+	    relates_to?: string[];
+	    duplicates?: string[];
+	    split_from?: string[];
+	    due: string;
 	    created: string;
 	    updated: string;
+	    history?: Transition[];
 	    body: string;
+	    version?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Task(source);
@@ -244,13 +604,39 @@ export namespace task {
 	        this.title = source["title"];
 	        this.status = source["status"];
 	        this.priority = source["priority"];
+	        this.rank = source["rank"];
 	        this.assignee = source["assignee"];
 	        this.tags = source["tags"];
 	        this.links = source["links"];
+	        this.depends_on = source["depends_on"];
+	        this.relates_to = source["relates_to"];
+	        this.duplicates = source["duplicates"];
+	        this.split_from = source["split_from"];
+	        this.due = source["due"];
 	        this.created = source["created"];
 	        this.updated = source["updated"];
+	        this.history = this.convertValues(source["history"], Transition);
 	        this.body = source["body"];
+	        this.version = source["version"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 
 }
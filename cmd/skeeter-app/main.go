@@ -23,6 +23,8 @@ func main() {
 		},
 		BackgroundColour: &options.RGBA{R: 24, G: 24, B: 27, A: 1},
 		OnStartup:        app.startup,
+		OnBeforeClose:    app.beforeClose,
+		ErrorFormatter:   formatError,
 		Bind: []interface{}{
 			app,
 		},
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/agent"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// overdueInterval is how often watched repos are checked for tasks that
+// went overdue without anything else changing.
+const overdueInterval = time.Hour
+
+// activity follows the tasks of the watched repos so the app can notify
+// about changes and show what's going on while it sits in the background.
+type activity struct {
+	mu      sync.Mutex
+	known   map[string]map[string]string // repo → task ID → status
+	overdue map[string]string            // repo:ID → date last reported
+	counts  map[string][2]int            // repo → in progress, blocked
+}
+
+func newActivity() *activity {
+	return &activity{
+		known:   make(map[string]map[string]string),
+		overdue: make(map[string]string),
+		counts:  make(map[string][2]int),
+	}
+}
+
+// reset forgets the watched repos when the board switches, keeping which
+// overdue tasks were already reported today.
+func (ac *activity) reset() {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	ac.known = make(map[string]map[string]string)
+	ac.counts = make(map[string][2]int)
+}
+
+// totals sums the counts of every watched repo.
+func (ac *activity) totals() (inProgress, blocked int) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	for _, c := range ac.counts {
+		inProgress += c[0]
+		blocked += c[1]
+	}
+	return inProgress, blocked
+}
+
+// notification is one message to show the user.
+type notification struct {
+	title, body string
+}
+
+// update records a fresh listing of repo and returns what to notify about.
+// The first listing of a repo only records state, so opening a board does
+// not replay old changes. Tasks in inProgressStatus are counted for the
+// window title.
+func (ac *activity) update(repo string, tasks []task.Task, blocked int, cfg *NotifyConfig, inProgressStatus, doneStatus, today string) []notification {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	var notes []notification
+	prev, seen := ac.known[repo]
+	next := make(map[string]string, len(tasks))
+	inProgress := 0
+	for _, t := range tasks {
+		next[t.ID] = t.Status
+		if inProgressStatus != "" && t.Status == inProgressStatus {
+			inProgress++
+		}
+		if cfg == nil {
+			continue
+		}
+
+		if old, ok := prev[t.ID]; seen && ok && old != t.Status && cfg.Assignee != "" && t.Assignee == cfg.Assignee {
+			notes = append(notes, notification{
+				title: repo,
+				body:  fmt.Sprintf("%s moved from %s to %s: %s", t.ID, old, t.Status, t.Title),
+			})
+		}
+
+		key := qualify(repo, t.ID)
+		if cfg.Overdue && t.Due != "" && t.Due < today && t.Status != doneStatus && ac.overdue[key] != today {
+			ac.overdue[key] = today
+			notes = append(notes, notification{
+				title: repo,
+				body:  fmt.Sprintf("%s is overdue (due %s): %s", t.ID, t.Due, t.Title),
+			})
+		}
+	}
+	ac.known[repo] = next
+	ac.counts[repo] = [2]int{inProgress, blocked}
+	return notes
+}
+
+// observe lists repo and raises any notifications its changes call for.
+// The listing holds the app lock like the board does, so it never runs
+// alongside the app's own writes to s.
+func (a *App) observe(ctx context.Context, repo string, s store.Store) {
+	a.mu.RLock()
+	if ctx.Err() != nil {
+		a.mu.RUnlock()
+		return
+	}
+	tasks, err := s.List(store.Filter{})
+	if err != nil {
+		a.mu.RUnlock()
+		return
+	}
+	cfg := s.GetConfig()
+	doneStatus := cfg.DoneStatus()
+	blocked := 0
+	for i := range tasks {
+		if tasks[i].Status != doneStatus && store.IsBlocked(s, &tasks[i], tasks) {
+			blocked++
+		}
+	}
+	notifyCfg := a.notifyConfig(repo)
+	a.mu.RUnlock()
+
+	notes := a.activity.update(repo, tasks, blocked, notifyCfg, cfg.InProgressStatus(), doneStatus, time.Now().Format("2006-01-02"))
+	for _, n := range notes {
+		a.sendNotification(n.title, n.body)
+	}
+	a.updateTitle()
+}
+
+// watchActivity observes repo now and every overdueInterval until ctx is
+// cancelled, so overdue tasks are noticed even when nothing changes.
+func (a *App) watchActivity(ctx context.Context, repo string, s store.Store) {
+	a.observe(ctx, repo, s)
+	ticker := time.NewTicker(overdueInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.observe(ctx, repo, s)
+		}
+	}
+}
+
+// notifyConfig returns repo's notification settings, or nil if it has none.
+func (a *App) notifyConfig(repo string) *NotifyConfig {
+	if a.repoStore == nil {
+		return nil
+	}
+	entry, err := a.repoStore.Get(repo)
+	if err != nil {
+		return nil
+	}
+	return entry.Notify
+}
+
+// notifyWork reports tasks the work loop finished or failed in repo.
+func (a *App) notifyWork(repo string, e agent.Event) {
+	cfg := a.notifyConfig(repo)
+	if cfg == nil || !cfg.Agent {
+		return
+	}
+	switch e.Kind {
+	case agent.EventTaskDone:
+		a.sendNotification(repo, fmt.Sprintf("Agent finished %s: %s", e.TaskID, e.Title))
+	case agent.EventTaskFailed:
+		a.sendNotification(repo, fmt.Sprintf("Agent failed %s: %s", e.TaskID, e.Message))
+	}
+}
+
+// sendNotification shows a native notification, falling back to an in-app
+// toast where the platform has no notifier.
+func (a *App) sendNotification(title, body string) {
+	if err := notifyNative(title, body); err != nil {
+		eventsEmit(a.ctx, "notify", title+": "+body)
+	}
+}
+
+// updateTitle shows the active board and its task counts in the window
+// title, which stays visible in the taskbar while the app is minimised.
+// Wails v2 has no system tray API, so the title stands in for a tray icon.
+func (a *App) updateTitle() {
+	a.mu.RLock()
+	name := a.repoName
+	a.mu.RUnlock()
+
+	title := "Skeeter"
+	if name != "" {
+		title += " — " + name
+	}
+	var parts []string
+	inProgress, blocked := a.activity.totals()
+	if inProgress > 0 {
+		parts = append(parts, fmt.Sprintf("%d in progress", inProgress))
+	}
+	if blocked > 0 {
+		parts = append(parts, fmt.Sprintf("%d blocked", blocked))
+	}
+	if len(parts) > 0 {
+		title += " (" + strings.Join(parts, ", ") + ")"
+	}
+	runtime.WindowSetTitle(a.ctx, title)
+}
+
+// beforeClose keeps the app running in background mode. Without a tray
+// icon to hide in, the window is minimised rather than hidden, so it can
+// still be brought back from the taskbar.
+func (a *App) beforeClose(ctx context.Context) (prevent bool) {
+	if a.quitting.Load() || a.repoStore == nil {
+		return false
+	}
+	if background, _ := a.repoStore.Background(); background {
+		runtime.WindowMinimise(ctx)
+		return true
+	}
+	return false
+}
+
+// Quit exits the app, even in background mode.
+func (a *App) Quit() {
+	a.quitting.Store(true)
+	runtime.Quit(a.ctx)
+}
+
+// GetBackgroundMode reports whether closing the window keeps the app
+// running.
+func (a *App) GetBackgroundMode() (bool, error) {
+	return a.repoStore.Background()
+}
+
+// SetBackgroundMode turns background mode on or off.
+func (a *App) SetBackgroundMode(on bool) error {
+	return a.repoStore.SetBackground(on)
+}
+
+// SetRepoNotifications saves which task events of repo raise
+// notifications. A nil or empty config turns them off.
+func (a *App) SetRepoNotifications(name string, cfg *NotifyConfig) error {
+	if cfg != nil && *cfg == (NotifyConfig{}) {
+		cfg = nil
+	}
+	return a.repoStore.Update(name, func(entry *RepoEntry) {
+		entry.Notify = cfg
+	})
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// notifyNative shows a Notification Center notification through osascript.
+func notifyNative(title, body string) error {
+	script := "display notification " + appleScriptString(body) + " with title " + appleScriptString(title)
+	return exec.Command("osascript", "-e", script).Run()
+}
+
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,8 @@
+package main
+
+import "os/exec"
+
+// notifyNative shows a desktop notification through notify-send.
+func notifyNative(title, body string) error {
+	return exec.Command("notify-send", "--app-name=Skeeter", title, body).Run()
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package main
+
+import "errors"
+
+// notifyNative is unsupported here; callers fall back to in-app toasts.
+func notifyNative(title, body string) error {
+	return errors.New("native notifications are not supported on this platform")
+}
@@ -13,10 +13,63 @@ type RepoEntry struct {
 	Path   string `json:"path"`
 	Remote string `json:"remote"`
 	Dir    string `json:"dir"`
+	// Color marks the repo's cards on the aggregated board.
+	Color string `json:"color,omitempty"`
+	// StatusMap maps this repo's statuses onto the aggregated board's
+	// columns where the names differ.
+	StatusMap map[string]string `json:"statusMap,omitempty"`
+	// Notify chooses which of this repo's task events raise native
+	// notifications; nil means none.
+	Notify *NotifyConfig `json:"notify,omitempty"`
+	// UI is the board state last left on this repo.
+	UI *RepoUIState `json:"ui,omitempty"`
+	// Problem explains why the entry can't be opened. It is worked out when
+	// the list is loaded and never saved.
+	Problem string `json:"problem,omitempty"`
+}
+
+// RepoUIState is the board state restored when a repo is switched to.
+type RepoUIState struct {
+	Filter    BoardFilter `json:"filter"`
+	Collapsed []string    `json:"collapsed,omitempty"`
+	OpenTask  string      `json:"openTask,omitempty"`
+}
+
+// NotifyConfig chooses which task events raise native notifications.
+type NotifyConfig struct {
+	// Assignee is who counts as "me": status changes of tasks assigned to
+	// them are reported. Empty turns those notifications off.
+	Assignee string `json:"assignee"`
+	// Agent reports tasks the work loop finishes or fails.
+	Agent bool `json:"agent"`
+	// Overdue reports open tasks whose due date has passed, once a day.
+	Overdue bool `json:"overdue"`
 }
 
 type repoList struct {
 	Repos []RepoEntry `json:"repos"`
+	// Background keeps the app running, minimised, when its window is
+	// closed so notifications keep arriving.
+	Background bool `json:"background,omitempty"`
+	// AllReposUI is the board state of the aggregated board.
+	AllReposUI *RepoUIState `json:"allReposUi,omitempty"`
+}
+
+// workspace is a shareable repo list, without anyone's personal settings.
+type workspace struct {
+	Repos []RepoEntry `json:"repos"`
+}
+
+// shared is the entry as written to a workspace file.
+func (r RepoEntry) shared() RepoEntry {
+	return RepoEntry{
+		Name:      r.Name,
+		Path:      r.Path,
+		Remote:    r.Remote,
+		Dir:       r.Dir,
+		Color:     r.Color,
+		StatusMap: r.StatusMap,
+	}
 }
 
 type RepoStore struct {
@@ -36,33 +89,268 @@ func NewRepoStore() (*RepoStore, error) {
 	return &RepoStore{path: filepath.Join(dir, "repos.json")}, nil
 }
 
+// Load returns the saved repos, with Problem set on entries that can't be
+// opened.
 func (rs *RepoStore) Load() ([]RepoEntry, error) {
 	rs.mu.Lock()
 	defer rs.mu.Unlock()
-	return rs.loadLocked()
+	repos, err := rs.loadLocked()
+	if err != nil {
+		return nil, err
+	}
+	checkEntries(repos)
+	return repos, nil
 }
 
 func (rs *RepoStore) loadLocked() ([]RepoEntry, error) {
+	rl, err := rs.readLocked()
+	return rl.Repos, err
+}
+
+func (rs *RepoStore) readLocked() (repoList, error) {
+	var rl repoList
 	data, err := os.ReadFile(rs.path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, nil
+			return rl, nil
 		}
-		return nil, err
+		return rl, err
 	}
-	var rl repoList
 	if err := json.Unmarshal(data, &rl); err != nil {
-		return nil, err
+		return rl, fmt.Errorf("reading %s: %w", rs.path, err)
 	}
-	return rl.Repos, nil
+	return rl, nil
 }
 
 func (rs *RepoStore) saveLocked(repos []RepoEntry) error {
-	data, err := json.MarshalIndent(repoList{Repos: repos}, "", "  ")
+	rl, err := rs.readLocked()
+	if err != nil {
+		return err
+	}
+	rl.Repos = repos
+	return rs.writeLocked(rl)
+}
+
+// writeLocked replaces repos.json atomically, so a crash mid-write can't
+// lose the list.
+func (rs *RepoStore) writeLocked(rl repoList) error {
+	for i := range rl.Repos {
+		rl.Repos[i].Problem = ""
+	}
+	data, err := json.MarshalIndent(rl, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(rs.path, data)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// checkEntries sets Problem on entries that are invalid or whose local path
+// is unreachable. Remote repos are only checked when opened.
+func checkEntries(repos []RepoEntry) {
+	seen := make(map[string]bool)
+	for i := range repos {
+		r := &repos[i]
+		r.Problem = entryProblem(*r, seen)
+		seen[r.Name] = true
+	}
+}
+
+func entryProblem(r RepoEntry, seen map[string]bool) string {
+	switch {
+	case r.Name == "":
+		return "missing name"
+	case r.Name == AllRepos:
+		return fmt.Sprintf("%q is reserved for the combined board", AllRepos)
+	case seen[r.Name]:
+		return "duplicate name"
+	case r.Path == "" && r.Remote == "":
+		return "needs a path or a remote"
+	case r.Path != "" && r.Remote != "":
+		return "has both a path and a remote"
+	case r.Path != "":
+		if _, err := os.Stat(filepath.Join(r.Path, "config.yaml")); err != nil {
+			if os.IsNotExist(err) {
+				return "no skeeter config at " + r.Path
+			}
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+// UIState returns the board state saved for name, which may be AllRepos.
+func (rs *RepoStore) UIState(name string) (*RepoUIState, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rl, err := rs.readLocked()
+	if err != nil {
+		return nil, err
+	}
+	if name == AllRepos {
+		return rl.AllReposUI, nil
+	}
+	for _, r := range rl.Repos {
+		if r.Name == name {
+			return r.UI, nil
+		}
+	}
+	return nil, fmt.Errorf("repo %q not found", name)
+}
+
+// SetUIState saves the board state for name, which may be AllRepos.
+func (rs *RepoStore) SetUIState(name string, state *RepoUIState) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rl, err := rs.readLocked()
+	if err != nil {
+		return err
+	}
+	if name == AllRepos {
+		rl.AllReposUI = state
+		return rs.writeLocked(rl)
+	}
+	for i := range rl.Repos {
+		if rl.Repos[i].Name == name {
+			rl.Repos[i].UI = state
+			return rs.writeLocked(rl)
+		}
+	}
+	return fmt.Errorf("repo %q not found", name)
+}
+
+// Export writes the repo list to path as a workspace file.
+func (rs *RepoStore) Export(path string) error {
+	repos, err := rs.Load()
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(rs.path, data, 0644)
+	ws := workspace{Repos: make([]RepoEntry, len(repos))}
+	for i, r := range repos {
+		ws.Repos[i] = r.shared()
+	}
+	data, err := json.MarshalIndent(ws, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// Import adds the repos of the workspace file at path. Relative paths are
+// taken relative to the file. Entries that are invalid or whose name is
+// already saved are skipped, with the reason.
+func (rs *RepoStore) Import(path string) (added, skipped []string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var ws workspace
+	if err := json.Unmarshal(data, &ws); err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	repos, err := rs.loadLocked()
+	if err != nil {
+		return nil, nil, err
+	}
+	seen := make(map[string]bool, len(repos))
+	for _, r := range repos {
+		seen[r.Name] = true
+	}
+
+	for _, r := range ws.Repos {
+		if r.Path != "" && !filepath.IsAbs(r.Path) {
+			r.Path = filepath.Join(filepath.Dir(path), r.Path)
+		}
+		if seen[r.Name] {
+			skipped = append(skipped, r.Name+": already saved")
+			continue
+		}
+		if problem := entryProblem(r, seen); problem != "" {
+			skipped = append(skipped, fmt.Sprintf("%s: %s", r.Name, problem))
+			continue
+		}
+		seen[r.Name] = true
+		repos = append(repos, r.shared())
+		added = append(added, r.Name)
+	}
+	if len(added) == 0 {
+		return nil, skipped, nil
+	}
+	return added, skipped, rs.saveLocked(repos)
+}
+
+// Background reports whether background mode is on.
+func (rs *RepoStore) Background() (bool, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rl, err := rs.readLocked()
+	return rl.Background, err
+}
+
+// SetBackground turns background mode on or off.
+func (rs *RepoStore) SetBackground(on bool) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rl, err := rs.readLocked()
+	if err != nil {
+		return err
+	}
+	rl.Background = on
+	return rs.writeLocked(rl)
+}
+
+// Update applies fn to the saved entry called name.
+func (rs *RepoStore) Update(name string, fn func(*RepoEntry)) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	repos, err := rs.loadLocked()
+	if err != nil {
+		return err
+	}
+	for i := range repos {
+		if repos[i].Name == name {
+			fn(&repos[i])
+			return rs.saveLocked(repos)
+		}
+	}
+	return fmt.Errorf("repo %q not found", name)
+}
+
+// Get returns the saved entry called name.
+func (rs *RepoStore) Get(name string) (RepoEntry, error) {
+	repos, err := rs.Load()
+	if err != nil {
+		return RepoEntry{}, err
+	}
+	for _, r := range repos {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return RepoEntry{}, fmt.Errorf("repo %q not found", name)
 }
 
 func (rs *RepoStore) Add(entry RepoEntry) error {
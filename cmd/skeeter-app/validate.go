@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// ValidationError reports which task fields were rejected and why, keyed by
+// the field's JSON name.
+type ValidationError struct {
+	Fields map[string]string `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	msgs := make([]string, len(fields))
+	for i, field := range fields {
+		msgs[i] = field + ": " + e.Fields[field]
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...any) {
+	if e.Fields == nil {
+		e.Fields = make(map[string]string)
+	}
+	if _, ok := e.Fields[field]; !ok {
+		e.Fields[field] = fmt.Sprintf(format, args...)
+	}
+}
+
+// formatError is the Wails error formatter. Validation errors reach the
+// frontend as {message, fields} so forms can show each next to its field,
+// conflicts as {message, conflict} and workflow violations as
+// {message, transition}; everything else stays a plain message.
+func formatError(err error) any {
+	var v *ValidationError
+	if errors.As(err, &v) {
+		return map[string]any{"message": v.Error(), "fields": v.Fields}
+	}
+	var c *ConflictError
+	if errors.As(err, &c) {
+		return map[string]any{"message": c.Error(), "conflict": c}
+	}
+	var te *store.TransitionError
+	if errors.As(err, &te) {
+		return map[string]any{"message": te.Error(), "transition": te}
+	}
+	return err.Error()
+}
+
+// validateTask checks t against s's config and tasks the way the CLI does,
+// including that its dependencies exist and don't form a cycle.
+func validateTask(s store.Store, t *task.Task) error {
+	cfg := s.GetConfig()
+	v := &ValidationError{}
+
+	if strings.TrimSpace(t.Title) == "" {
+		v.add("title", "title is required")
+	}
+	if !cfg.ValidStatus(t.Status) {
+		v.add("status", "invalid status %q (valid: %s)", t.Status, strings.Join(cfg.Statuses, ", "))
+	}
+	if !cfg.ValidPriority(t.Priority) {
+		v.add("priority", "invalid priority %q (valid: %s)", t.Priority, strings.Join(cfg.Priorities, ", "))
+	}
+	if t.Due != "" {
+		if _, err := time.Parse("2006-01-02", t.Due); err != nil {
+			v.add("due", "invalid due date %q (format: YYYY-MM-DD)", t.Due)
+		}
+	}
+
+	for _, depID := range t.DependsOn {
+		if depID == t.ID {
+			v.add("depends_on", "a task cannot depend on itself")
+			continue
+		}
+		if _, err := s.Get(depID); err != nil {
+			v.add("depends_on", "dependency task %q not found", depID)
+		}
+	}
+	if _, ok := v.Fields["depends_on"]; !ok && len(t.DependsOn) > 0 {
+		if cycle, _ := store.DetectCircularDependency(t, s); len(cycle) > 0 {
+			v.add("depends_on", "circular dependency detected: %s -> %s", strings.Join(cycle, " -> "), t.ID)
+		}
+	}
+
+	if len(v.Fields) > 0 {
+		return v
+	}
+	return nil
+}
+
+// cleanList trims entries and drops empty ones, uppercasing them when they
+// are task IDs.
+func cleanList(items []string, ids bool) task.FlowSlice {
+	var out task.FlowSlice
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if ids {
+			item = strings.ToUpper(item)
+		}
+		out = append(out, item)
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package main
+
+import "github.com/wailsapp/wails/v2/pkg/runtime"
+
+// workspaceFilter matches workspace files in the open and save dialogs.
+var workspaceFilter = []runtime.FileFilter{{DisplayName: "Skeeter workspace (*.json)", Pattern: "*.json"}}
+
+// ImportResult reports which repos an import added and which it skipped,
+// with the reason.
+type ImportResult struct {
+	Added   []string `json:"added"`
+	Skipped []string `json:"skipped"`
+}
+
+// ExportRepos saves the repo list to a workspace file the user picks, so a
+// team can share a standard set of repos. Personal settings such as
+// notifications and board state are left out. It returns the path written,
+// or "" if the user cancelled.
+func (a *App) ExportRepos() (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export repos",
+		DefaultFilename: "skeeter-workspace.json",
+		Filters:         workspaceFilter,
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	return path, a.repoStore.Export(path)
+}
+
+// ImportRepos adds the repos of a workspace file the user picks. It returns
+// nil if the user cancelled.
+func (a *App) ImportRepos() (*ImportResult, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "Import repos",
+		Filters: workspaceFilter,
+	})
+	if err != nil || path == "" {
+		return nil, err
+	}
+	added, skipped, err := a.repoStore.Import(path)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	hasStore := a.store != nil
+	aggregated := a.repos != nil
+	a.mu.RUnlock()
+	switch {
+	case aggregated:
+		_, err = a.SwitchRepo(AllRepos)
+	case !hasStore && len(added) > 0:
+		_, err = a.SwitchRepo(added[0])
+	}
+	return &ImportResult{Added: added, Skipped: skipped}, err
+}
+
+// GetUIState returns the board state saved for the repo called name.
+func (a *App) GetUIState(name string) (*RepoUIState, error) {
+	return a.repoStore.UIState(name)
+}
+
+// SaveUIState saves the board state of the repo called name, to be restored
+// when it is next switched to.
+func (a *App) SaveUIState(name string, state RepoUIState) error {
+	return a.repoStore.SetUIState(name, &state)
+}
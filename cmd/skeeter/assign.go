@@ -4,14 +4,20 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/andybarilla/skeeter/internal/store"
 	"github.com/spf13/cobra"
 )
 
+var assignForce bool
+
 var assignCmd = &cobra.Command{
 	Use:   "assign <id> <assignee>",
 	Short: "Assign a task",
-	Long:  "Assign a task to a person or agent. Use empty string to unassign.",
-	Args:  cobra.RangeArgs(1, 2),
+	Long: `Assign a task to a person or agent. Use empty string to unassign.
+
+Assigning a task that is in flight counts toward the new assignee's
+wip_limits.per_assignee; --force assigns past the limit.`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		s, err := openStore()
 		if err != nil {
@@ -28,7 +34,9 @@ var assignCmd = &cobra.Command{
 			assignee = args[1]
 		}
 
-		t.Assignee = assignee
+		if err := store.ChangeAssignee(s, t, assignee, assignForce); err != nil {
+			return withForceHint(err, "--force")
+		}
 
 		if err := s.Update(t); err != nil {
 			return err
@@ -44,5 +52,6 @@ var assignCmd = &cobra.Command{
 }
 
 func init() {
+	assignCmd.Flags().BoolVar(&assignForce, "force", false, "assign past the per-assignee WIP limit")
 	rootCmd.AddCommand(assignCmd)
 }
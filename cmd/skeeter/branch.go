@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/andybarilla/skeeter/internal/gitinfo"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var branchNoCheckout bool
+
+var branchCmd = &cobra.Command{
+	Use:   "branch <id>",
+	Short: "Create and check out a git branch for a task",
+	Long: `Create a branch for a task in the repository holding .skeeter, check it out and
+record it in the task's branch field. A task that already has a branch is
+switched back to it. 'skeeter show' then reports how the branch compares
+to main.
+
+Branch names come from branches.pattern in config.yaml, a Go template with
+the functions id, title, assignee, priority, slug, lower and upper:
+
+  branches:
+    pattern: "{{id}}-{{slug title}}"      # the default: US-012-add-login-form
+    main: main                            # default: origin's HEAD, main or master`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+		fs, ok := s.(*store.FilesystemStore)
+		if !ok {
+			return fmt.Errorf("branch only works on a local .skeeter directory")
+		}
+		repoDir := filepath.Dir(fs.Dir)
+		t, err := s.Get(strings.ToUpper(args[0]))
+		if err != nil {
+			return err
+		}
+
+		name := t.Branch
+		if name == "" {
+			if name, err = gitinfo.BranchName(s.GetConfig().Branches.Pattern, t); err != nil {
+				return err
+			}
+		}
+		existed := gitinfo.BranchExists(repoDir, name)
+		if err := gitinfo.Checkout(repoDir, name, !branchNoCheckout); err != nil {
+			return err
+		}
+		if t.Branch != name {
+			t.Branch = name
+			if err := s.Update(t); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case !existed && branchNoCheckout:
+			fmt.Printf("Created branch %s for %s\n", name, t.ID)
+		case !existed:
+			fmt.Printf("Switched to a new branch %s for %s\n", name, t.ID)
+		case branchNoCheckout:
+			fmt.Printf("%s already has branch %s\n", t.ID, name)
+		default:
+			fmt.Printf("Switched to branch %s for %s\n", name, t.ID)
+		}
+		return nil
+	},
+}
+
+func init() {
+	branchCmd.Flags().BoolVar(&branchNoCheckout, "no-checkout", false, "create the branch without switching to it")
+	rootCmd.AddCommand(branchCmd)
+}
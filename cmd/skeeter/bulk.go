@@ -6,12 +6,19 @@ import (
 	"os"
 	"strings"
 
+	"github.com/andybarilla/skeeter/internal/store"
 	"github.com/spf13/cobra"
 )
 
 var (
 	bulkFromFile string
 	bulkForce    bool
+	// bulkStatusOverride overrides the workflow, like status --force; bulk's
+	// own --force only skips the prompt.
+	bulkStatusOverride bool
+	// bulkAssignOverride assigns past the per-assignee WIP limit, like
+	// assign --force.
+	bulkAssignOverride bool
 )
 
 var bulkCmd = &cobra.Command{
@@ -58,7 +65,10 @@ var bulkStatusCmd = &cobra.Command{
 				fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", id, err)
 				continue
 			}
-			t.Status = newStatus
+			if err := store.ChangeStatus(s, t, newStatus, bulkStatusOverride); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", withForceHint(err, "--override"))
+				continue
+			}
 			if err := s.Update(t); err != nil {
 				fmt.Fprintf(os.Stderr, "Error updating %s: %v\n", id, err)
 				continue
@@ -103,7 +113,10 @@ var bulkAssignCmd = &cobra.Command{
 				fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", id, err)
 				continue
 			}
-			t.Assignee = assignee
+			if err := store.ChangeAssignee(s, t, assignee, bulkAssignOverride); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", withForceHint(err, "--override"))
+				continue
+			}
 			if err := s.Update(t); err != nil {
 				fmt.Fprintf(os.Stderr, "Error updating %s: %v\n", id, err)
 				continue
@@ -204,6 +217,9 @@ func init() {
 	bulkCmd.PersistentFlags().StringVar(&bulkFromFile, "from-file", "", "read task IDs from file (one per line)")
 	bulkCmd.PersistentFlags().BoolVar(&bulkForce, "force", false, "skip confirmation prompt")
 
+	bulkAssignCmd.Flags().BoolVar(&bulkAssignOverride, "override", false, "assign past the per-assignee WIP limit")
+	bulkStatusCmd.Flags().BoolVar(&bulkStatusOverride, "override", false, "override workflow transition rules and guards")
+
 	bulkCmd.AddCommand(bulkStatusCmd)
 	bulkCmd.AddCommand(bulkAssignCmd)
 	bulkCmd.AddCommand(bulkPriorityCmd)
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/changelog"
+	"github.com/andybarilla/skeeter/internal/gitinfo"
+	"github.com/andybarilla/skeeter/internal/llm"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+	"github.com/spf13/cobra"
+)
+
+var (
+	changelogSince    string
+	changelogUntil    string
+	changelogFormat   string
+	changelogTemplate string
+	changelogRelease  string
+	changelogLLM      bool
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Generate release notes from completed tasks",
+	Long: `Collect the tasks that moved to the last status between two git refs or
+dates and render them as release notes.
+
+With git refs, a task is included when a commit in since..until changed its
+file's status to the last one; --until defaults to HEAD, so commit the
+board first. With YYYY-MM-DD dates, tasks are picked by the day their
+history says they were done, from --since up to and including --until,
+which defaults to today. The two ends must be both refs or both dates.
+
+Tasks are grouped by their type, which 'create -T <template>' records, or
+else by their tags. The default groups follow keepachangelog.com (Added,
+Changed, Deprecated, Removed, Fixed, Security), with anything unmatched
+under Other; change them in config.yaml:
+
+  changelog:
+    format: keep-a-changelog
+    groups:
+      - title: Features
+        match: [feature, story]
+      - title: Bug Fixes
+        match: [bug]
+
+Commit SHAs in a task's links, as the git hooks and scan-commits record
+them, are listed with it. --template renders with your own Go template
+instead of a built-in format.
+
+  skeeter changelog --since v1.4.0 --release 1.5.0 >> CHANGELOG.md
+  skeeter changelog --since 2026-01-01 --until 2026-03-31 --llm`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+		cfg := s.GetConfig()
+		format := changelogFormat
+		if format == "" {
+			format = cfg.Changelog.Format
+		}
+		if format != "" && !slices.Contains(changelog.Formats, format) {
+			return fmt.Errorf("unknown changelog format %q (valid: %s)", format, strings.Join(changelog.Formats, ", "))
+		}
+
+		done := cfg.DoneStatus()
+		if done == "" {
+			return fmt.Errorf("config.yaml lists no statuses, so no task can be done")
+		}
+		tasks, err := s.List(store.Filter{})
+		if err != nil {
+			return err
+		}
+
+		var r changelog.Range
+		var date string
+		if isRef(changelogSince) || isRef(changelogUntil) {
+			if tasks, date, err = changelogTasksBetweenRefs(s, tasks, done); err != nil {
+				return err
+			}
+		} else {
+			r.From, r.To = changelogSince, changelogUntil
+			if r.To == "" {
+				r.To = time.Now().Format("2006-01-02")
+			}
+			date = r.To
+		}
+
+		c := &changelog.Changelog{
+			Project: cfg.Project.Name,
+			Release: changelogRelease,
+			Range:   r,
+			Groups:  changelog.Collect(tasks, done, r, cfg.ChangelogGroups()),
+		}
+		if c.Release == "" {
+			c.Release = changelog.Unreleased
+		} else {
+			c.Date = date
+		}
+
+		if changelogLLM {
+			for i := range c.Groups {
+				fmt.Fprintf(os.Stderr, "Summarizing %s...\n", c.Groups[i].Title)
+				summary, err := llm.SummarizeGroup(context.Background(), cfg, c.Groups[i])
+				if err != nil {
+					return fmt.Errorf("summarize failed: %w", err)
+				}
+				c.Groups[i].Summary = summary
+			}
+		}
+
+		if isJSONOutput() {
+			return outputJSON(c)
+		}
+		if isYAMLOutput() {
+			return outputYAML(c)
+		}
+		if changelogTemplate != "" {
+			text, err := os.ReadFile(changelogTemplate)
+			if err != nil {
+				return err
+			}
+			return changelog.RenderTemplate(os.Stdout, c, string(text))
+		}
+		return changelog.Render(os.Stdout, c, format)
+	},
+}
+
+// isRef reports whether a --since or --until value names a git ref rather
+// than a YYYY-MM-DD date.
+func isRef(value string) bool {
+	if value == "" {
+		return false
+	}
+	_, err := time.Parse("2006-01-02", value)
+	return err != nil
+}
+
+// changelogTasksBetweenRefs keeps the tasks whose files a commit between
+// --since and --until moved to done, and returns them with the date of the
+// --until commit. --until defaults to HEAD, and without --since the whole
+// history up to it counts.
+func changelogTasksBetweenRefs(s store.Store, tasks []task.Task, done string) ([]task.Task, string, error) {
+	fs, ok := s.(*store.FilesystemStore)
+	if !ok {
+		return nil, "", fmt.Errorf("git refs need a board in a local repository; use YYYY-MM-DD dates instead")
+	}
+	if changelogSince != "" && !isRef(changelogSince) || changelogUntil != "" && !isRef(changelogUntil) {
+		return nil, "", fmt.Errorf("--since and --until must both be git refs or both be dates")
+	}
+	until := changelogUntil
+	if until == "" {
+		until = "HEAD"
+	}
+
+	repoDir := filepath.Dir(fs.Dir)
+	moved, err := gitinfo.MovedTo(repoDir, filepath.Base(fs.Dir)+"/tasks", done, changelogSince, until)
+	if err != nil {
+		return nil, "", err
+	}
+	date, err := gitinfo.CommitDate(repoDir, until)
+	if err != nil {
+		return nil, "", err
+	}
+	return slices.DeleteFunc(tasks, func(t task.Task) bool { return !moved[t.ID] }), date, nil
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogSince, "since", "", "git ref to start after, or YYYY-MM-DD date to start from")
+	changelogCmd.Flags().StringVar(&changelogUntil, "until", "", "git ref or YYYY-MM-DD date to end at (default: HEAD for refs, today for dates)")
+	changelogCmd.Flags().StringVar(&changelogFormat, "format", "", "output format: markdown, keep-a-changelog (default: changelog.format, or markdown)")
+	changelogCmd.Flags().StringVar(&changelogTemplate, "template", "", "Go template file to render instead of a built-in format")
+	changelogCmd.Flags().StringVar(&changelogRelease, "release", "", "release name for the heading (default: Unreleased)")
+	changelogCmd.Flags().BoolVar(&changelogLLM, "llm", false, "summarize each group with the configured LLM tool")
+	rootCmd.AddCommand(changelogCmd)
+}
@@ -2,11 +2,20 @@ package main
 
 import (
 	"bytes"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/store"
 	"github.com/spf13/cobra"
 )
 
@@ -300,6 +309,37 @@ func TestAssignCommand(t *testing.T) {
 	if !strings.Contains(string(content), "assignee: claude") {
 		t.Error("task file not updated with assignee")
 	}
+
+	// claude holds US-001 in flight; a second task would pass the limit.
+	s, _ := store.NewFilesystem(".skeeter")
+	s.UpdateConfig(func(cfg *config.Config) error {
+		cfg.WIPLimits.PerAssignee = 1
+		return nil
+	})
+	executeCommand(rootCmd, "status", "US-001", "in-progress")
+	executeCommand(rootCmd, "create", "Second Task", "-s", "in-progress")
+	createStatus = ""
+	if _, _, err := executeCommand(rootCmd, "assign", "US-002", "claude"); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("assign past the per-assignee limit = %v, want a refusal", err)
+	}
+	if _, _, err := executeCommand(rootCmd, "assign", "US-002", "claude", "--force"); err != nil {
+		t.Errorf("assign --force: %v", err)
+	}
+	assignForce = false
+
+	// bulk assign's --force only skips the prompt.
+	executeCommand(rootCmd, "create", "Third Task", "-s", "in-progress")
+	createStatus = ""
+	executeCommand(rootCmd, "bulk", "assign", "claude", "US-003", "--force")
+	bulkForce = false
+	if got, _ := s.Get("US-003"); got.Assignee != "" {
+		t.Errorf("bulk assign --force assigned past the limit")
+	}
+	executeCommand(rootCmd, "bulk", "assign", "claude", "US-003", "--override")
+	bulkAssignOverride = false
+	if got, _ := s.Get("US-003"); got.Assignee != "claude" {
+		t.Errorf("bulk assign --override: assignee %q, want claude", got.Assignee)
+	}
 }
 
 func TestNextCommandNoTasks(t *testing.T) {
@@ -408,3 +448,835 @@ func TestConfigSetCommand(t *testing.T) {
 		t.Error("config not updated with new prefix")
 	}
 }
+
+func TestConfigSetCommandInvalidKey(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	_, _, err := executeCommand(rootCmd, "init", "test")
+	if err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	_, _, err = executeCommand(rootCmd, "config", "set", "nope", "x")
+	if err == nil {
+		t.Error("expected error for unknown config key")
+	}
+
+	content, _ := os.ReadFile(filepath.Join(".skeeter", "SKEETER.md"))
+	if !strings.Contains(string(content), "US-001") {
+		t.Error("SKEETER.md should be unchanged after a failed config set")
+	}
+}
+
+func TestDoctorCommand(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	_, _, err := executeCommand(rootCmd, "init", "test")
+	if err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	_, _, err = executeCommand(rootCmd, "create", "Healthy task")
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	_, _, err = executeCommand(rootCmd, "doctor")
+	if err != nil {
+		t.Fatalf("doctor on a clean repo failed: %v", err)
+	}
+
+	broken := "---\nid: US-002\ntitle: Broken\nstatus: In Progress\n---\n"
+	os.WriteFile(filepath.Join(".skeeter", "tasks", "US-002.md"), []byte(broken), 0644)
+
+	_, _, err = executeCommand(rootCmd, "doctor")
+	if err == nil {
+		t.Fatal("expected doctor to fail with an invalid status")
+	}
+
+	_, _, err = executeCommand(rootCmd, "doctor", "--fix")
+	if err != nil {
+		t.Fatalf("doctor --fix failed: %v", err)
+	}
+	doctorFix = false
+
+	content, _ := os.ReadFile(filepath.Join(".skeeter", "tasks", "US-002.md"))
+	if !strings.Contains(string(content), "status: in-progress") {
+		t.Errorf("status not normalized:\n%s", content)
+	}
+}
+
+func TestInstallMergeDriverCommand(t *testing.T) {
+	repoDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	if out, err := exec.Command("git", "init", "-q", repoDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	for range 2 {
+		if _, _, err := executeCommand(rootCmd, "install-merge-driver"); err != nil {
+			t.Fatalf("install-merge-driver failed: %v", err)
+		}
+	}
+
+	out, err := exec.Command("git", "config", "merge.skeeter.driver").Output()
+	if err != nil || strings.TrimSpace(string(out)) != "skeeter merge-driver %O %A %B" {
+		t.Errorf("merge.skeeter.driver = %q, %v", out, err)
+	}
+	attrs, _ := os.ReadFile(filepath.Join(".skeeter", ".gitattributes"))
+	if strings.Count(string(attrs), mergeAttribute) != 1 {
+		t.Errorf(".gitattributes = %q, want the merge rule once", attrs)
+	}
+}
+
+func TestRankCommand(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	_, _, err := executeCommand(rootCmd, "init", "test")
+	if err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	for _, title := range []string{"First", "Second", "Third"} {
+		if _, _, err := executeCommand(rootCmd, "create", title, "-p", "high"); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	}
+	createPriority = ""
+
+	_, _, err = executeCommand(rootCmd, "rank", "us-003", "--before", "us-001")
+	rankBefore = ""
+	if err != nil {
+		t.Fatalf("rank failed: %v", err)
+	}
+
+	s, err := store.NewFilesystem(".skeeter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, _ := s.List(store.Filter{})
+	store.SortTasks(tasks, s.GetConfig())
+	var order []string
+	for _, tk := range tasks {
+		order = append(order, tk.ID)
+	}
+	if strings.Join(order, ",") != "US-003,US-001,US-002" {
+		t.Errorf("order = %v, want US-003 first", order)
+	}
+
+	_, _, err = executeCommand(rootCmd, "rank", "US-001")
+	if err == nil {
+		t.Error("expected error without --before or --after")
+	}
+}
+
+func TestEditTaskThroughCopy(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, _, err := executeCommand(rootCmd, "create", "Original"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	script := "#!/bin/sh\nsed -i 's/^title: .*/title: Edited/' \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", editor)
+
+	fs, err := store.NewFilesystem(".skeeter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Hide the concrete type so the task is edited the way remote tasks are.
+	s := struct{ store.Store }{fs}
+	if err := editTask(s, "US-001"); err != nil {
+		t.Fatalf("editTask: %v", err)
+	}
+
+	got, err := fs.Get("US-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Edited" {
+		t.Errorf("title = %q, want Edited", got.Title)
+	}
+}
+
+func TestTUIRequiresTerminal(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	_, _, err := executeCommand(rootCmd, "tui")
+	if err == nil || !strings.Contains(err.Error(), "interactive terminal") {
+		t.Errorf("err = %v, want an interactive terminal error", err)
+	}
+}
+
+// captureStdout returns what fn writes to os.Stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	fn()
+	w.Close()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestGraphAndPlanOrderCommands(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	for _, args := range [][]string{
+		{"create", "Schema"},
+		{"create", "API", "-d", "US-001"},
+		{"create", "Документация для каждого модуля и интерфейса"},
+		{"create", "Launch", "-d", "US-002,US-003"},
+	} {
+		if _, _, err := executeCommand(rootCmd, args...); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+	}
+	createDepends = ""
+
+	var err error
+	out := captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "graph", "--format", "mermaid", "--epic", "us-002")
+	})
+	graphFlags = graphScope{}
+	graphFormat = "dot"
+	if err != nil {
+		t.Fatalf("graph failed: %v", err)
+	}
+	if !strings.Contains(out, "US_001 --> US_002") || strings.Contains(out, "US_004") {
+		t.Errorf("graph --epic US-002 = %s", out)
+	}
+
+	out = captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "plan-order", "-o", "json")
+	})
+	outputFlag = "table"
+	if err != nil {
+		t.Fatalf("plan-order failed: %v", err)
+	}
+	if !strings.Contains(out, `"critical_path": [
+    "US-001",
+    "US-002",
+    "US-004"
+  ]`) {
+		t.Errorf("plan-order critical path:\n%s", out)
+	}
+
+	// Long titles are cut by character, not byte.
+	out = captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "plan-order")
+	})
+	if err != nil {
+		t.Fatalf("plan-order failed: %v", err)
+	}
+	if !utf8.ValidString(out) || !strings.Contains(out, "Документация для каждого модуля и инт...") {
+		t.Errorf("plan-order table:\n%s", out)
+	}
+
+	_, _, err = executeCommand(rootCmd, "graph", "--task", "US-042")
+	graphFlags = graphScope{}
+	if err == nil {
+		t.Error("expected an error for an unknown task")
+	}
+}
+
+func TestDepsCheckCommand(t *testing.T) {
+	repoDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	for _, args := range [][]string{
+		{"create", "Schema"},
+		{"create", "API", "-d", "US-001"},
+	} {
+		if _, _, err := executeCommand(rootCmd, args...); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+	}
+	createDepends = ""
+
+	var err error
+	out := captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "deps", "check")
+	})
+	if err != nil || !strings.Contains(out, "No dependency problems found.") {
+		t.Fatalf("deps check on a clean set = %q, %v", out, err)
+	}
+
+	// Hand-edit a cycle and a dangling dependency into the files, which
+	// create would have refused.
+	path := filepath.Join(repoDir, ".skeeter", "tasks", "US-001.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(data), "status:", "depends_on: [US-002, US-009]\nstatus:", 1)
+	if err := os.WriteFile(path, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out = captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "deps", "check")
+	})
+	if err == nil {
+		t.Fatal("expected deps check to fail")
+	}
+	for _, want := range []string{"circular dependency: US-001 -> US-002 -> US-001", "depends on US-009, which does not exist"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("deps check output missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, _, err := executeCommand(rootCmd, "create", "Loop", "-d", "US-003"); err == nil {
+		t.Error("expected create to refuse a self-dependency")
+	}
+	createDepends = ""
+}
+
+func TestDepsAddRmShowCommands(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	for _, title := range []string{"Schema", "API", "Launch", "Launch v2"} {
+		if _, _, err := executeCommand(rootCmd, "create", title); err != nil {
+			t.Fatalf("create %s failed: %v", title, err)
+		}
+	}
+
+	run := func(args ...string) (string, error) {
+		var err error
+		out := captureStdout(t, func() {
+			_, _, err = executeCommand(rootCmd, args...)
+		})
+		depsType = "depends_on"
+		depsTransitive = false
+		outputFlag = "table"
+		return out, err
+	}
+
+	if _, err := run("deps", "add", "us-002", "US-001"); err != nil {
+		t.Fatalf("deps add failed: %v", err)
+	}
+	if _, err := run("deps", "add", "US-003", "US-002"); err != nil {
+		t.Fatalf("deps add failed: %v", err)
+	}
+	if _, err := run("deps", "add", "US-001", "US-003"); err == nil {
+		t.Error("expected deps add to refuse a cycle")
+	}
+	if _, err := run("deps", "add", "US-003", "US-042"); err == nil {
+		t.Error("expected deps add to refuse an unknown task")
+	}
+	if _, err := run("deps", "add", "US-004", "US-003", "--type", "duplicates"); err != nil {
+		t.Fatalf("deps add --type duplicates failed: %v", err)
+	}
+
+	out, err := run("deps", "show", "US-003", "--transitive")
+	if err != nil {
+		t.Fatalf("deps show failed: %v", err)
+	}
+	for _, want := range []string{"\n  US-002 API [", "\n    US-001 Schema [", "Duplicated by: US-004"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("deps show missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = run("show", "US-003")
+	if err != nil || !strings.Contains(out, "Duplicated by: US-004") {
+		t.Errorf("show US-003 = %q, %v; want a duplicated-by backlink", out, err)
+	}
+
+	if _, err := run("deps", "rm", "US-003", "US-002"); err != nil {
+		t.Fatalf("deps rm failed: %v", err)
+	}
+	if _, err := run("deps", "rm", "US-003", "US-002"); err == nil {
+		t.Error("expected deps rm to fail for a dependency that isn't there")
+	}
+	out, _ = run("deps", "show", "US-003", "-o", "json")
+	if !strings.Contains(out, `"depends_on": []`) {
+		t.Errorf("deps show after rm:\n%s", out)
+	}
+}
+
+func TestWorkDryRunLeavesTaskAlone(t *testing.T) {
+	repoDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, _, err := executeCommand(rootCmd, "config", "set", "llm.tool", "claude"); err != nil {
+		t.Fatalf("config set failed: %v", err)
+	}
+	if _, _, err := executeCommand(rootCmd, "create", "Ready task", "-s", "ready-for-development"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	createStatus = ""
+	s, err := store.NewFilesystem(filepath.Join(repoDir, ".skeeter"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, _ := s.Get("US-001")
+
+	var runErr error
+	out := captureStdout(t, func() {
+		_, _, runErr = executeCommand(rootCmd, "work", "--dry-run")
+	})
+	workDryRun = false
+	if runErr != nil {
+		t.Fatalf("work --dry-run failed: %v", runErr)
+	}
+	if !strings.Contains(out, "Ready task") {
+		t.Errorf("output = %q, want the task's prompt", out)
+	}
+
+	after, _ := s.Get("US-001")
+	if after.Status != before.Status || after.Assignee != "" || len(after.History) != len(before.History) {
+		t.Errorf("dry run changed the task: %+v", after)
+	}
+}
+
+func TestBulkStatusOverride(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	executeCommand(rootCmd, "create", "First", "-s", "backlog")
+	executeCommand(rootCmd, "create", "Second", "-s", "backlog")
+	createStatus = ""
+	s, _ := store.NewFilesystem(".skeeter")
+	s.UpdateConfig(func(cfg *config.Config) error {
+		cfg.Workflow.Transitions = map[string][]string{"backlog": {"ready-for-development"}}
+		return nil
+	})
+
+	statuses := func() []string {
+		s, _ := store.NewFilesystem(".skeeter")
+		var got []string
+		for _, id := range []string{"US-001", "US-002"} {
+			tk, _ := s.Get(id)
+			got = append(got, tk.Status)
+		}
+		return got
+	}
+
+	// --force only skips the prompt; the workflow still applies.
+	_, _, err := executeCommand(rootCmd, "bulk", "status", "in-progress", "US-001", "US-002", "--force")
+	bulkForce = false
+	if err != nil {
+		t.Fatalf("bulk status --force: %v", err)
+	}
+	if got := statuses(); !slices.Equal(got, []string{"backlog", "backlog"}) {
+		t.Errorf("after bulk status --force: %v, want the moves refused", got)
+	}
+
+	_, _, err = executeCommand(rootCmd, "bulk", "status", "in-progress", "US-001", "US-002", "--override")
+	bulkStatusOverride = false
+	if err != nil {
+		t.Fatalf("bulk status --override: %v", err)
+	}
+	if got := statuses(); !slices.Equal(got, []string{"in-progress", "in-progress"}) {
+		t.Errorf("after bulk status --override: %v, want both moved", got)
+	}
+}
+
+func TestStatusFollowsWorkflow(t *testing.T) {
+	repoDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, _, err := executeCommand(rootCmd, "create", "Task", "-s", "backlog"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	createStatus = ""
+
+	path := filepath.Join(repoDir, ".skeeter", "config.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	workflow := "workflow:\n  transitions:\n    backlog: [ready-for-development]\n  guards:\n    in-progress: [assignee]\n"
+	if err := os.WriteFile(path, append(data, workflow...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = executeCommand(rootCmd, "status", "US-001", "in-progress")
+	if err == nil || !strings.Contains(err.Error(), "backlog can only move to ready-for-development") ||
+		!strings.Contains(err.Error(), "requires an assignee") {
+		t.Fatalf("status without --force = %v", err)
+	}
+
+	_, _, err = executeCommand(rootCmd, "status", "US-001", "in-progress", "--force")
+	statusForce = false
+	if err != nil {
+		t.Fatalf("status --force failed: %v", err)
+	}
+	s, err := store.NewFilesystem(filepath.Join(repoDir, ".skeeter"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Get("US-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != "in-progress" || len(got.History) != 1 || got.History[0].Forced == "" {
+		t.Errorf("after --force: status %q, history %+v", got.Status, got.History)
+	}
+
+	// Starting a task past the first status is a move like any other.
+	_, _, err = executeCommand(rootCmd, "create", "Started", "-s", "in-progress")
+	createStatus = ""
+	if err == nil || !strings.Contains(err.Error(), "backlog can only move to ready-for-development") {
+		t.Fatalf("create -s in-progress = %v", err)
+	}
+	_, _, err = executeCommand(rootCmd, "create", "Started", "-s", "in-progress", "--force")
+	createStatus, createForce = "", false
+	if err != nil {
+		t.Fatalf("create --force failed: %v", err)
+	}
+}
+
+func TestWebhooksCommands(t *testing.T) {
+	repoDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	var up atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	s, err := store.NewFilesystem(filepath.Join(repoDir, ".skeeter"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.UpdateConfig(func(cfg *config.Config) error {
+		cfg.Webhooks = []config.Webhook{{URL: srv.URL}}
+		return nil
+	})
+	if _, _, err := executeCommand(rootCmd, "create", "Thing"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "webhooks")
+	})
+	if err != nil || !strings.Contains(out, "create") || !strings.Contains(out, "502") {
+		t.Fatalf("webhooks = %q, %v, want the queued create", out, err)
+	}
+
+	up.Store(true)
+	out = captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "webhooks", "flush")
+	})
+	if err != nil || !strings.Contains(out, "No pending webhook deliveries.") {
+		t.Errorf("webhooks flush = %q, %v", out, err)
+	}
+}
+
+func TestScanCommitsAndGitHooks(t *testing.T) {
+	repoDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	for _, kv := range [][2]string{
+		{"GIT_AUTHOR_NAME", "Test"}, {"GIT_AUTHOR_EMAIL", "test@example.com"},
+		{"GIT_COMMITTER_NAME", "Test"}, {"GIT_COMMITTER_EMAIL", "test@example.com"},
+		{gitHookEnv, ""},
+	} {
+		t.Setenv(kv[0], kv[1])
+	}
+	git := func(args ...string) {
+		t.Helper()
+		c := exec.Command("git", args...)
+		c.Dir = repoDir
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	for _, title := range []string{"Login", "Logout", "Profile"} {
+		if _, _, err := executeCommand(rootCmd, "create", title); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	}
+	git("add", "-A")
+	git("commit", "-q", "-m", "Add tasks")
+	git("commit", "-q", "--allow-empty", "-m", "Start on the login form (US-001)")
+	git("commit", "-q", "--allow-empty", "-m", "Finish logout\n\nFixes US-002, refs US-009")
+
+	var err error
+	out := captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "scan-commits", "HEAD")
+	})
+	if err != nil {
+		t.Fatalf("scan-commits failed: %v", err)
+	}
+	if !strings.Contains(out, "US-001: linked, backlog -> in-progress") ||
+		!strings.Contains(out, "US-002: linked, backlog -> done") || strings.Contains(out, "US-009") {
+		t.Errorf("scan-commits output = %q", out)
+	}
+	s, _ := store.NewFilesystem(filepath.Join(repoDir, ".skeeter"))
+	if tk, _ := s.Get("US-002"); tk.Status != "done" || len(tk.Links) != 1 || len(tk.Links[0]) != 40 {
+		t.Errorf("US-002 = %s %v, want done and linked to the commit", tk.Status, tk.Links)
+	}
+
+	// Scanning again finds nothing new.
+	out = captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "scan-commits", "HEAD")
+	})
+	if err != nil || !strings.Contains(out, "no task changes") {
+		t.Errorf("second scan-commits = %q, %v", out, err)
+	}
+
+	if _, _, err := executeCommand(rootCmd, "hooks", "install"); err != nil {
+		t.Fatalf("hooks install failed: %v", err)
+	}
+	script, err := os.ReadFile(filepath.Join(repoDir, ".git", "hooks", "post-commit"))
+	if err != nil || !strings.Contains(string(script), "skeeter hooks run post-commit") {
+		t.Fatalf("post-commit hook = %q, %v", script, err)
+	}
+
+	// Run the hook the way git would, without needing skeeter on PATH.
+	git("-c", "core.hooksPath=/dev/null", "commit", "-q", "--allow-empty", "-m", "Closes US-003")
+	captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "hooks", "run", "post-commit")
+	})
+	if err != nil {
+		t.Fatalf("hooks run post-commit failed: %v", err)
+	}
+	if tk, _ := s.Get("US-003"); tk.Status != "done" {
+		t.Errorf("US-003 status = %q, want done", tk.Status)
+	}
+}
+
+func TestBranchCommand(t *testing.T) {
+	repoDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	for _, kv := range [][2]string{
+		{"GIT_AUTHOR_NAME", "Test"}, {"GIT_AUTHOR_EMAIL", "test@example.com"},
+		{"GIT_COMMITTER_NAME", "Test"}, {"GIT_COMMITTER_EMAIL", "test@example.com"},
+	} {
+		t.Setenv(kv[0], kv[1])
+	}
+	git := func(args ...string) string {
+		t.Helper()
+		c := exec.Command("git", args...)
+		c.Dir = repoDir
+		out, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q", "-b", "main")
+
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, _, err := executeCommand(rootCmd, "create", "Add login form"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	git("add", "-A")
+	git("commit", "-q", "-m", "Add tasks")
+
+	var err error
+	out := captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "branch", "us-001")
+	})
+	if err != nil {
+		t.Fatalf("branch failed: %v", err)
+	}
+	if !strings.Contains(out, "Switched to a new branch US-001-add-login-form") {
+		t.Errorf("branch output = %q", out)
+	}
+	if current := git("branch", "--show-current"); current != "US-001-add-login-form" {
+		t.Errorf("current branch = %q", current)
+	}
+	s, _ := store.NewFilesystem(filepath.Join(repoDir, ".skeeter"))
+	if tk, _ := s.Get("US-001"); tk.Branch != "US-001-add-login-form" {
+		t.Errorf("task branch = %q", tk.Branch)
+	}
+
+	git("commit", "-q", "-am", "Build the form\n\nRefs US-001")
+	out = captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "show", "US-001")
+	})
+	if err != nil {
+		t.Fatalf("show failed: %v", err)
+	}
+	if !strings.Contains(out, "Branch: US-001-add-login-form (1 ahead, 0 behind main)") ||
+		!strings.Contains(out, "Build the form") {
+		t.Errorf("show output = %q", out)
+	}
+
+	// Running it again switches back to the recorded branch.
+	git("switch", "-q", "main")
+	captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "branch", "US-001")
+	})
+	if err != nil || git("branch", "--show-current") != "US-001-add-login-form" {
+		t.Errorf("branch on an existing branch: %v", err)
+	}
+}
+
+func TestChangelogCommand(t *testing.T) {
+	repoDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+	for _, kv := range [][2]string{
+		{"GIT_AUTHOR_NAME", "Test"}, {"GIT_AUTHOR_EMAIL", "test@example.com"},
+		{"GIT_COMMITTER_NAME", "Test"}, {"GIT_COMMITTER_EMAIL", "test@example.com"},
+	} {
+		t.Setenv(kv[0], kv[1])
+	}
+	git := func(args ...string) {
+		t.Helper()
+		c := exec.Command("git", args...)
+		c.Dir = repoDir
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+
+	if _, _, err := executeCommand(rootCmd, "init", "test"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	os.WriteFile(filepath.Join(repoDir, ".skeeter", "templates", "bug.md"), []byte("## Steps\n"), 0644)
+	if _, _, err := executeCommand(rootCmd, "create", "Login form", "-t", "feature"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, _, err := executeCommand(rootCmd, "create", "Crash on logout", "-T", "bug"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	createTemplate = ""
+	if _, _, err := executeCommand(rootCmd, "create", "Not started"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	// Tag a release well before today, so today's work comes after it.
+	git("add", "-A")
+	c := exec.Command("git", "commit", "-q", "-m", "Add tasks")
+	c.Dir = repoDir
+	c.Env = append(os.Environ(), "GIT_AUTHOR_DATE=2026-01-01T12:00:00", "GIT_COMMITTER_DATE=2026-01-01T12:00:00")
+	if out, err := c.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v\n%s", err, out)
+	}
+	git("tag", "v1.4.0")
+
+	s, _ := store.NewFilesystem(filepath.Join(repoDir, ".skeeter"))
+	if tk, _ := s.Get("US-002"); tk.Type != "bug" {
+		t.Errorf("type = %q, want bug from the template", tk.Type)
+	}
+	sha := strings.Repeat("ab", 20)
+	for _, id := range []string{"US-001", "US-002"} {
+		tk, _ := s.Get(id)
+		if id == "US-001" {
+			tk.Links = append(tk.Links, sha)
+		}
+		store.ChangeStatus(s, tk, "done", true)
+		s.Update(tk)
+	}
+	git("add", "-A")
+	git("commit", "-q", "-m", "Finish tasks")
+	// Done after the last commit, so not between any refs yet.
+	if tk, _ := s.Get("US-003"); tk != nil {
+		store.ChangeStatus(s, tk, "done", true)
+		s.Update(tk)
+	}
+
+	var err error
+	out := captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "changelog", "--since", "v1.4.0", "--release", "1.5.0", "--format", "keep-a-changelog")
+	})
+	if err != nil {
+		t.Fatalf("changelog failed: %v", err)
+	}
+	today := time.Now().Format("2006-01-02")
+	for _, want := range []string{"## [1.5.0] - " + today, "### Added\n\n- Login form (US-001, ababab", "### Fixed\n\n- Crash on logout (US-002)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("changelog output = %q, want %q", out, want)
+		}
+	}
+	if strings.Contains(out, "Not started") {
+		t.Errorf("changelog included a task done after HEAD: %q", out)
+	}
+
+	if _, _, err := executeCommand(rootCmd, "changelog", "--since", "v1.4.0", "--until", "2026-03-31"); err == nil {
+		t.Error("expected an error mixing a ref and a date")
+	}
+	changelogUntil = ""
+
+	// Nothing was done by the time of the release.
+	out = captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "changelog", "--until", "v1.4.0", "--release", "", "--format", "markdown")
+	})
+	changelogUntil = ""
+	if err != nil || out != "## Unreleased\n" {
+		t.Errorf("changelog until v1.4.0 = %q, %v", out, err)
+	}
+
+	s.UpdateConfig(func(cfg *config.Config) error {
+		cfg.LLM.Tool = "echo"
+		cfg.LLM.Tools = map[string]config.LLMToolDef{"echo": {Command: "cat", PrintFlag: "-"}}
+		return nil
+	})
+	out = captureStdout(t, func() {
+		_, _, err = executeCommand(rootCmd, "changelog", "--since", "2026-01-02", "--format", "markdown", "--llm")
+	})
+	changelogLLM = false
+	if err != nil || !strings.Contains(out, "### Fixed\n\nYou are writing release notes") {
+		t.Errorf("changelog --llm = %q, %v", out, err)
+	}
+
+	if _, _, err := executeCommand(rootCmd, "changelog", "--format", "html"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	changelogFormat, changelogSince = "", ""
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/andybarilla/skeeter/internal/commitref"
+	"github.com/andybarilla/skeeter/internal/resolve"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+	"github.com/spf13/cobra"
+)
+
+// gitHookEnv is set while skeeter handles a git hook, so the commits its
+// own auto-commit makes don't set the hook off again.
+const gitHookEnv = "SKEETER_GIT_HOOK"
+
+// gitHookMarker identifies the git hooks skeeter installed.
+const gitHookMarker = "# Installed by skeeter hooks install"
+
+// gitHooks are the git hooks skeeter installs.
+var gitHooks = []string{"commit-msg", "post-commit"}
+
+var scanDryRun bool
+
+// commit is one commit read from git log.
+type commit struct {
+	SHA     string
+	Message string
+}
+
+// commitChange is what one commit did to one task.
+type commitChange struct {
+	Commit  string `json:"commit" yaml:"commit"`
+	Task    string `json:"task" yaml:"task"`
+	Linked  bool   `json:"linked" yaml:"linked"`
+	From    string `json:"from,omitempty" yaml:"from,omitempty"`
+	To      string `json:"to,omitempty" yaml:"to,omitempty"`
+	Warning string `json:"warning,omitempty" yaml:"warning,omitempty"`
+}
+
+var scanCommitsCmd = &cobra.Command{
+	Use:   "scan-commits <revision-range>...",
+	Short: "Link past commits to the tasks their messages mention",
+	Long: `Read the commits in a range, oldest first, and treat each one the way the
+post-commit hook from 'skeeter hooks install' would: add the commit SHA to
+the links of every task its message mentions, move mentioned tasks to
+in-progress and mark tasks it fixes done.
+
+A task is mentioned by its ID ("US-012") or "Refs US-012", and fixed by
+"Fixes", "Closes" or "Resolves" followed by its ID. Tasks only move forward
+through the workflow, and the statuses can be changed in config.yaml:
+
+  commits:
+    refs: in-progress   # or none to only link
+    fixes: done
+
+  skeeter scan-commits main..feature
+  skeeter scan-commits v1.4.0..HEAD --dry-run`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+		commits, err := gitCommits("", args...)
+		if err != nil {
+			return err
+		}
+
+		var changes []commitChange
+		for _, c := range commits {
+			applied, err := applyCommit(s, c, scanDryRun)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, applied...)
+		}
+
+		if isJSONOutput() {
+			if changes == nil {
+				changes = []commitChange{}
+			}
+			return outputJSON(changes)
+		}
+		if isYAMLOutput() {
+			return outputYAML(changes)
+		}
+		if len(changes) == 0 {
+			fmt.Printf("Scanned %d commit(s); no task changes.\n", len(commits))
+			return nil
+		}
+		printCommitChanges(changes)
+		return nil
+	},
+}
+
+// gitCommits returns the commits git log selects with args, oldest first.
+// dir is where git runs; empty means the working directory.
+func gitCommits(dir string, args ...string) ([]commit, error) {
+	cmd := exec.Command("git", append([]string{"log", "--reverse", "--format=%H%x00%B%x1e"}, args...)...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var commits []commit
+	for _, record := range strings.Split(string(out), "\x1e") {
+		sha, message, ok := strings.Cut(strings.TrimSpace(record), "\x00")
+		if ok {
+			commits = append(commits, commit{SHA: sha, Message: message})
+		}
+	}
+	return commits, nil
+}
+
+// applyCommit links c to the existing tasks its message refers to and
+// moves them forward as configured. With dryRun nothing is saved.
+// skeeter's own auto-commits are ignored.
+func applyCommit(s store.Store, c commit, dryRun bool) ([]commitChange, error) {
+	if strings.HasPrefix(c.Message, "skeeter: ") {
+		return nil, nil
+	}
+	cfg := s.GetConfig()
+	refs := commitref.Parse(c.Message, cfg.Project.Prefix)
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	tasks, err := s.List(store.Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []commitChange
+	for _, ref := range refs {
+		i := slices.IndexFunc(tasks, func(t task.Task) bool { return t.ID == ref.ID })
+		if i < 0 {
+			continue
+		}
+		t := &tasks[i]
+		change := commitChange{Commit: c.SHA, Task: t.ID}
+		if !slices.Contains(t.Links, c.SHA) {
+			t.Links = append(t.Links, c.SHA)
+			change.Linked = true
+		}
+		to := cfg.CommitStatus(ref.Fixes)
+		if to != "" && slices.Index(cfg.Statuses, to) > slices.Index(cfg.Statuses, t.Status) {
+			from := t.Status
+			if err := store.ChangeStatus(s, t, to, false); err != nil {
+				change.Warning = err.Error()
+			} else {
+				change.From, change.To = from, to
+			}
+		}
+		changed := change.Linked || change.To != ""
+		if !changed && change.Warning == "" {
+			continue
+		}
+		if changed && !dryRun {
+			if err := s.Update(t); err != nil {
+				return nil, err
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func printCommitChanges(changes []commitChange) {
+	for _, c := range changes {
+		var parts []string
+		if c.Linked {
+			parts = append(parts, "linked")
+		}
+		if c.To != "" {
+			parts = append(parts, c.From+" -> "+c.To)
+		}
+		if c.Warning != "" {
+			parts = append(parts, "not moved: "+c.Warning)
+		}
+		fmt.Printf("%s %s: %s\n", c.Commit[:min(7, len(c.Commit))], c.Task, strings.Join(parts, ", "))
+	}
+}
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage the git hooks that link commits to tasks",
+	Long: `Manage the git hooks that read task IDs from commit messages. These are
+git's hooks, in .git/hooks; the lifecycle hooks skeeter runs when a task
+changes live in .skeeter/hooks instead.`,
+}
+
+var hooksForce bool
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install git hooks that link commits to the tasks they mention",
+	Long: `Install a commit-msg hook, which warns about IDs that match no task, and
+a post-commit hook, which links each commit to the tasks it mentions and
+moves them as 'skeeter scan-commits' describes. The hooks call skeeter
+from your PATH and do nothing if it isn't there.
+
+Task files the post-commit hook changes are committed with auto_commit,
+and otherwise left for your next commit.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := gitHooksDir()
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		for _, hook := range gitHooks {
+			path := filepath.Join(dir, hook)
+			if data, err := os.ReadFile(path); err == nil && !strings.Contains(string(data), gitHookMarker) && !hooksForce {
+				return fmt.Errorf("%s already exists; use --force to replace it", path)
+			}
+		}
+		for _, hook := range gitHooks {
+			script := fmt.Sprintf("#!/bin/sh\n%s\ncommand -v skeeter >/dev/null 2>&1 || exit 0\nexec skeeter hooks run %s \"$@\"\n", gitHookMarker, hook)
+			if err := os.WriteFile(filepath.Join(dir, hook), []byte(script), 0755); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Installed %s hooks in %s\n", strings.Join(gitHooks, " and "), dir)
+		return nil
+	},
+}
+
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the git hooks skeeter installed",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := gitHooksDir()
+		if err != nil {
+			return err
+		}
+		for _, hook := range gitHooks {
+			path := filepath.Join(dir, hook)
+			data, err := os.ReadFile(path)
+			if err != nil || !strings.Contains(string(data), gitHookMarker) {
+				continue
+			}
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			fmt.Printf("Removed %s\n", path)
+		}
+		return nil
+	},
+}
+
+var hooksRunCmd = &cobra.Command{
+	Use:    "run <hook> [args]...",
+	Short:  "Run a git hook (called by the hooks skeeter installs)",
+	Hidden: true,
+	Args:   cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if os.Getenv(gitHookEnv) != "" {
+			return nil
+		}
+		os.Setenv(gitHookEnv, "1")
+
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+		switch args[0] {
+		case "commit-msg":
+			if len(args) < 2 {
+				return fmt.Errorf("commit-msg needs the message file")
+			}
+			data, err := os.ReadFile(args[1])
+			if err != nil {
+				return err
+			}
+			warnUnknownRefs(s, commitMessage(string(data)))
+			return nil
+		case "post-commit":
+			commits, err := gitCommits("", "-1", "HEAD")
+			if err != nil || len(commits) == 0 {
+				return err
+			}
+			changes, err := applyCommit(s, commits[0], false)
+			if err != nil {
+				return err
+			}
+			printCommitChanges(changes)
+			return nil
+		}
+		return fmt.Errorf("unknown git hook %q (valid: %s)", args[0], strings.Join(gitHooks, ", "))
+	},
+}
+
+// commitMessage drops the comment lines git leaves in a message file.
+func commitMessage(raw string) string {
+	var lines []string
+	for _, line := range strings.Split(raw, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// warnUnknownRefs warns about task IDs in message that match no task,
+// which usually means a typo. It never blocks the commit.
+func warnUnknownRefs(s store.Store, message string) {
+	refs := commitref.Parse(message, s.GetConfig().Project.Prefix)
+	if len(refs) == 0 {
+		return
+	}
+	tasks, err := s.List(store.Filter{})
+	if err != nil {
+		return
+	}
+	for _, ref := range refs {
+		if !slices.ContainsFunc(tasks, func(t task.Task) bool { return t.ID == ref.ID }) {
+			fmt.Fprintf(os.Stderr, "skeeter: %s doesn't match any task\n", ref.ID)
+		}
+	}
+}
+
+// gitHooksDir returns the repository's git hooks directory, honoring
+// core.hooksPath.
+func gitHooksDir() (string, error) {
+	if remoteFlag != "" {
+		return "", fmt.Errorf("git hooks can only be installed in a local repository")
+	}
+	dir, err := resolve.Dir(dirFlag)
+	if err != nil {
+		return "", err
+	}
+	repoDir := filepath.Dir(dir)
+	cmd := exec.Command("git", "rev-parse", "--git-path", "hooks")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s is not in a git repository", repoDir)
+	}
+	hooks := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(hooks) {
+		hooks = filepath.Join(repoDir, hooks)
+	}
+	return hooks, nil
+}
+
+func init() {
+	scanCommitsCmd.Flags().BoolVar(&scanDryRun, "dry-run", false, "show what would change without saving")
+	hooksInstallCmd.Flags().BoolVar(&hooksForce, "force", false, "replace existing hooks that skeeter didn't install")
+	hooksCmd.AddCommand(hooksInstallCmd, hooksUninstallCmd, hooksRunCmd)
+	rootCmd.AddCommand(scanCommitsCmd, hooksCmd)
+}
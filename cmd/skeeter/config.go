@@ -2,10 +2,15 @@ package main
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
-	"github.com/andybarilla/skeeter/internal/resolve"
-	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/changelog"
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/gitinfo"
+	"github.com/andybarilla/skeeter/internal/id"
+	"github.com/andybarilla/skeeter/internal/task"
 	"github.com/spf13/cobra"
 )
 
@@ -13,26 +18,23 @@ var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "View or modify project configuration",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if remoteFlag != "" {
-			return fmt.Errorf("config is not supported for remote repositories")
-		}
-
-		dir, err := resolve.Dir(dirFlag)
+		s, err := openStore()
 		if err != nil {
 			return err
 		}
 
-		s, err := store.NewFilesystem(dir)
-		if err != nil {
-			return err
-		}
-
-		cfg := s.Config
+		cfg := s.GetConfig()
 		fmt.Printf("Project:       %s\n", cfg.Project.Name)
 		fmt.Printf("Prefix:        %s\n", cfg.Project.Prefix)
 		fmt.Printf("Statuses:      %s\n", strings.Join(cfg.Statuses, " -> "))
 		fmt.Printf("Priorities:    %s\n", strings.Join(cfg.Priorities, ", "))
 		fmt.Printf("Auto-commit:   %v\n", cfg.AutoCommit)
+		fmt.Printf("Index:         %v\n", cfg.Index)
+		strategy := cfg.IDs.Strategy
+		if strategy == "" {
+			strategy = id.Sequential
+		}
+		fmt.Printf("ID strategy:   %s\n", strategy)
 		fmt.Printf("LLM tool:      %s\n", cfg.LLM.Tool)
 		if len(cfg.LLM.WorkArgs) > 0 {
 			fmt.Printf("LLM work args: %s\n", strings.Join(cfg.LLM.WorkArgs, " "))
@@ -44,6 +46,27 @@ var configCmd = &cobra.Command{
 			}
 			fmt.Printf("LLM custom:    %s\n", strings.Join(names, ", "))
 		}
+		fmt.Printf("Commits:       mentions -> %s, fixes -> %s\n", orNone(cfg.CommitStatus(false)), orNone(cfg.CommitStatus(true)))
+		pattern, main := cfg.Branches.Pattern, cfg.Branches.Main
+		if pattern == "" {
+			pattern = config.DefaultBranchPattern
+		}
+		if main == "" {
+			main = "auto"
+		}
+		fmt.Printf("Branches:      %s (main: %s)\n", pattern, main)
+		format := cfg.Changelog.Format
+		if format == "" {
+			format = changelog.FormatMarkdown
+		}
+		fmt.Printf("Changelog:     %s\n", format)
+		for _, hook := range cfg.Webhooks {
+			events := "all events"
+			if len(hook.Events) > 0 {
+				events = strings.Join(hook.Events, ", ")
+			}
+			fmt.Printf("Webhook:       %s (%s)\n", hook.URL, events)
+		}
 		return nil
 	},
 }
@@ -57,84 +80,138 @@ var configSetCmd = &cobra.Command{
   statuses          Comma-separated status list (ordered as workflow)
   priorities        Comma-separated priority list (highest first)
   auto_commit       Enable auto-commit (true/false)
+  index             Cache parsed tasks in .cache/index.json for fast listing (true/false)
+  ids.strategy      How new IDs are allocated: sequential, hash, block
+  ids.block_size    Number of IDs reserved per author with the block strategy
   llm.tool          LLM tool name (builtin: claude)
-  llm.work_args     Comma-separated extra args for skeeter work (e.g., "--dangerously-skip-permissions")`,
+  llm.work_args     Comma-separated extra args for skeeter work (e.g., "--dangerously-skip-permissions")
+  commits.refs      Status for tasks a commit mentions (default in-progress, none to only link)
+  commits.fixes     Status for tasks a commit fixes (default the last status, none to only link)
+  branches.pattern  Template for task branch names (default "{{id}}-{{slug title}}")
+  branches.main     Branch that task branches merge into (default origin's HEAD, main or master)
+  changelog.format  Default changelog format: markdown, keep-a-changelog`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if remoteFlag != "" {
-			return fmt.Errorf("config is not supported for remote repositories")
-		}
-
-		dir, err := resolve.Dir(dirFlag)
+		s, err := openStore()
 		if err != nil {
 			return err
 		}
 
-		s, err := store.NewFilesystem(dir)
-		if err != nil {
+		key, value := args[0], args[1]
+		if err := s.UpdateConfig(func(cfg *config.Config) error {
+			return applyConfigSetting(cfg, key, value)
+		}); err != nil {
 			return err
 		}
 
-		key, value := args[0], args[1]
+		fmt.Printf("Set %s = %s\n", key, value)
+		return nil
+	},
+}
 
-		switch key {
-		case "name":
-			s.Config.Project.Name = value
-		case "prefix":
-			s.Config.Project.Prefix = value
-		case "statuses":
-			var statuses []string
-			for _, st := range strings.Split(value, ",") {
-				statuses = append(statuses, strings.TrimSpace(st))
-			}
-			if len(statuses) < 2 {
-				return fmt.Errorf("need at least 2 statuses")
-			}
-			s.Config.Statuses = statuses
-		case "priorities":
-			var priorities []string
-			for _, p := range strings.Split(value, ",") {
-				priorities = append(priorities, strings.TrimSpace(p))
-			}
-			if len(priorities) < 1 {
-				return fmt.Errorf("need at least 1 priority")
-			}
-			s.Config.Priorities = priorities
-		case "auto_commit":
-			switch strings.ToLower(value) {
-			case "true", "1", "yes":
-				s.Config.AutoCommit = true
-			case "false", "0", "no":
-				s.Config.AutoCommit = false
-			default:
-				return fmt.Errorf("invalid value %q for auto_commit (use true/false)", value)
-			}
-		case "llm.tool":
-			s.Config.LLM.Tool = value
-		case "llm.work_args":
-			var workArgs []string
-			for _, a := range strings.Split(value, ",") {
-				trimmed := strings.TrimSpace(a)
-				if trimmed != "" {
-					workArgs = append(workArgs, trimmed)
-				}
-			}
-			s.Config.LLM.WorkArgs = workArgs
-		default:
-			return fmt.Errorf("unknown config key %q (valid: name, prefix, statuses, priorities, auto_commit, llm.tool, llm.work_args)", key)
+func applyConfigSetting(cfg *config.Config, key, value string) error {
+	switch key {
+	case "name":
+		cfg.Project.Name = value
+	case "prefix":
+		cfg.Project.Prefix = value
+	case "statuses":
+		var statuses []string
+		for _, st := range strings.Split(value, ",") {
+			statuses = append(statuses, strings.TrimSpace(st))
 		}
-
-		if err := s.Config.Save(dir); err != nil {
+		if len(statuses) < 2 {
+			return fmt.Errorf("need at least 2 statuses")
+		}
+		cfg.Statuses = statuses
+	case "priorities":
+		var priorities []string
+		for _, p := range strings.Split(value, ",") {
+			priorities = append(priorities, strings.TrimSpace(p))
+		}
+		if len(priorities) < 1 {
+			return fmt.Errorf("need at least 1 priority")
+		}
+		cfg.Priorities = priorities
+	case "auto_commit":
+		enabled, err := parseBool(key, value)
+		if err != nil {
 			return err
 		}
-
-		if err := s.RegenerateSkeeterMD(); err != nil {
+		cfg.AutoCommit = enabled
+	case "index":
+		enabled, err := parseBool(key, value)
+		if err != nil {
+			return err
+		}
+		cfg.Index = enabled
+	case "ids.strategy":
+		if !id.ValidStrategy(value) {
+			return fmt.Errorf("invalid ID strategy %q (valid: %s)", value, strings.Join(id.Strategies, ", "))
+		}
+		cfg.IDs.Strategy = value
+	case "ids.block_size":
+		size, err := strconv.Atoi(value)
+		if err != nil || size <= 0 {
+			return fmt.Errorf("invalid value for ids.block_size: %q (use a positive number)", value)
+		}
+		cfg.IDs.BlockSize = size
+	case "llm.tool":
+		cfg.LLM.Tool = value
+	case "llm.work_args":
+		var workArgs []string
+		for _, a := range strings.Split(value, ",") {
+			trimmed := strings.TrimSpace(a)
+			if trimmed != "" {
+				workArgs = append(workArgs, trimmed)
+			}
+		}
+		cfg.LLM.WorkArgs = workArgs
+	case "commits.refs", "commits.fixes":
+		if value != "none" && !cfg.ValidStatus(value) {
+			return fmt.Errorf("invalid status %q for %s (valid: %s, none)", value, key, strings.Join(cfg.Statuses, ", "))
+		}
+		if key == "commits.refs" {
+			cfg.Commits.Refs = value
+		} else {
+			cfg.Commits.Fixes = value
+		}
+	case "branches.pattern":
+		sample := &task.Task{ID: cfg.Project.Prefix + "-001", Title: "Sample task"}
+		if _, err := gitinfo.BranchName(value, sample); err != nil {
 			return err
 		}
+		cfg.Branches.Pattern = value
+	case "branches.main":
+		cfg.Branches.Main = value
+	case "changelog.format":
+		if !slices.Contains(changelog.Formats, value) {
+			return fmt.Errorf("invalid changelog format %q (valid: %s)", value, strings.Join(changelog.Formats, ", "))
+		}
+		cfg.Changelog.Format = value
+	default:
+		return fmt.Errorf("unknown config key %q (valid: name, prefix, statuses, priorities, auto_commit, index, ids.strategy, ids.block_size, llm.tool, llm.work_args, commits.refs, commits.fixes, branches.pattern, branches.main, changelog.format)", key)
+	}
+	return nil
+}
 
-		fmt.Printf("Set %s = %s\n", key, value)
-		return nil
-	},
+// orNone shows an empty commit status as "none".
+func orNone(status string) string {
+	if status == "" {
+		return "none"
+	}
+	return status
+}
+
+func parseBool(key, value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "1", "yes":
+		return true, nil
+	case "false", "0", "no":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid value %q for %s (use true/false)", value, key)
+	}
 }
 
 func init() {
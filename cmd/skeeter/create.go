@@ -19,6 +19,7 @@ var (
 	createNoTemplate bool
 	createDepends    string
 	createDue        string
+	createForce      bool
 )
 
 var createCmd = &cobra.Command{
@@ -90,13 +91,20 @@ var createCmd = &cobra.Command{
 				body = tmplBody
 			}
 		}
+		// A task made from a template other than the default is that kind
+		// of task, which changelog groups by.
+		taskType := ""
+		if createTemplate != "default" && !createNoTemplate {
+			taskType = createTemplate
+		}
 
 		t := &task.Task{
 			ID:        id,
 			Title:     args[0],
-			Status:    createStatus,
+			Status:    cfg.Statuses[0],
 			Priority:  createPriority,
 			Assignee:  createAssignee,
+			Type:      taskType,
 			Tags:      tags,
 			DependsOn: dependsOn,
 			Due:       createDue,
@@ -105,10 +113,14 @@ var createCmd = &cobra.Command{
 			Body:      body,
 		}
 
-		if len(dependsOn) > 0 {
-			if cycle, _ := store.DetectCircularDependency(t, s); len(cycle) > 0 {
-				return fmt.Errorf("circular dependency detected: %s -> %s", strings.Join(cycle, " -> "), t.ID)
-			}
+		// Starting past the first status is a move from it, so it follows
+		// the workflow like any other.
+		if err := store.ChangeStatus(s, t, createStatus, createForce); err != nil {
+			return withForceHint(err, "--force")
+		}
+
+		if err := checkCycle(t, s); err != nil {
+			return err
 		}
 
 		if err := s.Create(t); err != nil {
@@ -132,5 +144,6 @@ func init() {
 	createCmd.Flags().BoolVar(&createNoTemplate, "no-template", false, "create with empty body")
 	createCmd.Flags().StringVarP(&createDepends, "depends", "d", "", "comma-separated task IDs this task depends on")
 	createCmd.Flags().StringVarP(&createDue, "due", "", "", "due date (format: YYYY-MM-DD)")
+	createCmd.Flags().BoolVar(&createForce, "force", false, "override workflow transition rules and guards for --status")
 	rootCmd.AddCommand(createCmd)
 }
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/andybarilla/skeeter/internal/graph"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+	"github.com/spf13/cobra"
+)
+
+var depsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Manage, inspect and validate task dependencies and relations",
+	Long: `Manage the links between tasks. depends_on blocks a task until its
+dependencies are done; relates_to, duplicates and split_from are
+informational and shown from both sides by 'skeeter show'.`,
+}
+
+var (
+	depsType       string
+	depsTransitive bool
+)
+
+// relationLabels describe each relation kind in output.
+var relationLabels = map[string]string{
+	"depends_on": "depends on",
+	"relates_to": "relates to",
+	"duplicates": "duplicates",
+	"split_from": "was split from",
+}
+
+var depsAddCmd = &cobra.Command{
+	Use:   "add <id> <other-id>...",
+	Short: "Add dependencies or relations to a task",
+	Long: `Add task IDs to a task's depends_on, or to another relation with --type.
+Every ID must name an existing task, and new dependencies may not create a
+cycle.
+
+  skeeter deps add US-004 US-002 US-003
+  skeeter deps add US-007 US-003 --type duplicates`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeRelation(args, func(t *task.Task, ids *task.FlowSlice, other string) error {
+			if other == t.ID {
+				return fmt.Errorf("%s cannot list itself in %s", t.ID, depsType)
+			}
+			if !slices.Contains(*ids, other) {
+				*ids = append(*ids, other)
+			}
+			return nil
+		})
+	},
+}
+
+var depsRmCmd = &cobra.Command{
+	Use:   "rm <id> <other-id>...",
+	Short: "Remove dependencies or relations from a task",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeRelation(args, func(t *task.Task, ids *task.FlowSlice, other string) error {
+			i := slices.Index(*ids, other)
+			if i < 0 {
+				return fmt.Errorf("%s does not list %s in %s", t.ID, other, depsType)
+			}
+			*ids = slices.Delete(*ids, i, i+1)
+			return nil
+		})
+	},
+}
+
+// changeRelation applies change to the depsType list of the task args[0]
+// for each of the other IDs, validates the result and saves it.
+func changeRelation(args []string, change func(t *task.Task, ids *task.FlowSlice, other string) error) error {
+	s, err := openStore()
+	if err != nil {
+		return err
+	}
+	t, err := s.Get(strings.ToUpper(args[0]))
+	if err != nil {
+		return err
+	}
+	ids := t.Relation(depsType)
+	if ids == nil {
+		return fmt.Errorf("invalid relation %q (valid: %s)", depsType, strings.Join(task.Relations, ", "))
+	}
+
+	tasks, err := s.List(store.Filter{})
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(tasks))
+	for _, other := range tasks {
+		known[other.ID] = true
+	}
+	for _, arg := range args[1:] {
+		other := strings.ToUpper(arg)
+		if !known[other] && !slices.Contains(*ids, other) {
+			return fmt.Errorf("task %s not found", other)
+		}
+		if err := change(t, ids, other); err != nil {
+			return err
+		}
+	}
+	if depsType == "depends_on" {
+		if err := checkCycle(t, s); err != nil {
+			return err
+		}
+	}
+
+	if err := s.Update(t); err != nil {
+		return err
+	}
+	list := "(none)"
+	if len(*ids) > 0 {
+		list = strings.Join(*ids, ", ")
+	}
+	fmt.Printf("%s %s: %s\n", t.ID, relationLabels[depsType], list)
+	return nil
+}
+
+// depsReport is the machine-readable output of deps show.
+type depsReport struct {
+	ID               string       `json:"id" yaml:"id"`
+	DependsOn        []graph.Node `json:"depends_on" yaml:"depends_on"`
+	Dependents       []graph.Node `json:"dependents" yaml:"dependents"`
+	*store.Relations `yaml:",inline"`
+}
+
+var depsShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show what a task depends on, what depends on it, and its relations",
+	Long: `Show a task's dependencies and dependents with their status, followed by
+its other relations in both directions. With --transitive, follow
+dependencies all the way up and dependents all the way down, as trees.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+		g, tasks, err := loadGraph(s, graphScope{})
+		if err != nil {
+			return err
+		}
+		id := strings.ToUpper(args[0])
+		i := slices.IndexFunc(tasks, func(t task.Task) bool { return t.ID == id })
+		if i < 0 {
+			return fmt.Errorf("task %s not found", id)
+		}
+		t := &tasks[i]
+
+		report := depsReport{
+			ID:         t.ID,
+			DependsOn:  reachedNodes(g, t.ID, g.DependsOn, g.Upstream),
+			Dependents: reachedNodes(g, t.ID, g.Dependents, g.Downstream),
+			Relations:  store.GetRelations(t, tasks),
+		}
+		if isJSONOutput() {
+			return outputJSON(report)
+		}
+		if isYAMLOutput() {
+			return outputYAML(report)
+		}
+
+		fmt.Printf("%s: %s\n", t.ID, t.Title)
+		for _, section := range []struct {
+			label string
+			next  func(string) []string
+		}{
+			{"Depends on", g.DependsOn},
+			{"Needed by", g.Dependents},
+		} {
+			fmt.Printf("\n%s:\n", section.label)
+			if len(section.next(t.ID)) == 0 {
+				fmt.Println("  (none)")
+				continue
+			}
+			printDepsTree(g, t.ID, section.next, 1, map[string]bool{t.ID: true})
+		}
+
+		for _, line := range relationLines(report.Relations) {
+			if len(line.ids) > 0 {
+				fmt.Printf("\n%s: %s\n", line.label, strings.Join(line.ids, ", "))
+			}
+		}
+		return nil
+	},
+}
+
+// reachedNodes returns the nodes next leads to from id, or with
+// --transitive everything reach finds, in graph order.
+func reachedNodes(g *graph.Graph, id string, next func(string) []string, reach func(string) map[string]bool) []graph.Node {
+	nodes := []graph.Node{}
+	if !depsTransitive {
+		for _, n := range next(id) {
+			nodes = append(nodes, *g.Node(n))
+		}
+		return nodes
+	}
+	reached := reach(id)
+	for _, n := range g.Nodes {
+		if reached[n.ID] && n.ID != id {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// printDepsTree prints what next leads to from id, indented by depth, and
+// with --transitive what those lead to in turn. A task reached twice is
+// expanded only the first time.
+func printDepsTree(g *graph.Graph, id string, next func(string) []string, depth int, seen map[string]bool) {
+	for _, n := range next(id) {
+		node := g.Node(n)
+		line := node.ID + " " + node.Title
+		switch {
+		case node.Missing:
+			line = node.ID + " (missing)"
+		case node.Status != "":
+			line += " [" + node.Status + "]"
+		}
+		expand := depsTransitive && len(next(n)) > 0
+		if expand && seen[n] {
+			line += " (see above)"
+			expand = false
+		}
+		fmt.Printf("%s%s\n", strings.Repeat("  ", depth), line)
+		if expand {
+			seen[n] = true
+			printDepsTree(g, n, next, depth+1, seen)
+		}
+	}
+}
+
+// depsProblem is one problem found by deps check.
+type depsProblem struct {
+	Kind    string   `json:"kind" yaml:"kind"`
+	Task    string   `json:"task" yaml:"task"`
+	Tasks   []string `json:"tasks,omitempty" yaml:"tasks,omitempty"`
+	Message string   `json:"message" yaml:"message"`
+}
+
+var depsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the whole task set for circular and dangling dependencies",
+	Long: `Build the dependency graph of every task and report each group of tasks
+that depend on each other in a loop, with one cycle through it, and every
+depends_on entry that names a task that doesn't exist.
+
+Exits nonzero if any problems are found, so it can gate CI.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+		problems, err := checkDependencies(s)
+		if err != nil {
+			return err
+		}
+
+		if isJSONOutput() {
+			if problems == nil {
+				problems = []depsProblem{}
+			}
+			if err := outputJSON(problems); err != nil {
+				return err
+			}
+		} else if isYAMLOutput() {
+			if err := outputYAML(problems); err != nil {
+				return err
+			}
+		} else if len(problems) == 0 {
+			fmt.Println("No dependency problems found.")
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TASK\tPROBLEM\tDETAILS")
+			for _, p := range problems {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", p.Task, p.Kind, p.Message)
+			}
+			w.Flush()
+		}
+
+		if len(problems) > 0 {
+			return fmt.Errorf("%d dependency problem(s) found", len(problems))
+		}
+		return nil
+	},
+}
+
+// checkDependencies lists s once and returns its dependency cycles, one per
+// strongly connected component, followed by its dangling dependencies.
+func checkDependencies(s store.Store) ([]depsProblem, error) {
+	tasks, err := s.List(store.Filter{})
+	if err != nil {
+		return nil, err
+	}
+	warnSkippedFiles(s)
+	g := graph.Build(tasks, "")
+
+	var problems []depsProblem
+	for _, comp := range g.Components() {
+		cycle := g.CycleThrough(comp[0])
+		msg := "circular dependency: " + strings.Join(append(cycle, cycle[0]), " -> ")
+		if len(comp) > len(cycle) {
+			msg += fmt.Sprintf(" (%d tasks in the loop: %s)", len(comp), strings.Join(comp, ", "))
+		}
+		problems = append(problems, depsProblem{Kind: "cycle", Task: comp[0], Tasks: comp, Message: msg})
+	}
+	for _, t := range tasks {
+		for _, dep := range g.DependsOn(t.ID) {
+			if g.Node(dep).Missing {
+				problems = append(problems, depsProblem{
+					Kind:    "dangling",
+					Task:    t.ID,
+					Tasks:   []string{dep},
+					Message: fmt.Sprintf("depends on %s, which does not exist", dep),
+				})
+			}
+		}
+	}
+	return problems, nil
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{depsAddCmd, depsRmCmd} {
+		cmd.Flags().StringVarP(&depsType, "type", "t", "depends_on", "relation to change: "+strings.Join(task.Relations, ", "))
+	}
+	depsShowCmd.Flags().BoolVar(&depsTransitive, "transitive", false, "follow dependencies and dependents all the way")
+	depsCmd.AddCommand(depsAddCmd, depsRmCmd, depsShowCmd, depsCheckCmd)
+	rootCmd.AddCommand(depsCmd)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/andybarilla/skeeter/internal/doctor"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var doctorFix bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check task files for problems",
+	Long: `Check every task file for problems that would otherwise make tasks vanish
+from listings or behave unexpectedly: unparseable files, file name/id
+mismatches, duplicate IDs, invalid statuses and priorities, dangling or
+circular depends_on references, and malformed due dates.
+
+With --fix, mechanically fixable problems are repaired in place. Exits
+nonzero if any problems remain.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+
+		scanner, ok := s.(store.Scanner)
+		if !ok {
+			return fmt.Errorf("doctor is not supported for this backend")
+		}
+
+		files, err := scanner.Scan()
+		if err != nil {
+			return err
+		}
+
+		if doctorFix {
+			fixed, err := doctor.Fix(s, files)
+			for _, issue := range fixed {
+				fmt.Printf("Fixed %s: %s\n", issue.File, issue.Message)
+			}
+			if err != nil {
+				return err
+			}
+			if files, err = scanner.Scan(); err != nil {
+				return err
+			}
+		}
+
+		issues := doctor.Check(s.GetConfig(), files)
+
+		if isJSONOutput() {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(issues); err != nil {
+				return err
+			}
+		} else if len(issues) == 0 {
+			fmt.Println("No problems found.")
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "FILE\tPROBLEM\tFIXABLE\tDETAILS")
+			for _, issue := range issues {
+				fixable := "-"
+				if issue.Fixable {
+					fixable = "yes"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", issue.File, issue.Kind, fixable, issue.Message)
+			}
+			w.Flush()
+		}
+
+		if len(issues) > 0 {
+			return fmt.Errorf("%d problem(s) found", len(issues))
+		}
+		return nil
+	},
+}
+
+// warnSkippedFiles prints a summary on stderr of task files the last List
+// call skipped because they could not be parsed.
+func warnSkippedFiles(s store.Store) {
+	reporter, ok := s.(store.ProblemReporter)
+	if !ok {
+		return
+	}
+	problems := reporter.Problems()
+	if len(problems) == 0 {
+		return
+	}
+
+	var names []string
+	for _, p := range problems {
+		names = append(names, p.Name)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: skipped %d malformed task file(s): %s (run 'skeeter doctor' for details)\n",
+		len(problems), strings.Join(names, ", "))
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "repair mechanically fixable problems")
+	rootCmd.AddCommand(doctorCmd)
+}
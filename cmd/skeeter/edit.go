@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,13 +10,18 @@ import (
 
 	"github.com/andybarilla/skeeter/internal/resolve"
 	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
 	"github.com/spf13/cobra"
 )
 
+var editForce bool
+
 var editCmd = &cobra.Command{
 	Use:   "edit <id>",
 	Short: "Open a task in your editor",
-	Args:  cobra.ExactArgs(1),
+	Long: `Open a task in your editor. A changed status must follow the workflow
+in config.yaml, as with skeeter status; --force overrides it.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if remoteFlag != "" {
 			return fmt.Errorf("edit is not supported for remote repositories")
@@ -38,19 +44,28 @@ var editCmd = &cobra.Command{
 			return err
 		}
 
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = "vi"
+		if err := editTask(s, taskID); err != nil {
+			return err
 		}
 
-		taskPath := filepath.Join(dir, "tasks", taskID+".md")
-		c := exec.Command(editor, taskPath)
-		c.Stdin = os.Stdin
-		c.Stdout = os.Stdout
-		c.Stderr = os.Stderr
+		fmt.Printf("Updated %s\n", taskID)
+		return nil
+	},
+}
 
-		if err := c.Run(); err != nil {
-			return fmt.Errorf("editor exited with error: %w", err)
+// editTask opens a task in $EDITOR and saves the result. Local tasks are
+// edited in place; remote ones through a temporary copy.
+func editTask(s store.Store, taskID string) error {
+	if fs, ok := s.(*store.FilesystemStore); ok {
+		path := filepath.Join(fs.Dir, "tasks", taskID+".md")
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		// A file that was already malformed has no before for hooks.
+		before, _ := task.Parse(string(original))
+		if err := runEditor(path); err != nil {
+			return err
 		}
 
 		// Re-parse to update the timestamp
@@ -58,16 +73,109 @@ var editCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("warning: file may have invalid format: %w", err)
 		}
-
-		if err := s.Update(t); err != nil {
+		if before != nil {
+			if err := store.ApplyStatusChange(s, t, before, editForce); err != nil {
+				return discardEdit(path, original, withForceHint(err, "--force"))
+			}
+		}
+		if err := fs.UpdateFrom(before, t); err != nil {
+			// A pre-update hook vetoed the edit, which is already on disk.
+			var he *store.HookError
+			if errors.As(err, &he) {
+				return discardEdit(path, original, err)
+			}
 			return err
 		}
-
-		fmt.Printf("Updated %s\n", taskID)
+		// The file is already saved, so all that's left is to say so.
+		if err := checkCycle(t, s); err != nil {
+			return fmt.Errorf("%w; the file was saved, fix depends_on and run skeeter deps check", err)
+		}
 		return nil
-	},
+	}
+
+	t, err := s.Get(taskID)
+	if err != nil {
+		return err
+	}
+	content, err := task.Marshal(t)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp("", taskID+"-*.md")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := runEditor(tmp.Name()); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return err
+	}
+	edited, err := task.Parse(string(data))
+	if err != nil {
+		return fmt.Errorf("invalid task format, changes discarded: %w", err)
+	}
+	edited.ID = taskID
+	if err := checkCycle(edited, s); err != nil {
+		return fmt.Errorf("%w, changes discarded", err)
+	}
+	if err := store.ApplyStatusChange(s, edited, t, editForce); err != nil {
+		return fmt.Errorf("%w, changes discarded", withForceHint(err, "--force"))
+	}
+	return s.Update(edited)
+}
+
+// discardEdit puts back the original contents of an edited task file that
+// couldn't be saved.
+func discardEdit(path string, original []byte, err error) error {
+	if werr := os.WriteFile(path, original, 0644); werr != nil {
+		return fmt.Errorf("%w; restoring %s: %v", err, path, werr)
+	}
+	return fmt.Errorf("%w, changes discarded", err)
+}
+
+// checkCycle fails if t's dependencies put it on a cycle.
+func checkCycle(t *task.Task, s store.Store) error {
+	cycle, err := store.DetectCircularDependency(t, s)
+	if err != nil {
+		return err
+	}
+	if len(cycle) > 0 {
+		return fmt.Errorf("circular dependency detected: %s -> %s", strings.Join(cycle, " -> "), t.ID)
+	}
+	return nil
+}
+
+// runEditor opens path in $EDITOR, falling back to vi, on the terminal.
+func runEditor(path string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	c := exec.Command(editor, path)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor exited with error: %w", err)
+	}
+	return nil
 }
 
 func init() {
+	editCmd.Flags().BoolVar(&editForce, "force", false, "override workflow transition rules and guards for a changed status")
 	rootCmd.AddCommand(editCmd)
 }
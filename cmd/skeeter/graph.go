@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/andybarilla/skeeter/internal/graph"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+	"github.com/spf13/cobra"
+)
+
+// graphScope narrows a dependency graph; the flags are shared by graph and
+// plan-order.
+type graphScope struct {
+	tag  string
+	epic string
+	task string
+}
+
+func (sc *graphScope) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&sc.tag, "tag", "", "only tasks with this tag")
+	cmd.Flags().StringVar(&sc.epic, "epic", "", "only this task and everything it transitively depends on")
+	cmd.Flags().StringVar(&sc.task, "task", "", "only tasks connected to this one through dependencies, in either direction")
+}
+
+var (
+	graphFlags    graphScope
+	graphFormat   string
+	graphCritical bool
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the dependency graph as Graphviz DOT, Mermaid or JSON",
+	Long: `Export the depends_on graph of all tasks. Edges point from a dependency to
+the task that depends on it. Done tasks are shaded and dependencies on
+tasks that don't exist are dashed.
+
+An epic is a task whose depends_on lists its parts, so --epic shows
+everything that gates it.
+
+  skeeter graph | dot -Tsvg > deps.svg
+  skeeter graph --format mermaid --epic US-010 --critical`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+		g, _, err := loadGraph(s, graphFlags)
+		if err != nil {
+			return err
+		}
+
+		var highlight map[string]bool
+		if graphCritical {
+			path, err := g.CriticalPath()
+			if err != nil {
+				return err
+			}
+			highlight = make(map[string]bool, len(path))
+			for _, id := range path {
+				highlight[id] = true
+			}
+		}
+		return graph.Write(os.Stdout, g, graphFormat, highlight)
+	},
+}
+
+var (
+	planFlags graphScope
+	planAll   bool
+)
+
+// planOrder is the machine-readable output of plan-order.
+type planOrder struct {
+	Order        []graph.Node `json:"order" yaml:"order"`
+	CriticalPath []string     `json:"critical_path" yaml:"critical_path"`
+}
+
+var planOrderCmd = &cobra.Command{
+	Use:   "plan-order",
+	Short: "Print an execution order and the critical path of unfinished work",
+	Long: `Print the unfinished tasks in an order that respects depends_on, with
+higher-priority tasks first wherever dependencies allow, followed by the
+critical path: the longest chain of unfinished tasks, each waiting on the
+one before it. Use --epic to see what gates a milestone.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+		g, _, err := loadGraph(s, planFlags)
+		if err != nil {
+			return err
+		}
+		if !planAll {
+			keep := make(map[string]bool, len(g.Nodes))
+			for _, n := range g.Nodes {
+				keep[n.ID] = !n.Done
+			}
+			g = g.Subgraph(keep)
+		}
+
+		order, err := g.Order()
+		if err != nil {
+			return err
+		}
+		path, err := g.CriticalPath()
+		if err != nil {
+			return err
+		}
+
+		out := planOrder{Order: []graph.Node{}, CriticalPath: path}
+		for _, id := range order {
+			out.Order = append(out.Order, *g.Node(id))
+		}
+		if out.CriticalPath == nil {
+			out.CriticalPath = []string{}
+		}
+		if isJSONOutput() {
+			return outputJSON(out)
+		}
+		if isYAMLOutput() {
+			return outputYAML(out)
+		}
+
+		if len(order) == 0 {
+			fmt.Println("No unfinished tasks.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "#\tID\tTITLE\tSTATUS\tPRIORITY")
+		for i, n := range out.Order {
+			title := n.Title
+			if n.Missing {
+				title = "(missing)"
+			}
+			if r := []rune(title); len(r) > 40 {
+				title = string(r[:37]) + "..."
+			}
+			priority := n.Priority
+			if priority == "" {
+				priority = "-"
+			}
+			status := n.Status
+			if status == "" {
+				status = "-"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", i+1, n.ID, title, status, priority)
+		}
+		w.Flush()
+
+		fmt.Printf("\nCritical path (%d tasks): %s\n", len(path), strings.Join(path, " -> "))
+		return nil
+	},
+}
+
+// loadGraph builds the dependency graph of s, narrowed to scope, and returns
+// it with the tasks it was built from. Tasks are added in board order so
+// that orderings favor higher-priority work.
+func loadGraph(s store.Store, scope graphScope) (*graph.Graph, []task.Task, error) {
+	cfg := s.GetConfig()
+	tasks, err := s.List(store.Filter{})
+	if err != nil {
+		return nil, nil, err
+	}
+	warnSkippedFiles(s)
+	store.SortTasks(tasks, cfg)
+
+	done := ""
+	if len(cfg.Statuses) > 0 {
+		done = cfg.Statuses[len(cfg.Statuses)-1]
+	}
+	g := graph.Build(tasks, done)
+
+	keep := make(map[string]bool, len(g.Nodes))
+	for _, n := range g.Nodes {
+		keep[n.ID] = true
+	}
+	if scope.tag != "" {
+		for _, t := range tasks {
+			if !slices.Contains(t.Tags, scope.tag) {
+				keep[t.ID] = false
+			}
+		}
+		for _, n := range g.Nodes {
+			if n.Missing {
+				keep[n.ID] = false
+			}
+		}
+	}
+	for _, root := range []struct {
+		id    string
+		reach func(string) map[string]bool
+	}{
+		{strings.ToUpper(scope.epic), g.Upstream},
+		{strings.ToUpper(scope.task), func(id string) map[string]bool {
+			connected := g.Upstream(id)
+			for id := range g.Downstream(id) {
+				connected[id] = true
+			}
+			return connected
+		}},
+	} {
+		if root.id == "" {
+			continue
+		}
+		if g.Node(root.id) == nil {
+			return nil, nil, fmt.Errorf("task %s not found", root.id)
+		}
+		reached := root.reach(root.id)
+		for id := range keep {
+			keep[id] = keep[id] && reached[id]
+		}
+	}
+	return g.Subgraph(keep), tasks, nil
+}
+
+func init() {
+	graphFlags.addFlags(graphCmd)
+	graphCmd.Flags().StringVar(&graphFormat, "format", "dot", "output format: "+strings.Join(graph.Formats, ", "))
+	graphCmd.Flags().BoolVar(&graphCritical, "critical", false, "highlight the critical path")
+	rootCmd.AddCommand(graphCmd)
+
+	planFlags.addFlags(planOrderCmd)
+	planOrderCmd.Flags().BoolVar(&planAll, "all", false, "include done tasks in the order")
+	rootCmd.AddCommand(planOrderCmd)
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/andybarilla/skeeter/internal/resolve"
 	"github.com/andybarilla/skeeter/internal/store"
@@ -16,7 +18,7 @@ var initCmd = &cobra.Command{
 	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if remoteFlag != "" {
-			return fmt.Errorf("init is not supported for remote repositories — initialize locally and push")
+			return initRemote(args)
 		}
 
 		dir, err := resolve.DirForInit(dirFlag)
@@ -48,6 +50,28 @@ var initCmd = &cobra.Command{
 	},
 }
 
+func initRemote(args []string) error {
+	projectName := ""
+	if len(args) > 0 {
+		projectName = args[0]
+	} else {
+		projectName = strings.TrimSuffix(path.Base(strings.TrimRight(remoteFlag, "/")), ".git")
+	}
+
+	s, err := store.NewRemoteForInit(remoteFlag, dirFlag)
+	if err != nil {
+		return err
+	}
+	if err := s.Init(projectName); err != nil {
+		return err
+	}
+
+	fmt.Printf("Initialized skeeter in %s\n", remoteFlag)
+	fmt.Printf("  Project: %s\n", projectName)
+	fmt.Printf("  Prefix:  %s\n", s.GetConfig().Project.Prefix)
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(initCmd)
 }
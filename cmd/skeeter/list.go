@@ -42,6 +42,7 @@ var listCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		warnSkippedFiles(s)
 
 		if listBlocked {
 			allTasks, _ := s.List(store.Filter{})
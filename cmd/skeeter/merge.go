@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/merge"
+	"github.com/andybarilla/skeeter/internal/resolve"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// mergeAttribute routes task files to the merge driver. It lives in the
+// skeeter directory's own .gitattributes, next to the id-blocks rule.
+const mergeAttribute = "tasks/*.md merge=skeeter"
+
+var mergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver <base> <ours> <theirs>",
+	Short: "Three-way merge of a task file (git merge driver)",
+	Long: `Merge two versions of a task file field by field and write the result to
+<ours>. Called by git as "skeeter merge-driver %O %A %B" once registered with
+'skeeter install-merge-driver'.
+
+Tags, links, depends_on and the other relations keep additions from both
+sides, status takes the side further along the workflow, updated takes the
+newest date and the body is merged line by line. Exits nonzero if a conflict
+needs attention.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// git runs drivers from the repository root, where the project config
+		// gives us the workflow order. Fall back to the default workflow.
+		cfg := config.Default()
+		if dir, err := resolve.Dir(dirFlag); err == nil {
+			if loaded, err := config.Load(dir); err == nil {
+				cfg = loaded
+			}
+		}
+
+		conflicts, err := merge.Files(args[0], args[1], args[2], cfg.Statuses)
+		if err != nil {
+			return err
+		}
+		for _, c := range conflicts {
+			fmt.Fprintf(os.Stderr, "skeeter merge: %s\n", c)
+		}
+		if len(conflicts) > 0 {
+			return fmt.Errorf("%d conflict(s) in task file", len(conflicts))
+		}
+		return nil
+	},
+}
+
+var installMergeDriverCmd = &cobra.Command{
+	Use:   "install-merge-driver",
+	Short: "Register the task merge driver with git",
+	Long: `Register 'skeeter merge-driver' in .git/config and route task files to it
+from the skeeter directory's .gitattributes. Commit .gitattributes so the
+rule applies for everyone; each clone still needs to run this command to
+define the driver.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if remoteFlag != "" {
+			return fmt.Errorf("install-merge-driver only works on a local .skeeter directory")
+		}
+		dir, err := resolve.Dir(dirFlag)
+		if err != nil {
+			return err
+		}
+
+		settings := [][]string{
+			{"merge.skeeter.name", "skeeter task merge"},
+			{"merge.skeeter.driver", "skeeter merge-driver %O %A %B"},
+		}
+		for _, kv := range settings {
+			c := exec.Command("git", "config", kv[0], kv[1])
+			c.Dir = dir
+			if out, err := c.CombinedOutput(); err != nil {
+				return fmt.Errorf("git config %s: %w\n%s", kv[0], err, strings.TrimSpace(string(out)))
+			}
+		}
+
+		if err := store.AddGitAttribute(filepath.Join(dir, ".gitattributes"), mergeAttribute); err != nil {
+			return err
+		}
+
+		fmt.Println("Installed merge driver for task files.")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeDriverCmd)
+	rootCmd.AddCommand(installMergeDriverCmd)
+}
@@ -3,7 +3,12 @@ package main
 import (
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/andybarilla/skeeter/internal/agent"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
 	"github.com/spf13/cobra"
 )
 
@@ -17,7 +22,7 @@ var ErrNoTasksAvailable = errors.New("no tasks available")
 var nextCmd = &cobra.Command{
 	Use:   "next",
 	Short: "Show the next available task for an agent to pick up",
-	Long:  "Returns the highest-priority unassigned task in ready-for-development status. Designed for coding agents to discover work.",
+	Long:  "Returns the highest-priority unassigned task in the ready status (the second one, ready-for-development by default). Designed for coding agents to discover work.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		s, err := openStore()
 		if err != nil {
@@ -26,10 +31,11 @@ var nextCmd = &cobra.Command{
 
 		cfg := s.GetConfig()
 
-		picked, err := pickNextTask(s, cfg)
+		picked, err := agent.PickNext(s, cfg)
 		if err != nil {
 			return err
 		}
+		warnSkippedFiles(s)
 
 		if picked == nil {
 			if isJSONOutput() {
@@ -42,11 +48,11 @@ var nextCmd = &cobra.Command{
 		}
 
 		if nextAssign != "" {
-			picked.Assignee = nextAssign
-			picked.Status = "in-progress"
-			if err := s.Update(picked); err != nil {
+			if err := agent.Claim(s, picked, nextAssign); err != nil {
 				return err
 			}
+		} else {
+			warnWIPLimit(s, picked, agent.ClaimStatus(cfg))
 		}
 
 		if isJSONOutput() {
@@ -66,6 +72,22 @@ var nextCmd = &cobra.Command{
 	},
 }
 
+// warnWIPLimit warns when moving t to status would break a WIP limit, so
+// an agent that only looks at next knows a claim will be refused.
+func warnWIPLimit(s store.Store, t *task.Task, status string) {
+	cfg := s.GetConfig()
+	if cfg.WIPLimits.Statuses[status] == 0 && (cfg.WIPLimits.PerAssignee == 0 || t.Assignee == "") {
+		return
+	}
+	tasks, err := s.List(store.Filter{})
+	if err != nil {
+		return
+	}
+	if violations := store.WIPViolations(cfg, t, status, tasks); len(violations) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", strings.Join(violations, "; "))
+	}
+}
+
 func init() {
 	nextCmd.Flags().StringVar(&nextAssign, "assign", "", "auto-assign the task and move to in-progress")
 	nextCmd.Flags().BoolVarP(&nextQuiet, "quiet", "q", false, "output only the task ID")
@@ -33,6 +33,18 @@ func outputTasksYAML(tasks []task.Task) error {
 	return enc.Encode(tasks)
 }
 
+func outputJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func outputYAML(v any) error {
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	return enc.Encode(v)
+}
+
 func outputNullJSON() error {
 	fmt.Fprintln(os.Stdout, "null")
 	return nil
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rankBefore string
+	rankAfter  string
+)
+
+var rankCmd = &cobra.Command{
+	Use:   "rank <id> (--before <id> | --after <id>)",
+	Short: "Order a task relative to another of the same priority",
+	Long: `Place a task just before or just after another task with the same status
+and priority. The board and 'skeeter next' order tasks by priority, then by
+this manual rank, then by ID.
+
+Ranks are sortable keys, so moving a task only rewrites that task. The first
+time unranked tasks are involved, the ones ahead of the new position are
+ranked in their current order.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (rankBefore == "") == (rankAfter == "") {
+			return fmt.Errorf("specify exactly one of --before or --after")
+		}
+
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+
+		t, err := store.Reorder(s, strings.ToUpper(args[0]), strings.ToUpper(rankBefore), strings.ToUpper(rankAfter))
+		if err != nil {
+			return err
+		}
+
+		if rankBefore != "" {
+			fmt.Printf("%s: ranked before %s\n", t.ID, strings.ToUpper(rankBefore))
+		} else {
+			fmt.Printf("%s: ranked after %s\n", t.ID, strings.ToUpper(rankAfter))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rankCmd.Flags().StringVar(&rankBefore, "before", "", "place the task before this one")
+	rankCmd.Flags().StringVar(&rankAfter, "after", "", "place the task after this one")
+	rootCmd.AddCommand(rankCmd)
+}
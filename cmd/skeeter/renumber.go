@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var renumberCmd = &cobra.Command{
+	Use:   "renumber [<id> [<new-id>]]",
+	Short: "Give tasks new IDs, resolving merge collisions",
+	Long: `Give tasks new IDs and rewrite depends_on and other relation references to
+match.
+
+With no arguments, resolve ID collisions left by a merge: when two branches
+each created a task with the same ID, git leaves one conflicted file. The
+task from the current branch keeps the ID and the merged-in task is moved to
+a new one. References to the ID that the merged-in branch added, in its
+moved task and the other tasks it changed, are rewritten to the new ID;
+the current branch's keep pointing at the task that kept it.
+
+With one argument, move that task to the next ID from the configured
+strategy. With two, move it to the given ID.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+		fs, ok := s.(*store.FilesystemStore)
+		if !ok {
+			return fmt.Errorf("renumber only works on a local .skeeter directory")
+		}
+
+		if len(args) == 0 {
+			renamed, err := fs.ResolveCollisions()
+			if err != nil {
+				return err
+			}
+			return printRenamed(renamed)
+		}
+
+		oldID := strings.ToUpper(args[0])
+		var newID string
+		if len(args) == 2 {
+			newID = strings.ToUpper(args[1])
+		} else if newID, err = fs.NextID(); err != nil {
+			return err
+		}
+
+		t, err := fs.Get(oldID)
+		if err != nil {
+			return err
+		}
+		if err := fs.Renumber(oldID, newID); err != nil {
+			return err
+		}
+		return printRenamed([]store.Renamed{{OldID: oldID, NewID: newID, Title: t.Title}})
+	},
+}
+
+func printRenamed(renamed []store.Renamed) error {
+	if isJSONOutput() {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(renamed)
+	}
+	if len(renamed) == 0 {
+		fmt.Println("No ID collisions found.")
+		return nil
+	}
+	for _, r := range renamed {
+		fmt.Printf("Renumbered %s -> %s: %s\n", r.OldID, r.NewID, r.Title)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(renumberCmd)
+}
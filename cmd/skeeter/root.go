@@ -35,13 +35,13 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&dirFlag, "dir", "", "path to skeeter directory (default: auto-detect .skeeter/)")
-	rootCmd.PersistentFlags().StringVar(&remoteFlag, "remote", "", "use GitHub API backend (format: owner/repo)")
+	rootCmd.PersistentFlags().StringVar(&remoteFlag, "remote", "", "use a remote backend (owner/repo for the GitHub API, or any git URL)")
 	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", "table", "output format: table, json, yaml")
 }
 
 func openStore() (store.Store, error) {
 	if remoteFlag != "" {
-		return store.NewGitHub(remoteFlag, dirFlag)
+		return store.NewRemote(remoteFlag, dirFlag)
 	}
 	dir, err := resolve.Dir(dirFlag)
 	if err != nil {
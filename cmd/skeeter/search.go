@@ -3,11 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
-	"strings"
 	"text/tabwriter"
 
 	"github.com/andybarilla/skeeter/internal/store"
-	"github.com/andybarilla/skeeter/internal/task"
 	"github.com/spf13/cobra"
 )
 
@@ -26,32 +24,16 @@ var searchCmd = &cobra.Command{
 			return err
 		}
 
-		query := strings.ToLower(args[0])
-
 		filter := store.Filter{}
 		if searchTag != "" {
 			filter.Tag = searchTag
 		}
 
-		allTasks, err := s.List(filter)
+		results, err := store.Search(s, args[0], searchTitleOnly, filter)
 		if err != nil {
 			return err
 		}
-
-		var results []task.Task
-		for _, t := range allTasks {
-			if searchTitleOnly {
-				if strings.Contains(strings.ToLower(t.Title), query) {
-					results = append(results, t)
-				}
-			} else {
-				titleMatch := strings.Contains(strings.ToLower(t.Title), query)
-				bodyMatch := strings.Contains(strings.ToLower(t.Body), query)
-				if titleMatch || bodyMatch {
-					results = append(results, t)
-				}
-			}
-		}
+		warnSkippedFiles(s)
 
 		if isJSONOutput() {
 			return outputTasksJSON(results)
@@ -2,8 +2,10 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
+	"github.com/andybarilla/skeeter/internal/gitinfo"
 	"github.com/andybarilla/skeeter/internal/store"
 	"github.com/andybarilla/skeeter/internal/task"
 	"github.com/spf13/cobra"
@@ -31,19 +33,74 @@ var showCmd = &cobra.Command{
 			return outputTaskYAML(t)
 		}
 
-		allTasks, _ := s.List(store.Filter{})
-		printTaskWithDeps(t, s, allTasks)
+		if err := printTaskWithDeps(t, s); err != nil {
+			return err
+		}
+		printGitStatus(s, t)
 		return nil
 	},
 }
 
-func printTaskWithDeps(t *task.Task, s store.Store, allTasks []task.Task) {
+// showCommits is how many commits mentioning a task show lists.
+const showCommits = 5
+
+// printGitStatus prints what the git repository holding the board knows
+// about t: its branch and the latest commits that mention it. For remote
+// boards, or outside a repository, only the branch name is left to show.
+func printGitStatus(s store.Store, t *task.Task) {
+	cfg := s.GetConfig()
+	st, err := &gitinfo.Status{}, gitinfo.ErrNotRepository
+	if fs, ok := s.(*store.FilesystemStore); ok {
+		st, err = gitinfo.Inspect(filepath.Dir(fs.Dir), t, cfg.Project.Prefix, cfg.Branches.Main, showCommits)
+	}
+	if err != nil {
+		if t.Branch != "" {
+			fmt.Printf("\nBranch: %s\n", t.Branch)
+		}
+		return
+	}
+	if st.Branch == "" && len(st.Commits) == 0 {
+		return
+	}
+
+	fmt.Println()
+	if st.Branch != "" {
+		state := ""
+		switch {
+		case !st.Exists:
+			state = " (not in this repository)"
+		case st.Merged:
+			state = fmt.Sprintf(" (merged into %s)", st.Main)
+		case st.Main != "":
+			state = fmt.Sprintf(" (%d ahead, %d behind %s)", st.Ahead, st.Behind, st.Main)
+		}
+		fmt.Printf("Branch: %s%s\n", st.Branch, state)
+	}
+	if len(st.Commits) > 0 {
+		fmt.Println("Commits:")
+		for _, c := range st.Commits {
+			fmt.Printf("  %s %s %s\n", c.SHA[:7], c.Date, c.Subject)
+		}
+	}
+}
+
+func printTaskWithDeps(t *task.Task, s store.Store) error {
+	depStatus, rel, err := store.GetLinks(s, t)
+	if err != nil {
+		return err
+	}
 	printTask(t)
+	lines := relationLines(rel)
 
-	depStatus := store.GetDependencyStatus(s, t, allTasks)
+	linked := len(depStatus.DependsOn) > 0 || len(depStatus.Blocking) > 0
+	for _, line := range lines {
+		linked = linked || len(line.ids) > 0
+	}
+	if linked {
+		fmt.Println()
+	}
 
 	if len(depStatus.DependsOn) > 0 {
-		fmt.Println()
 		if len(depStatus.BlockedBy) > 0 {
 			fmt.Printf("Blocked by: %s (incomplete)\n", strings.Join(depStatus.BlockedBy, ", "))
 		} else {
@@ -53,6 +110,29 @@ func printTaskWithDeps(t *task.Task, s store.Store, allTasks []task.Task) {
 	if len(depStatus.Blocking) > 0 {
 		fmt.Printf("Blocking:   %s\n", strings.Join(depStatus.Blocking, ", "))
 	}
+	for _, line := range lines {
+		if len(line.ids) > 0 {
+			fmt.Printf("%s: %s\n", line.label, strings.Join(line.ids, ", "))
+		}
+	}
+	return nil
+}
+
+// relationLine is one of a task's relations with its label.
+type relationLine struct {
+	label string
+	ids   []string
+}
+
+// relationLines lists rel's relations in display order.
+func relationLines(rel *store.Relations) []relationLine {
+	return []relationLine{
+		{"Relates to", rel.RelatesTo},
+		{"Duplicates", rel.Duplicates},
+		{"Duplicated by", rel.DuplicatedBy},
+		{"Split from", rel.SplitFrom},
+		{"Split into", rel.SplitInto},
+	}
 }
 
 func printTask(t *task.Task) {
@@ -67,6 +147,9 @@ func printTask(t *task.Task) {
 
 	fmt.Printf("%s: %s\n", t.ID, t.Title)
 	fmt.Printf("Status: %s | Priority: %s | Assignee: %s\n", t.Status, priority, assignee)
+	if t.Type != "" {
+		fmt.Printf("Type: %s\n", t.Type)
+	}
 	if t.Due != "" {
 		fmt.Printf("Due: %s\n", t.Due)
 	}
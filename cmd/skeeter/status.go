@@ -1,35 +1,50 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/andybarilla/skeeter/internal/store"
 	"github.com/spf13/cobra"
 )
 
+var statusForce bool
+
 var statusCmd = &cobra.Command{
 	Use:   "status <id> <new-status>",
 	Short: "Change task status",
-	Args:  cobra.ExactArgs(2),
+	Long: `Change a task's status. When config.yaml declares a workflow, the move
+must be an allowed transition and the task must pass the guards of the new
+status:
+
+  workflow:
+    transitions:
+      backlog: [ready-for-development]
+      ready-for-development: [in-progress, backlog]
+      in-progress: [done, ready-for-development]
+    guards:
+      in-progress: [not_blocked, assignee]
+      done: [criteria_checked]
+
+--force overrides the rules; the task's history records what was overridden.`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		s, err := openStore()
 		if err != nil {
 			return err
 		}
 
-		cfg := s.GetConfig()
 		newStatus := args[1]
-		if !cfg.ValidStatus(newStatus) {
-			return fmt.Errorf("invalid status %q (valid: %s)", newStatus, strings.Join(cfg.Statuses, ", "))
-		}
-
 		t, err := s.Get(strings.ToUpper(args[0]))
 		if err != nil {
 			return err
 		}
 
 		oldStatus := t.Status
-		t.Status = newStatus
+		if err := store.ChangeStatus(s, t, newStatus, statusForce); err != nil {
+			return withForceHint(err, "--force")
+		}
 
 		if err := s.Update(t); err != nil {
 			return err
@@ -40,6 +55,17 @@ var statusCmd = &cobra.Command{
 	},
 }
 
+// withForceHint points out flag on workflow violations.
+func withForceHint(err error, flag string) error {
+	var te *store.TransitionError
+	var ae *store.AssignError
+	if errors.As(err, &te) || errors.As(err, &ae) {
+		return fmt.Errorf("%w (%s overrides)", err, flag)
+	}
+	return err
+}
+
 func init() {
+	statusCmd.Flags().BoolVar(&statusForce, "force", false, "override workflow transition rules and guards")
 	rootCmd.AddCommand(statusCmd)
 }
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiPollInterval time.Duration
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Full-screen kanban board in the terminal",
+	Long: `Show the board full screen in the terminal, with a column per status.
+
+Select tasks with the arrow keys (or h/j/k/l), move them between columns
+with H and L, open them in $EDITOR with e, and filter with /. Press ? for
+all keys. The board reloads when task files change, or every
+--poll-interval for remote repositories.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+		defer stop()
+
+		return tui.Run(ctx, s, os.Stdin, os.Stdout, tui.Options{
+			Edit: func(id string) error {
+				return editTask(s, strings.ToUpper(id))
+			},
+			PollInterval: tuiPollInterval,
+		})
+	},
+}
+
+func init() {
+	tuiCmd.Flags().DurationVar(&tuiPollInterval, "poll-interval", 30*time.Second, "how often to check remote repositories for changes")
+	rootCmd.AddCommand(tuiCmd)
+}
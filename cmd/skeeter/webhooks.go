@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/webhook"
+	"github.com/spf13/cobra"
+)
+
+var webhooksCmd = &cobra.Command{
+	Use:   "webhooks",
+	Short: "Show webhook deliveries that haven't gone through yet",
+	Long: `Task events are POSTed to the endpoints under webhooks in config.yaml.
+Each event waits in .skeeter/.outbox (for remote repositories, in the user
+cache directory) until its endpoint accepts it. Every write tries what's
+pending once; this lists the deliveries still waiting, and 'webhooks flush'
+retries them with backoff.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outbox, err := openOutbox()
+		if err != nil {
+			return err
+		}
+		pending, err := outbox.Pending()
+		if err != nil {
+			return err
+		}
+		return printDeliveries(pending)
+	},
+}
+
+var webhooksFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Retry pending webhook deliveries",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+		outbox, err := storeOutbox(s)
+		if err != nil {
+			return err
+		}
+		left, err := outbox.Flush(s.GetConfig().Webhooks)
+		if err != nil {
+			return err
+		}
+		if err := printDeliveries(left); err != nil {
+			return err
+		}
+		if len(left) > 0 {
+			return fmt.Errorf("%d webhook deliveries still pending", len(left))
+		}
+		return nil
+	},
+}
+
+var webhooksClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Drop pending webhook deliveries without sending them",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outbox, err := openOutbox()
+		if err != nil {
+			return err
+		}
+		n, err := outbox.Clear()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Dropped %d pending deliveries.\n", n)
+		return nil
+	},
+}
+
+func openOutbox() (*webhook.Outbox, error) {
+	s, err := openStore()
+	if err != nil {
+		return nil, err
+	}
+	return storeOutbox(s)
+}
+
+func storeOutbox(s store.Store) (*webhook.Outbox, error) {
+	owner, ok := s.(store.OutboxOwner)
+	if !ok {
+		return nil, fmt.Errorf("webhooks are not supported for this repository")
+	}
+	return owner.Outbox(), nil
+}
+
+func printDeliveries(deliveries []webhook.Delivery) error {
+	if isJSONOutput() {
+		if deliveries == nil {
+			deliveries = []webhook.Delivery{}
+		}
+		return outputJSON(deliveries)
+	}
+	if isYAMLOutput() {
+		return outputYAML(deliveries)
+	}
+	if len(deliveries) == 0 {
+		fmt.Println("No pending webhook deliveries.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CREATED\tEVENT\tURL\tATTEMPTS\tLAST ERROR")
+	for _, d := range deliveries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", d.Created, d.Event, d.URL, d.Attempts, d.LastError)
+	}
+	return w.Flush()
+}
+
+func init() {
+	webhooksCmd.AddCommand(webhooksFlushCmd, webhooksClearCmd)
+	rootCmd.AddCommand(webhooksCmd)
+}
@@ -7,8 +7,10 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/andybarilla/skeeter/internal/agent"
 	"github.com/andybarilla/skeeter/internal/llm"
 	"github.com/andybarilla/skeeter/internal/resolve"
+	"github.com/andybarilla/skeeter/internal/store"
 	"github.com/spf13/cobra"
 )
 
@@ -24,11 +26,14 @@ var workCmd = &cobra.Command{
 	Long: `Run an autonomous coding loop (the "Ralph Wiggum" technique).
 
 Each iteration:
-  1. Finds the highest-priority unassigned task in ready-for-development
-  2. Claims it (assigns + moves to in-progress)
+  1. Finds the highest-priority unassigned task in the ready status
+  2. Claims it (assigns + moves to the in-progress status)
   3. Builds a prompt with task details
   4. Pipes the prompt to the configured LLM tool
-  5. On success, marks the task done
+  5. On success, moves the task to the last status
+
+The ready and in-progress statuses are the second and third configured
+statuses (ready-for-development and in-progress by default).
   6. Repeats until no tasks remain or --max iterations reached
 
 Configure the LLM tool:
@@ -41,8 +46,7 @@ Configure the LLM tool:
 
 		cfg := s.GetConfig()
 
-		tool, err := cfg.ResolveTool()
-		if err != nil {
+		if _, err := cfg.ResolveTool(); err != nil {
 			return err
 		}
 
@@ -51,80 +55,58 @@ Configure the LLM tool:
 			return err
 		}
 
+		if workDryRun {
+			return printWorkPrompts(s, dir)
+		}
+
 		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 		defer stop()
 
-		iteration := 0
-		for {
-			if ctx.Err() != nil {
-				fmt.Fprintln(os.Stderr, "\nInterrupted, stopping work loop.")
-				return nil
-			}
-
-			if workMax > 0 && iteration >= workMax {
-				fmt.Fprintf(os.Stderr, "Reached max iterations (%d), stopping.\n", workMax)
-				return nil
-			}
-
-			picked, err := pickNextTask(s, cfg)
-			if err != nil {
-				return err
-			}
-			if picked == nil {
-				fmt.Fprintln(os.Stderr, "No more tasks available, stopping.")
-				return nil
-			}
-
-			fmt.Printf("\n=== Iteration %d: %s — %s ===\n\n", iteration+1, picked.ID, picked.Title)
-
-			// Claim the task
-			picked.Assignee = workAssign
-			picked.Status = "in-progress"
-			if err := s.Update(picked); err != nil {
-				return err
-			}
-
-			systemPrompt, userContent := llm.BuildWorkPrompts(cfg, picked, dir)
-
-			if workDryRun {
-				fmt.Println("=== System Prompt ===")
-				fmt.Println(systemPrompt)
-				fmt.Println("\n=== User Content ===")
-				fmt.Println(userContent)
-				// Unclaim
-				picked.Assignee = ""
-				picked.Status = "ready-for-development"
-				_ = s.Update(picked)
-				return nil
-			}
-
-			// Execute work command
-			if err := llm.RunCLIPassthrough(ctx, tool, systemPrompt, userContent, cfg.LLM.WorkArgs...); err != nil {
-				fmt.Fprintf(os.Stderr, "\nWork command failed for %s: %v\n", picked.ID, err)
-				// Revert task
-				picked.Assignee = ""
-				picked.Status = "ready-for-development"
-				_ = s.Update(picked)
-				return fmt.Errorf("work command failed for %s: %w", picked.ID, err)
-			}
-
-			// Re-read task from disk in case agent modified it
-			fresh, err := s.Get(picked.ID)
-			if err != nil {
-				return fmt.Errorf("re-reading task %s: %w", picked.ID, err)
-			}
-
-			fresh.Status = "done"
-			if err := s.Update(fresh); err != nil {
-				return fmt.Errorf("marking %s done: %w", picked.ID, err)
-			}
-
-			fmt.Printf("\n=== %s marked done ===\n", picked.ID)
-			iteration++
-		}
+		runner := agent.NewRunner(s, dir)
+		return runner.Run(ctx, agent.Options{Max: workMax, Assign: workAssign}, printWorkEvent)
 	},
 }
 
+func printWorkEvent(e agent.Event) {
+	switch e.Kind {
+	case agent.EventIterationStart:
+		fmt.Printf("\n=== Iteration %d: %s — %s ===\n\n", e.Iteration, e.TaskID, e.Title)
+	case agent.EventOutput:
+		if e.Stream == "stderr" {
+			fmt.Fprintln(os.Stderr, e.Line)
+		} else {
+			fmt.Println(e.Line)
+		}
+	case agent.EventTaskDone:
+		fmt.Printf("\n=== %s marked done ===\n", e.TaskID)
+	case agent.EventTaskFailed:
+		fmt.Fprintf(os.Stderr, "\nWork command failed for %s: %s\n", e.TaskID, e.Message)
+	case agent.EventStopped:
+		fmt.Fprintf(os.Stderr, "Stopping: %s.\n", e.Message)
+	}
+}
+
+// printWorkPrompts shows the prompts the next task would get. They are built
+// from a claimed copy of the task, so nothing is saved and no hooks run.
+func printWorkPrompts(s store.Store, dir string) error {
+	cfg := s.GetConfig()
+	picked, err := agent.PickNext(s, cfg)
+	if err != nil {
+		return err
+	}
+	if picked == nil {
+		fmt.Fprintln(os.Stderr, "No more tasks available, stopping.")
+		return nil
+	}
+
+	systemPrompt, userContent := llm.BuildWorkPrompts(cfg, agent.Claimed(cfg, picked, workAssign), dir)
+	fmt.Println("=== System Prompt ===")
+	fmt.Println(systemPrompt)
+	fmt.Println("\n=== User Content ===")
+	fmt.Println(userContent)
+	return nil
+}
+
 func init() {
 	workCmd.Flags().IntVar(&workMax, "max", 0, "max iterations (0 = unlimited)")
 	workCmd.Flags().StringVar(&workAssign, "assign", "ralph", "assignee name for claimed tasks")
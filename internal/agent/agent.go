@@ -0,0 +1,320 @@
+// Package agent runs the autonomous work loop: pick the next ready task,
+// claim it, hand it to the configured LLM tool and mark it done, until no
+// work remains.
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/llm"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// Event kinds emitted while the loop runs.
+const (
+	EventIterationStart = "iteration-start"
+	EventOutput         = "output"
+	EventTaskDone       = "task-done"
+	EventTaskFailed     = "task-failed"
+	EventStopped        = "stopped"
+)
+
+// ErrRunning is returned by Start when a loop is already running.
+var ErrRunning = errors.New("work loop is already running")
+
+// Event describes one step of the work loop.
+type Event struct {
+	Kind      string `json:"kind"`
+	Iteration int    `json:"iteration"`
+	TaskID    string `json:"taskId,omitempty"`
+	Title     string `json:"title,omitempty"`
+	// Stream and Line are set for output events.
+	Stream string `json:"stream,omitempty"`
+	Line   string `json:"line,omitempty"`
+	// Message explains task-failed and stopped events.
+	Message string `json:"message,omitempty"`
+}
+
+// Options configure a run.
+type Options struct {
+	// Max is the number of tasks to work on; 0 means until none are left.
+	Max int `json:"max"`
+	// Assign is the assignee recorded on claimed tasks.
+	Assign string `json:"assign"`
+	// WorkDir is where the LLM tool runs; empty means the current directory.
+	WorkDir string `json:"workDir"`
+}
+
+// Status is a snapshot of the runner.
+type Status struct {
+	Running   bool     `json:"running"`
+	Iteration int      `json:"iteration"`
+	TaskID    string   `json:"taskId"`
+	Completed []string `json:"completed"`
+	Error     string   `json:"error"`
+}
+
+// execFunc runs the LLM tool for one task, reporting output line by line.
+type execFunc func(ctx context.Context, tool *config.LLMToolDef, dir, systemPrompt, userContent string, onLine func(stream, line string), extraArgs ...string) error
+
+// Runner supervises the work loop for one store. A Runner can run one loop
+// at a time, either blocking with Run or in the background with Start.
+type Runner struct {
+	store      store.Store
+	skeeterDir string
+	exec       execFunc
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+	status Status
+}
+
+// NewRunner returns a runner for s. skeeterDir is used to build prompts and
+// find prompt overrides.
+func NewRunner(s store.Store, skeeterDir string) *Runner {
+	return &Runner{store: s, skeeterDir: skeeterDir, exec: llm.RunCLIStream}
+}
+
+// Start runs the loop in the background until it finishes or Stop is
+// called. emit receives every event, from the loop's goroutine.
+func (r *Runner) Start(ctx context.Context, opts Options, emit func(Event)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.status.Running {
+		return ErrRunning
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	r.status = Status{Running: true}
+
+	go func() {
+		defer close(r.done)
+		defer cancel()
+		r.run(ctx, opts, emit)
+	}()
+	return nil
+}
+
+// Run runs the loop and blocks until it finishes, returning the error that
+// stopped it, if any. Cancelling ctx stops the loop without error.
+func (r *Runner) Run(ctx context.Context, opts Options, emit func(Event)) error {
+	r.mu.Lock()
+	if r.status.Running {
+		r.mu.Unlock()
+		return ErrRunning
+	}
+	r.status = Status{Running: true}
+	r.mu.Unlock()
+
+	return r.run(ctx, opts, emit)
+}
+
+// Stop cancels a background loop and waits for it to wind down. The task in
+// progress is returned to the ready column.
+func (r *Runner) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// Status returns a snapshot of the runner.
+func (r *Runner) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.status
+	s.Completed = append([]string(nil), r.status.Completed...)
+	return s
+}
+
+func (r *Runner) update(fn func(*Status)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(&r.status)
+}
+
+func (r *Runner) run(ctx context.Context, opts Options, emit func(Event)) (err error) {
+	iteration := 0
+	stop := func(message string) {
+		emit(Event{Kind: EventStopped, Iteration: iteration, Message: message})
+	}
+	defer func() {
+		r.update(func(s *Status) {
+			s.Running = false
+			s.TaskID = ""
+			if err != nil {
+				s.Error = err.Error()
+			}
+		})
+	}()
+
+	cfg := r.store.GetConfig()
+	tool, err := cfg.ResolveTool()
+	if err != nil {
+		stop(err.Error())
+		return err
+	}
+
+	for {
+		if ctx.Err() != nil {
+			stop("interrupted")
+			return nil
+		}
+		if opts.Max > 0 && iteration >= opts.Max {
+			stop(fmt.Sprintf("reached max iterations (%d)", opts.Max))
+			return nil
+		}
+
+		picked, err := PickNext(r.store, cfg)
+		if err != nil {
+			stop(err.Error())
+			return err
+		}
+		if picked == nil {
+			stop("no more tasks available")
+			return nil
+		}
+
+		iteration++
+		r.update(func(s *Status) {
+			s.Iteration = iteration
+			s.TaskID = picked.ID
+		})
+		emit(Event{Kind: EventIterationStart, Iteration: iteration, TaskID: picked.ID, Title: picked.Title})
+
+		if err := Claim(r.store, picked, opts.Assign); err != nil {
+			// The workflow, a WIP limit or a pre-update hook refusing new
+			// work is a reason to stop, not a failure.
+			stop(err.Error())
+			var te *store.TransitionError
+			var he *store.HookError
+			if errors.As(err, &te) || errors.As(err, &he) {
+				return nil
+			}
+			return err
+		}
+
+		systemPrompt, userContent := llm.BuildWorkPrompts(cfg, picked, r.skeeterDir)
+		onLine := func(stream, line string) {
+			emit(Event{Kind: EventOutput, Iteration: iteration, TaskID: picked.ID, Stream: stream, Line: line})
+		}
+
+		if err := r.exec(ctx, tool, opts.WorkDir, systemPrompt, userContent, onLine, cfg.LLM.WorkArgs...); err != nil {
+			_ = Unclaim(r.store, picked)
+			if ctx.Err() != nil {
+				stop("interrupted")
+				return nil
+			}
+			emit(Event{Kind: EventTaskFailed, Iteration: iteration, TaskID: picked.ID, Title: picked.Title, Message: err.Error()})
+			err = fmt.Errorf("work command failed for %s: %w", picked.ID, err)
+			stop(err.Error())
+			return err
+		}
+
+		// Re-read task from disk in case agent modified it
+		fresh, err := r.store.Get(picked.ID)
+		if err != nil {
+			err = fmt.Errorf("re-reading task %s: %w", picked.ID, err)
+			stop(err.Error())
+			return err
+		}
+		// A task the workflow won't let finish stays in progress for a
+		// person to look at.
+		if err := store.ChangeStatus(r.store, fresh, cfg.DoneStatus(), false); err != nil {
+			err = fmt.Errorf("marking %s done: %w", picked.ID, err)
+			stop(err.Error())
+			return err
+		}
+		if err := r.store.Update(fresh); err != nil {
+			err = fmt.Errorf("marking %s done: %w", picked.ID, err)
+			stop(err.Error())
+			return err
+		}
+
+		r.update(func(s *Status) {
+			s.Completed = append(s.Completed, picked.ID)
+		})
+		emit(Event{Kind: EventTaskDone, Iteration: iteration, TaskID: picked.ID, Title: picked.Title})
+	}
+}
+
+// ClaimStatus returns the status Claim moves tasks into: cfg's in-progress
+// status, or "" when the workflow has none.
+func ClaimStatus(cfg *config.Config) string {
+	return cfg.InProgressStatus()
+}
+
+// Claim assigns t and moves it to the in-progress status, subject to the
+// workflow.
+func Claim(s store.Store, t *task.Task, assignee string) error {
+	status := ClaimStatus(s.GetConfig())
+	if status == "" {
+		return fmt.Errorf("claiming %s: the workflow needs at least three statuses to have an in-progress one", t.ID)
+	}
+	t.Assignee = assignee
+	if err := store.ChangeStatus(s, t, status, false); err != nil {
+		return err
+	}
+	return s.Update(t)
+}
+
+// Claimed returns a copy of t as Claim would leave it, without checking the
+// workflow or saving anything.
+func Claimed(cfg *config.Config, t *task.Task, assignee string) *task.Task {
+	claimed := *t
+	claimed.Assignee = assignee
+	claimed.Status = ClaimStatus(cfg)
+	return &claimed
+}
+
+// Unclaim returns t to the ready status with no assignee. It undoes a claim,
+// so it overrides the workflow rather than strand the task.
+func Unclaim(s store.Store, t *task.Task) error {
+	t.Assignee = ""
+	if err := store.ChangeStatus(s, t, s.GetConfig().ReadyStatus(), true); err != nil {
+		return err
+	}
+	return s.Update(t)
+}
+
+// PickNext returns the highest-priority unassigned task in the ready status
+// that has all dependencies met, honoring manual rank within a priority.
+// Returns nil with no error when no tasks are available.
+func PickNext(s store.Store, cfg *config.Config) (*task.Task, error) {
+	ready := cfg.ReadyStatus()
+	if ready == "" {
+		return nil, nil
+	}
+	allTasks, err := s.List(store.Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var available []task.Task
+	for _, t := range allTasks {
+		if t.Status == ready && t.Assignee == "" && !store.IsBlocked(s, &t, allTasks) {
+			available = append(available, t)
+		}
+	}
+
+	if len(available) == 0 {
+		return nil, nil
+	}
+
+	store.SortTasks(available, cfg)
+
+	picked := available[0]
+	return &picked, nil
+}
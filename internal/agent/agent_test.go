@@ -0,0 +1,265 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+func setupStore(t *testing.T, tool config.LLMToolDef) *store.FilesystemStore {
+	t.Helper()
+	s := &store.FilesystemStore{Dir: filepath.Join(t.TempDir(), ".skeeter")}
+	if err := s.Init("test"); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	s.Config.LLM.Tool = "fake"
+	s.Config.LLM.Tools = map[string]config.LLMToolDef{"fake": tool}
+	return s
+}
+
+// recorder collects events; the runner may emit from several goroutines.
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recorder) emit(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) kinds() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var kinds []string
+	for _, e := range r.events {
+		if e.Kind != EventOutput {
+			kinds = append(kinds, e.Kind+":"+e.TaskID)
+		}
+	}
+	return kinds
+}
+
+func TestRunCompletesReadyTasks(t *testing.T) {
+	// cat echoes the prompt back, which gives us output lines to stream.
+	s := setupStore(t, config.LLMToolDef{Command: "cat", PrintFlag: "-"})
+	s.Create(&task.Task{ID: "US-001", Title: "Low", Status: "ready-for-development", Priority: "low"})
+	s.Create(&task.Task{ID: "US-002", Title: "High", Status: "ready-for-development", Priority: "high"})
+	s.Create(&task.Task{ID: "US-003", Title: "Backlog", Status: "backlog"})
+
+	var rec recorder
+	r := NewRunner(s, s.Dir)
+	if err := r.Run(context.Background(), Options{Assign: "bot"}, rec.emit); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := []string{
+		"iteration-start:US-002", "task-done:US-002",
+		"iteration-start:US-001", "task-done:US-001",
+		"stopped:",
+	}
+	if got := rec.kinds(); !slices.Equal(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+
+	var sawOutput bool
+	for _, e := range rec.events {
+		if e.Kind == EventOutput && e.TaskID == "US-002" && e.Line == "## Task US-002: High" {
+			sawOutput = true
+		}
+	}
+	if !sawOutput {
+		t.Error("expected the prompt echoed back as output events")
+	}
+
+	for _, id := range []string{"US-001", "US-002"} {
+		got, _ := s.Get(id)
+		if got.Status != "done" || got.Assignee != "bot" {
+			t.Errorf("%s = %s/%q, want done/bot", id, got.Status, got.Assignee)
+		}
+	}
+	if status := r.Status(); status.Running || !slices.Equal(status.Completed, []string{"US-002", "US-001"}) {
+		t.Errorf("Status = %+v", status)
+	}
+}
+
+func TestRunMax(t *testing.T) {
+	s := setupStore(t, config.LLMToolDef{Command: "cat", PrintFlag: "-"})
+	s.Create(&task.Task{ID: "US-001", Title: "One", Status: "ready-for-development"})
+	s.Create(&task.Task{ID: "US-002", Title: "Two", Status: "ready-for-development"})
+
+	r := NewRunner(s, s.Dir)
+	if err := r.Run(context.Background(), Options{Max: 1}, func(Event) {}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got, _ := s.Get("US-002"); got.Status != "ready-for-development" {
+		t.Errorf("US-002 status = %q, want it left alone", got.Status)
+	}
+}
+
+func TestRunUsesConfiguredStatuses(t *testing.T) {
+	s := setupStore(t, config.LLMToolDef{Command: "cat", PrintFlag: "-"})
+	s.Config.Statuses = []string{"todo", "queued", "doing", "shipped"}
+	s.Create(&task.Task{ID: "US-001", Title: "Queued", Status: "queued"})
+	s.Create(&task.Task{ID: "US-002", Title: "Not yet", Status: "todo"})
+
+	var rec recorder
+	r := NewRunner(s, s.Dir)
+	if err := r.Run(context.Background(), Options{Assign: "bot"}, rec.emit); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := []string{"iteration-start:US-001", "task-done:US-001", "stopped:"}
+	if got := rec.kinds(); !slices.Equal(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	got, _ := s.Get("US-001")
+	if got.Status != "shipped" {
+		t.Errorf("US-001 status = %q, want shipped", got.Status)
+	}
+	if len(got.History) < 2 || got.History[len(got.History)-2].To != "doing" {
+		t.Errorf("history = %+v, want a claim into doing", got.History)
+	}
+	if claimed := Claimed(s.GetConfig(), got, "bot"); claimed.Status != "doing" {
+		t.Errorf("Claimed status = %q, want doing", claimed.Status)
+	}
+
+	if err := Unclaim(s, got); err != nil {
+		t.Fatalf("Unclaim: %v", err)
+	}
+	if got.Status != "queued" || got.Assignee != "" {
+		t.Errorf("after Unclaim = %s/%q, want queued and unassigned", got.Status, got.Assignee)
+	}
+}
+
+func TestClaimNeedsInProgressStatus(t *testing.T) {
+	s := setupStore(t, config.LLMToolDef{})
+	s.Config.Statuses = []string{"open", "closed"}
+	s.Create(&task.Task{ID: "US-001", Title: "Open", Status: "open"})
+
+	tk, _ := s.Get("US-001")
+	if err := Claim(s, tk, "bot"); err == nil {
+		t.Error("Claim succeeded without an in-progress status")
+	}
+}
+
+func TestRunFailureUnclaimsTask(t *testing.T) {
+	s := setupStore(t, config.LLMToolDef{Command: "false"})
+	s.Create(&task.Task{ID: "US-001", Title: "Doomed", Status: "ready-for-development"})
+
+	var rec recorder
+	r := NewRunner(s, s.Dir)
+	if err := r.Run(context.Background(), Options{Assign: "bot"}, rec.emit); err == nil {
+		t.Fatal("expected error from failing tool")
+	}
+
+	want := []string{"iteration-start:US-001", "task-failed:US-001", "stopped:"}
+	if got := rec.kinds(); !slices.Equal(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	got, _ := s.Get("US-001")
+	if got.Status != "ready-for-development" || got.Assignee != "" {
+		t.Errorf("task = %s/%q, want it unclaimed", got.Status, got.Assignee)
+	}
+	if r.Status().Error == "" {
+		t.Error("Status should report the error")
+	}
+}
+
+func TestRunStopsAtWIPLimit(t *testing.T) {
+	s := setupStore(t, config.LLMToolDef{Command: "cat", PrintFlag: "-"})
+	s.Config.WIPLimits = config.WIPLimits{Statuses: map[string]int{"in-progress": 1}}
+	s.Create(&task.Task{ID: "US-001", Title: "Busy", Status: "in-progress", Assignee: "alice"})
+	s.Create(&task.Task{ID: "US-002", Title: "Waiting", Status: "ready-for-development"})
+
+	var rec recorder
+	r := NewRunner(s, s.Dir)
+	if err := r.Run(context.Background(), Options{Assign: "bot"}, rec.emit); err != nil {
+		t.Fatalf("Run: %v, want a clean stop", err)
+	}
+	last := rec.events[len(rec.events)-1]
+	if last.Kind != EventStopped || !strings.Contains(last.Message, "WIP limit") {
+		t.Errorf("last event = %+v, want a stop at the WIP limit", last)
+	}
+	if got, _ := s.Get("US-002"); got.Status != "ready-for-development" || got.Assignee != "" {
+		t.Errorf("US-002 = %s/%q, want it left alone", got.Status, got.Assignee)
+	}
+}
+
+func TestStartAndStop(t *testing.T) {
+	s := setupStore(t, config.LLMToolDef{})
+	s.Create(&task.Task{ID: "US-001", Title: "Slow", Status: "ready-for-development"})
+
+	r := NewRunner(s, s.Dir)
+	started := make(chan struct{})
+	r.exec = func(ctx context.Context, _ *config.LLMToolDef, _, _, _ string, _ func(string, string), _ ...string) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	var rec recorder
+	if err := r.Start(context.Background(), Options{}, rec.emit); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool never started")
+	}
+
+	if status := r.Status(); !status.Running || status.TaskID != "US-001" {
+		t.Errorf("Status while running = %+v", status)
+	}
+	if err := r.Start(context.Background(), Options{}, rec.emit); !errors.Is(err, ErrRunning) {
+		t.Errorf("second Start = %v, want ErrRunning", err)
+	}
+
+	r.Stop()
+	if r.Status().Running {
+		t.Error("still running after Stop")
+	}
+	if got, _ := s.Get("US-001"); got.Status != "ready-for-development" {
+		t.Errorf("status after Stop = %q, want ready-for-development", got.Status)
+	}
+}
+
+func TestPickNext(t *testing.T) {
+	s := setupStore(t, config.LLMToolDef{})
+	s.Create(&task.Task{ID: "US-001", Title: "Blocked", Status: "ready-for-development", Priority: "critical", DependsOn: []string{"US-003"}})
+	s.Create(&task.Task{ID: "US-002", Title: "Free", Status: "ready-for-development", Priority: "low"})
+	s.Create(&task.Task{ID: "US-003", Title: "Dependency", Status: "backlog"})
+
+	got, err := PickNext(s, s.GetConfig())
+	if err != nil {
+		t.Fatalf("PickNext: %v", err)
+	}
+	if got == nil || got.ID != "US-002" {
+		t.Errorf("PickNext = %v, want US-002", got)
+	}
+}
+
+func TestPickNextHonorsRank(t *testing.T) {
+	s := setupStore(t, config.LLMToolDef{})
+	s.Create(&task.Task{ID: "US-001", Title: "Unranked", Status: "ready-for-development", Priority: "high"})
+	s.Create(&task.Task{ID: "US-002", Title: "Ranked later", Status: "ready-for-development", Priority: "high", Rank: "m"})
+	s.Create(&task.Task{ID: "US-003", Title: "Ranked first", Status: "ready-for-development", Priority: "high", Rank: "c"})
+	s.Create(&task.Task{ID: "US-004", Title: "Lower priority", Status: "ready-for-development", Priority: "low", Rank: "a"})
+
+	got, err := PickNext(s, s.GetConfig())
+	if err != nil {
+		t.Fatalf("PickNext: %v", err)
+	}
+	if got == nil || got.ID != "US-003" {
+		t.Errorf("PickNext = %v, want US-003", got)
+	}
+}
@@ -0,0 +1,216 @@
+// Package changelog builds release notes from the tasks that reached the
+// last workflow status in a date range, grouped into sections such as
+// Added and Fixed by their type or tags.
+package changelog
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// Other is the group for tasks no configured group matches.
+const Other = "Other"
+
+// The built-in formats.
+const (
+	FormatMarkdown       = "markdown"
+	FormatKeepAChangelog = "keep-a-changelog"
+)
+
+// Formats lists the built-in format names.
+var Formats = []string{FormatMarkdown, FormatKeepAChangelog}
+
+// Unreleased is the release name used when none is given.
+const Unreleased = "Unreleased"
+
+// Range selects tasks by the day they were done. Both ends are inclusive
+// YYYY-MM-DD dates; an empty end is open.
+type Range struct {
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+	To   string `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// Contains reports whether date falls in r.
+func (r Range) Contains(date string) bool {
+	return (r.From == "" || date >= r.From) && (r.To == "" || date <= r.To)
+}
+
+// Entry is one finished task.
+type Entry struct {
+	ID    string   `json:"id" yaml:"id"`
+	Title string   `json:"title" yaml:"title"`
+	Type  string   `json:"type,omitempty" yaml:"type,omitempty"`
+	Tags  []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Done  string   `json:"done" yaml:"done"`
+	// Commits are the commit SHAs in the task's links.
+	Commits []string `json:"commits,omitempty" yaml:"commits,omitempty"`
+	// Body is kept for summaries, not for rendering.
+	Body string `json:"-" yaml:"-"`
+}
+
+// Group is one section of the changelog. Summary is prose written by an
+// LLM, if one was asked for.
+type Group struct {
+	Title   string  `json:"title" yaml:"title"`
+	Summary string  `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Entries []Entry `json:"entries" yaml:"entries"`
+}
+
+// Changelog is what a template renders.
+type Changelog struct {
+	Project string `json:"project" yaml:"project"`
+	// Release names the release, such as Unreleased; Date is its date.
+	Release string  `json:"release" yaml:"release"`
+	Date    string  `json:"date,omitempty" yaml:"date,omitempty"`
+	Range   Range   `json:"range" yaml:"range"`
+	Groups  []Group `json:"groups" yaml:"groups"`
+}
+
+// Collect returns the tasks in done status that got there within r,
+// grouped by groups. A task joins the first group matching its type, or
+// else the first matching one of its tags, or else Other. Groups keep
+// their configured order, with Other last, and leave out empty ones;
+// entries are ordered by when they were done.
+func Collect(tasks []task.Task, done string, r Range, groups []config.ChangelogGroup) []Group {
+	byTitle := map[string][]Entry{}
+	for _, t := range tasks {
+		if t.Status != done {
+			continue
+		}
+		date := DoneDate(t, done)
+		if !r.Contains(date) {
+			continue
+		}
+		title := groupFor(t, groups)
+		byTitle[title] = append(byTitle[title], Entry{
+			ID:      t.ID,
+			Title:   t.Title,
+			Type:    t.Type,
+			Tags:    t.Tags,
+			Done:    date,
+			Commits: Commits(t.Links),
+			Body:    t.Body,
+		})
+	}
+
+	result := []Group{}
+	titles := make([]string, 0, len(groups)+1)
+	for _, g := range groups {
+		titles = append(titles, g.Title)
+	}
+	for _, title := range append(titles, Other) {
+		entries := byTitle[title]
+		if len(entries) == 0 {
+			continue
+		}
+		delete(byTitle, title)
+		slices.SortStableFunc(entries, func(a, b Entry) int {
+			return strings.Compare(a.Done+a.ID, b.Done+b.ID)
+		})
+		result = append(result, Group{Title: title, Entries: entries})
+	}
+	return result
+}
+
+func groupFor(t task.Task, groups []config.ChangelogGroup) string {
+	if t.Type != "" {
+		for _, g := range groups {
+			if containsFold(g.Match, t.Type) {
+				return g.Title
+			}
+		}
+	}
+	for _, g := range groups {
+		for _, tag := range t.Tags {
+			if containsFold(g.Match, tag) {
+				return g.Title
+			}
+		}
+	}
+	return Other
+}
+
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+// DoneDate returns the day t last moved to done. Tasks with no such move
+// in their history, such as ones created done, count from their last
+// update.
+func DoneDate(t task.Task, done string) string {
+	for i := len(t.History) - 1; i >= 0; i-- {
+		if t.History[i].To == done {
+			return t.History[i].Date
+		}
+	}
+	return t.Updated
+}
+
+var shaPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// Commits returns the links that are commit SHAs, as the git hooks and
+// scan-commits record them.
+func Commits(links []string) []string {
+	var shas []string
+	for _, link := range links {
+		if shaPattern.MatchString(link) {
+			shas = append(shas, link)
+		}
+	}
+	return shas
+}
+
+const markdownTemplate = `## {{.Release}}{{with .Date}} ({{.}}){{end}}
+{{range .Groups}}
+### {{.Title}}
+{{with .Summary}}
+{{.}}
+{{end}}
+{{range .Entries}}- {{.ID}}: {{.Title}}{{with .Commits}} ({{range $i, $c := .}}{{if $i}}, {{end}}{{short $c}}{{end}}){{end}}
+{{end}}{{end}}`
+
+const keepAChangelogTemplate = `## [{{.Release}}]{{with .Date}} - {{.}}{{end}}
+{{range .Groups}}
+### {{.Title}}
+{{with .Summary}}
+{{.}}
+{{end}}
+{{range .Entries}}- {{.Title}} ({{.ID}}{{range .Commits}}, {{short .}}{{end}})
+{{end}}{{end}}`
+
+var builtins = map[string]string{
+	FormatMarkdown:       markdownTemplate,
+	FormatKeepAChangelog: keepAChangelogTemplate,
+}
+
+// Render writes c in a built-in format; empty means markdown.
+func Render(w io.Writer, c *Changelog, format string) error {
+	if format == "" {
+		format = FormatMarkdown
+	}
+	text, ok := builtins[format]
+	if !ok {
+		return fmt.Errorf("unknown changelog format %q (valid: %s)", format, strings.Join(Formats, ", "))
+	}
+	return RenderTemplate(w, c, text)
+}
+
+// RenderTemplate writes c through text, a text/template that can call
+// short to abbreviate a commit SHA and join to join a list.
+func RenderTemplate(w io.Writer, c *Changelog, text string) error {
+	tmpl, err := template.New("changelog").Funcs(template.FuncMap{
+		"short": func(sha string) string { return sha[:min(7, len(sha))] },
+		"join":  strings.Join,
+	}).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid changelog template: %w", err)
+	}
+	return tmpl.Execute(w, c)
+}
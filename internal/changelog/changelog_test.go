@@ -0,0 +1,100 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+const sha = "0123456789abcdef0123456789abcdef01234567"
+
+func testTasks() []task.Task {
+	doneOn := func(date string) []task.Transition {
+		return []task.Transition{{Date: date, From: "in-progress", To: "done"}}
+	}
+	return []task.Task{
+		{ID: "US-001", Title: "Login form", Status: "done", Tags: task.FlowSlice{"feature"}, History: doneOn("2026-03-02"), Links: task.FlowSlice{"https://example.com", sha}},
+		{ID: "US-002", Title: "Crash on logout", Status: "done", Type: "bug", Tags: task.FlowSlice{"feature"}, History: doneOn("2026-03-01")},
+		{ID: "US-003", Title: "Tidy docs", Status: "done", History: doneOn("2026-03-03")},
+		{ID: "US-004", Title: "Too early", Status: "done", Tags: task.FlowSlice{"bug"}, History: doneOn("2026-02-01")},
+		{ID: "US-005", Title: "Still open", Status: "in-progress", Tags: task.FlowSlice{"bug"}},
+		{ID: "US-006", Title: "Created done", Status: "done", Tags: task.FlowSlice{"Feature"}, Updated: "2026-03-01"},
+	}
+}
+
+func TestCollect(t *testing.T) {
+	groups := Collect(testTasks(), "done", Range{From: "2026-03-01"}, config.DefaultChangelogGroups)
+
+	var got []string
+	for _, g := range groups {
+		var ids []string
+		for _, e := range g.Entries {
+			ids = append(ids, e.ID)
+		}
+		got = append(got, g.Title+": "+strings.Join(ids, ","))
+	}
+	// The type wins over tags, and entries are in the order they were done.
+	want := "Added: US-006,US-001 | Fixed: US-002 | Other: US-003"
+	if strings.Join(got, " | ") != want {
+		t.Errorf("groups = %s, want %s", strings.Join(got, " | "), want)
+	}
+	if c := groups[0].Entries[1].Commits; len(c) != 1 || c[0] != sha {
+		t.Errorf("commits = %v, want only the SHA link", c)
+	}
+
+	if groups := Collect(testTasks(), "done", Range{From: "2026-03-02", To: "2026-03-02"}, config.DefaultChangelogGroups); len(groups) != 1 || groups[0].Entries[0].ID != "US-001" {
+		t.Errorf("one-day range = %+v", groups)
+	}
+}
+
+func TestRender(t *testing.T) {
+	c := &Changelog{
+		Release: "1.5.0",
+		Date:    "2026-03-04",
+		Groups:  Collect(testTasks(), "done", Range{From: "2026-03-01"}, config.DefaultChangelogGroups),
+	}
+	c.Groups[1].Summary = "Logging out no longer crashes."
+
+	var b strings.Builder
+	if err := Render(&b, c, FormatKeepAChangelog); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	want := `## [1.5.0] - 2026-03-04
+
+### Added
+
+- Created done (US-006)
+- Login form (US-001, 0123456)
+
+### Fixed
+
+Logging out no longer crashes.
+
+- Crash on logout (US-002)
+
+### Other
+
+- Tidy docs (US-003)
+`
+	if b.String() != want {
+		t.Errorf("keep-a-changelog =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := Render(&b, c, ""); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if !strings.HasPrefix(b.String(), "## 1.5.0 (2026-03-04)\n") || !strings.Contains(b.String(), "- US-001: Login form (0123456)\n") {
+		t.Errorf("markdown =\n%s", b.String())
+	}
+
+	if err := Render(&b, c, "html"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	b.Reset()
+	if err := RenderTemplate(&b, c, "{{range .Groups}}{{.Title}} {{end}}"); err != nil || b.String() != "Added Fixed Other " {
+		t.Errorf("RenderTemplate = %q, %v", b.String(), err)
+	}
+}
@@ -0,0 +1,44 @@
+// Package commitref finds task references in commit messages: bare IDs
+// such as "US-012", "Refs US-012", and closing keywords such as
+// "Fixes US-012".
+package commitref
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Ref is a task a commit message refers to. Fixes is set when the message
+// says the commit finishes the task.
+type Ref struct {
+	ID    string `json:"id" yaml:"id"`
+	Fixes bool   `json:"fixes" yaml:"fixes"`
+}
+
+// fixKeywords mark the IDs that follow them as finished, as on GitHub.
+var fixKeywords = []string{"fix", "fixes", "fixed", "close", "closes", "closed", "resolve", "resolves", "resolved"}
+
+// Parse returns the tasks with the given ID prefix that message refers
+// to, in order of first mention. A keyword applies to the list of IDs right
+// after it, as in "Fixes US-001, US-002 and US-003".
+func Parse(message, prefix string) []Ref {
+	id := regexp.QuoteMeta(prefix) + `-[0-9A-Za-z]+`
+	pattern := regexp.MustCompile(`(?i)(?:\b([a-z]+):?\s+)?\b(` + id + `(?:\s*(?:,|\band\b)\s*` + id + `)*)\b`)
+	idPattern := regexp.MustCompile(`(?i)\b` + id + `\b`)
+
+	var refs []Ref
+	for _, m := range pattern.FindAllStringSubmatch(message, -1) {
+		fixes := slices.Contains(fixKeywords, strings.ToLower(m[1]))
+		for _, found := range idPattern.FindAllString(m[2], -1) {
+			found = strings.ToUpper(found)
+			i := slices.IndexFunc(refs, func(r Ref) bool { return r.ID == found })
+			if i < 0 {
+				refs = append(refs, Ref{ID: found, Fixes: fixes})
+			} else if fixes {
+				refs[i].Fixes = true
+			}
+		}
+	}
+	return refs
+}
@@ -0,0 +1,28 @@
+package commitref
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		message string
+		want    []Ref
+	}{
+		{"Add login form (US-012)", []Ref{{ID: "US-012"}}},
+		{"Refs US-012: wire up the API", []Ref{{ID: "US-012"}}},
+		{"Fixes US-012", []Ref{{ID: "US-012", Fixes: true}}},
+		{"closes: us-7k3m9q", []Ref{{ID: "US-7K3M9Q", Fixes: true}}},
+		{"Resolves US-001, US-002 and US-003\n\nRefs US-004",
+			[]Ref{{ID: "US-001", Fixes: true}, {ID: "US-002", Fixes: true}, {ID: "US-003", Fixes: true}, {ID: "US-004"}}},
+		{"Start US-005\n\nFixes US-005", []Ref{{ID: "US-005", Fixes: true}}},
+		{"Bump BUS-001 and USB-3 handling", nil},
+		{"No task here", nil},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.message, "US"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.message, got, tt.want)
+		}
+	}
+}
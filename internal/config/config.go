@@ -35,12 +35,201 @@ type LLMConfig struct {
 	WorkArgs []string              `yaml:"work_args,omitempty" json:"work_args,omitempty"`
 }
 
+// IDConfig selects how new task IDs are allocated. See the id package for
+// the available strategies.
+type IDConfig struct {
+	Strategy  string `yaml:"strategy,omitempty" json:"strategy"`
+	BlockSize int    `yaml:"block_size,omitempty" json:"block_size,omitempty"`
+}
+
+// WorkflowConfig restricts how tasks move between statuses. Both maps are
+// keyed by status, and a status without an entry is unrestricted.
+type WorkflowConfig struct {
+	// Transitions lists the statuses a task may move to from each status.
+	Transitions map[string][]string `yaml:"transitions,omitempty" json:"transitions,omitempty"`
+	// Guards lists the conditions a task must meet to enter each status,
+	// by name; see Guards.
+	Guards map[string][]string `yaml:"guards,omitempty" json:"guards,omitempty"`
+}
+
+// WIPLimits caps how much work may be in flight. Statuses caps the tasks
+// in each status; in config.yaml they sit next to per_assignee, as in
+//
+//	wip_limits:
+//	  in-progress: 3
+//	  per_assignee: 2
+type WIPLimits struct {
+	// PerAssignee caps the tasks one person holds in the statuses between
+	// the first and the last.
+	PerAssignee int            `yaml:"per_assignee,omitempty" json:"per_assignee,omitempty"`
+	Statuses    map[string]int `yaml:",inline" json:"statuses,omitempty"`
+}
+
+// InFlight reports whether status counts toward a per-assignee WIP limit:
+// it is neither the first status nor the last.
+func (c *Config) InFlight(status string) bool {
+	i := slices.Index(c.Statuses, status)
+	return i > 0 && i < len(c.Statuses)-1
+}
+
+// ReadyStatus returns the status of tasks queued for work: the second
+// status, after the backlog, or "" when there are fewer than two.
+func (c *Config) ReadyStatus() string {
+	if len(c.Statuses) < 2 {
+		return ""
+	}
+	return c.Statuses[1]
+}
+
+// InProgressStatus returns the status tasks move to when work on them
+// starts: the third status, after the backlog and the ready queue, or ""
+// when there are fewer than three.
+func (c *Config) InProgressStatus() string {
+	if len(c.Statuses) < 3 {
+		return ""
+	}
+	return c.Statuses[2]
+}
+
+// DoneStatus returns the last status, or "" when none are configured.
+func (c *Config) DoneStatus() string {
+	if len(c.Statuses) == 0 {
+		return ""
+	}
+	return c.Statuses[len(c.Statuses)-1]
+}
+
+// Webhook is an endpoint task events are POSTed to.
+type Webhook struct {
+	URL string `yaml:"url" json:"url"`
+	// Events limits the events sent, by name; empty means all of them.
+	Events []string `yaml:"events,omitempty" json:"events,omitempty"`
+	// Secret, if set, signs each request body with HMAC-SHA256. $VAR
+	// references are expanded from the environment, so the secret itself
+	// needn't be committed.
+	Secret string `yaml:"secret,omitempty" json:"-"`
+}
+
+// Wants reports whether the webhook subscribes to event.
+func (w Webhook) Wants(event string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+// SigningKey returns the secret with environment references expanded.
+func (w Webhook) SigningKey() string {
+	return os.ExpandEnv(w.Secret)
+}
+
+// CommitsConfig maps task references in commit messages to statuses. An
+// empty status means the default; "none" links the commit without moving
+// the task.
+type CommitsConfig struct {
+	// Refs is the status for tasks a commit mentions; default the
+	// workflow's in-progress status (see InProgressStatus).
+	Refs string `yaml:"refs,omitempty" json:"refs,omitempty"`
+	// Fixes is the status for tasks a commit fixes; default the last status.
+	Fixes string `yaml:"fixes,omitempty" json:"fixes,omitempty"`
+}
+
+// CommitStatus returns the status a commit moves a task it refers to into,
+// or "" if it shouldn't move the task. Unset, it falls back to the
+// workflow's in-progress or done status, and so to "" for a workflow too
+// short to have one.
+func (c *Config) CommitStatus(fixes bool) string {
+	status, fallback := c.Commits.Refs, c.InProgressStatus()
+	if fixes {
+		status, fallback = c.Commits.Fixes, c.DoneStatus()
+	}
+	switch status {
+	case "":
+		return fallback
+	case "none":
+		return ""
+	}
+	return status
+}
+
+// BranchConfig controls the git branches skeeter creates for tasks.
+type BranchConfig struct {
+	// Pattern is a text/template for branch names; see the gitinfo
+	// package. Empty means DefaultBranchPattern.
+	Pattern string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	// Main is the branch task branches merge into. Empty means the
+	// remote's default branch, or main or master.
+	Main string `yaml:"main,omitempty" json:"main,omitempty"`
+}
+
+// DefaultBranchPattern names a branch after the task's ID and title.
+const DefaultBranchPattern = "{{id}}-{{slug title}}"
+
+// ChangelogConfig controls skeeter changelog.
+//
+//	changelog:
+//	  format: keep-a-changelog
+//	  groups:
+//	    - title: Fixed
+//	      match: [bug, fix]
+type ChangelogConfig struct {
+	// Format is the built-in template used without --format; empty means
+	// markdown.
+	Format string `yaml:"format,omitempty" json:"format,omitempty"`
+	// Groups replaces DefaultChangelogGroups.
+	Groups []ChangelogGroup `yaml:"groups,omitempty" json:"groups,omitempty"`
+}
+
+// ChangelogGroup is one section of a changelog, holding the tasks whose
+// type or tags include any of Match.
+type ChangelogGroup struct {
+	Title string   `yaml:"title" json:"title"`
+	Match []string `yaml:"match" json:"match"`
+}
+
+// DefaultChangelogGroups follow the sections of keepachangelog.com.
+var DefaultChangelogGroups = []ChangelogGroup{
+	{Title: "Added", Match: []string{"feature", "feat", "story"}},
+	{Title: "Changed", Match: []string{"change", "enhancement", "improvement", "refactor"}},
+	{Title: "Deprecated", Match: []string{"deprecation", "deprecated"}},
+	{Title: "Removed", Match: []string{"removal", "removed"}},
+	{Title: "Fixed", Match: []string{"bug", "fix", "bugfix"}},
+	{Title: "Security", Match: []string{"security"}},
+}
+
+// ChangelogGroups returns the configured changelog groups, or the defaults.
+func (c *Config) ChangelogGroups() []ChangelogGroup {
+	if len(c.Changelog.Groups) > 0 {
+		return c.Changelog.Groups
+	}
+	return DefaultChangelogGroups
+}
+
+// The workflow guards.
+const (
+	// GuardNotBlocked requires every depends_on task to be done.
+	GuardNotBlocked = "not_blocked"
+	// GuardAssignee requires an assignee.
+	GuardAssignee = "assignee"
+	// GuardCriteriaChecked requires every acceptance-criteria checkbox to
+	// be ticked.
+	GuardCriteriaChecked = "criteria_checked"
+)
+
+// Guards are the guard names workflow.guards accepts.
+var Guards = []string{GuardNotBlocked, GuardAssignee, GuardCriteriaChecked}
+
 type Config struct {
-	Project    ProjectConfig `yaml:"project" json:"project"`
-	Statuses   []string      `yaml:"statuses" json:"statuses"`
-	Priorities []string      `yaml:"priorities" json:"priorities"`
-	AutoCommit bool          `yaml:"auto_commit" json:"auto_commit"`
-	LLM        LLMConfig     `yaml:"llm,omitempty" json:"llm"`
+	Project    ProjectConfig   `yaml:"project" json:"project"`
+	Statuses   []string        `yaml:"statuses" json:"statuses"`
+	Priorities []string        `yaml:"priorities" json:"priorities"`
+	AutoCommit bool            `yaml:"auto_commit" json:"auto_commit"`
+	Index      bool            `yaml:"index,omitempty" json:"index"`
+	IDs        IDConfig        `yaml:"ids,omitempty" json:"ids"`
+	LLM        LLMConfig       `yaml:"llm,omitempty" json:"llm"`
+	Workflow   WorkflowConfig  `yaml:"workflow,omitempty" json:"workflow"`
+	WIPLimits  WIPLimits       `yaml:"wip_limits,omitempty" json:"wip_limits"`
+	Webhooks   []Webhook       `yaml:"webhooks,omitempty" json:"webhooks,omitempty"`
+	Commits    CommitsConfig   `yaml:"commits,omitempty" json:"commits"`
+	Branches   BranchConfig    `yaml:"branches,omitempty" json:"branches"`
+	Changelog  ChangelogConfig `yaml:"changelog,omitempty" json:"changelog"`
 }
 
 func Default() *Config {
@@ -113,3 +302,13 @@ func (c *Config) PriorityRank(priority string) int {
 	}
 	return idx
 }
+
+// CanTransition reports whether the workflow lets a task move from one
+// status to another, and if not, which statuses it may move to instead.
+func (c *Config) CanTransition(from, to string) (bool, []string) {
+	allowed, ok := c.Workflow.Transitions[from]
+	if !ok || from == to || slices.Contains(allowed, to) {
+		return true, nil
+	}
+	return false, allowed
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -301,3 +303,106 @@ func TestResolveTool(t *testing.T) {
 		}
 	})
 }
+
+func TestWorkflowAndWIPLimits(t *testing.T) {
+	dir := t.TempDir()
+	configContent := `statuses: [backlog, ready, in-progress, review, done]
+workflow:
+  transitions:
+    backlog: [ready]
+wip_limits:
+  in-progress: 3
+  per_assignee: 2
+`
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.WIPLimits.PerAssignee != 2 || cfg.WIPLimits.Statuses["in-progress"] != 3 || len(cfg.WIPLimits.Statuses) != 1 {
+		t.Errorf("WIPLimits = %+v", cfg.WIPLimits)
+	}
+	if ok, allowed := cfg.CanTransition("backlog", "done"); ok || !slices.Equal(allowed, []string{"ready"}) {
+		t.Errorf("CanTransition(backlog, done) = %v, %v", ok, allowed)
+	}
+	if ok, _ := cfg.CanTransition("review", "backlog"); !ok {
+		t.Error("statuses without transitions should be unrestricted")
+	}
+	for status, want := range map[string]bool{"backlog": false, "ready": true, "review": true, "done": false} {
+		if got := cfg.InFlight(status); got != want {
+			t.Errorf("InFlight(%s) = %v, want %v", status, got, want)
+		}
+	}
+
+	if err := cfg.Save(dir); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	saved, err := os.ReadFile(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(saved), "wip_limits:\n    per_assignee: 2\n    in-progress: 3\n") {
+		t.Errorf("saved config:\n%s", saved)
+	}
+}
+
+func TestInProgressAndDoneStatus(t *testing.T) {
+	cfg := Default()
+	if got := cfg.ReadyStatus(); got != "ready-for-development" {
+		t.Errorf("ReadyStatus = %q, want ready-for-development", got)
+	}
+	if got := cfg.InProgressStatus(); got != "in-progress" {
+		t.Errorf("InProgressStatus = %q, want in-progress", got)
+	}
+	if got := cfg.DoneStatus(); got != "done" {
+		t.Errorf("DoneStatus = %q, want done", got)
+	}
+
+	cfg.Statuses = []string{"open", "closed"}
+	if got := cfg.InProgressStatus(); got != "" {
+		t.Errorf("InProgressStatus with two statuses = %q, want none", got)
+	}
+	cfg.Statuses = []string{"open"}
+	if got := cfg.ReadyStatus(); got != "" {
+		t.Errorf("ReadyStatus with one status = %q, want none", got)
+	}
+	cfg.Statuses = nil
+	if got := cfg.DoneStatus(); got != "" {
+		t.Errorf("DoneStatus with no statuses = %q, want none", got)
+	}
+}
+
+func TestCommitStatus(t *testing.T) {
+	cfg := Default()
+	if got := cfg.CommitStatus(false); got != "in-progress" {
+		t.Errorf("default refs status = %q, want in-progress", got)
+	}
+	if got := cfg.CommitStatus(true); got != "done" {
+		t.Errorf("default fixes status = %q, want the last status", got)
+	}
+
+	cfg.Commits = CommitsConfig{Refs: "none", Fixes: "in-progress"}
+	if got := cfg.CommitStatus(false); got != "" {
+		t.Errorf("refs: none = %q, want no move", got)
+	}
+	if got := cfg.CommitStatus(true); got != "in-progress" {
+		t.Errorf("fixes = %q, want in-progress", got)
+	}
+
+	cfg = Default()
+	cfg.Statuses = []string{"todo", "next", "doing", "shipped"}
+	if got := cfg.CommitStatus(false); got != "doing" {
+		t.Errorf("refs with a custom workflow = %q, want doing", got)
+	}
+	if got := cfg.CommitStatus(true); got != "shipped" {
+		t.Errorf("fixes with a custom workflow = %q, want shipped", got)
+	}
+
+	cfg.Statuses = nil
+	if got := cfg.CommitStatus(true); got != "" {
+		t.Errorf("fixes with no statuses = %q, want no move", got)
+	}
+}
@@ -0,0 +1,239 @@
+// Package doctor finds and repairs problems in task files that List would
+// otherwise skip or silently accept.
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/graph"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// Issue kinds reported by Check.
+const (
+	KindUnparseable        = "unparseable"
+	KindIDMismatch         = "id-mismatch"
+	KindDuplicateID        = "duplicate-id"
+	KindInvalidStatus      = "invalid-status"
+	KindInvalidPriority    = "invalid-priority"
+	KindDanglingDependency = "dangling-dependency"
+	KindCycle              = "cycle"
+	KindInvalidDue         = "invalid-due"
+)
+
+// dueLayouts are the alternative date formats --fix can normalize.
+var dueLayouts = []string{
+	"2006/01/02",
+	"2006.01.02",
+	"2006-1-2",
+	"2006-01-02T15:04:05Z07:00",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+}
+
+type Issue struct {
+	Kind    string `json:"kind"`
+	File    string `json:"file"`
+	TaskID  string `json:"task_id,omitempty"`
+	Message string `json:"message"`
+	Fixable bool   `json:"fixable"`
+}
+
+// Check inspects every task file and returns the problems found, ordered by
+// file name.
+func Check(cfg *config.Config, files []store.TaskFile) []Issue {
+	var issues []Issue
+
+	byID := make(map[string][]string)
+	known := make(map[string]bool)
+	for _, f := range files {
+		if f.Task != nil {
+			byID[f.Task.ID] = append(byID[f.Task.ID], f.Name)
+			known[f.Task.ID] = true
+		}
+	}
+
+	for _, f := range files {
+		if f.Err != nil {
+			issues = append(issues, Issue{
+				Kind:    KindUnparseable,
+				File:    f.Name,
+				Message: f.Err.Error(),
+			})
+			continue
+		}
+		issues = append(issues, checkTask(cfg, f.Name, f.Task, known)...)
+	}
+
+	for id, names := range byID {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			issues = append(issues, Issue{
+				Kind:    KindDuplicateID,
+				File:    name,
+				TaskID:  id,
+				Message: fmt.Sprintf("id %s is used by %s", id, strings.Join(names, ", ")),
+			})
+		}
+	}
+
+	for _, cycle := range findCycles(files) {
+		issues = append(issues, Issue{
+			Kind:    KindCycle,
+			File:    cycle[0] + ".md",
+			TaskID:  cycle[0],
+			Message: "circular dependency: " + strings.Join(append(cycle, cycle[0]), " -> "),
+		})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].File < issues[j].File
+	})
+	return issues
+}
+
+func checkTask(cfg *config.Config, name string, t *task.Task, known map[string]bool) []Issue {
+	var issues []Issue
+	add := func(kind, msg string, fixable bool) {
+		issues = append(issues, Issue{Kind: kind, File: name, TaskID: t.ID, Message: msg, Fixable: fixable})
+	}
+
+	if stem := strings.TrimSuffix(name, filepath.Ext(name)); t.ID != stem {
+		add(KindIDMismatch, fmt.Sprintf("frontmatter id %q does not match file name", t.ID), true)
+	}
+
+	if !cfg.ValidStatus(t.Status) {
+		_, ok := normalizeChoice(t.Status, cfg.Statuses)
+		add(KindInvalidStatus, fmt.Sprintf("invalid status %q (valid: %s)", t.Status, strings.Join(cfg.Statuses, ", ")), ok)
+	}
+
+	if t.Priority != "" && !cfg.ValidPriority(t.Priority) {
+		_, ok := normalizeChoice(t.Priority, cfg.Priorities)
+		add(KindInvalidPriority, fmt.Sprintf("invalid priority %q (valid: %s)", t.Priority, strings.Join(cfg.Priorities, ", ")), ok)
+	}
+
+	for _, dep := range t.DependsOn {
+		if !known[dep] {
+			add(KindDanglingDependency, fmt.Sprintf("depends on %s, which does not exist", dep), true)
+		}
+	}
+
+	if t.Due != "" {
+		if _, err := time.Parse("2006-01-02", t.Due); err != nil {
+			_, ok := normalizeDue(t.Due)
+			add(KindInvalidDue, fmt.Sprintf("invalid due date %q (format: YYYY-MM-DD)", t.Due), ok)
+		}
+	}
+
+	return issues
+}
+
+// normalizeChoice matches value against choices ignoring case and treating
+// spaces and underscores as dashes, e.g. "In Progress" -> "in-progress".
+func normalizeChoice(value string, choices []string) (string, bool) {
+	norm := func(s string) string {
+		s = strings.ToLower(strings.TrimSpace(s))
+		return strings.NewReplacer(" ", "-", "_", "-").Replace(s)
+	}
+	for _, c := range choices {
+		if norm(c) == norm(value) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+func normalizeDue(due string) (string, bool) {
+	for _, layout := range dueLayouts {
+		if d, err := time.Parse(layout, strings.TrimSpace(due)); err == nil {
+			return d.Format("2006-01-02"), true
+		}
+	}
+	return "", false
+}
+
+// findCycles returns each dependency cycle once, starting at its smallest
+// ID.
+func findCycles(files []store.TaskFile) [][]string {
+	var tasks []task.Task
+	for _, f := range files {
+		if f.Task != nil {
+			tasks = append(tasks, *f.Task)
+		}
+	}
+	return graph.Build(tasks, "").Cycles()
+}
+
+// Fix applies every mechanical fix to the parsed task files and saves the
+// tasks that changed. It returns the issues that were fixed.
+func Fix(s store.Store, files []store.TaskFile) ([]Issue, error) {
+	cfg := s.GetConfig()
+
+	// Dependencies are checked against the IDs tasks will have once fixed,
+	// which are their file names, so fixing a mismatched ID doesn't make
+	// references to it dangle.
+	known := make(map[string]bool)
+	for _, f := range files {
+		if f.Task != nil {
+			known[strings.TrimSuffix(f.Name, filepath.Ext(f.Name))] = true
+		}
+	}
+
+	var fixed []Issue
+	for _, f := range files {
+		if f.Task == nil {
+			continue
+		}
+		t := *f.Task
+		var applied []Issue
+		for _, issue := range checkTask(cfg, f.Name, &t, known) {
+			if !issue.Fixable {
+				continue
+			}
+			switch issue.Kind {
+			case KindIDMismatch:
+				t.ID = strings.TrimSuffix(f.Name, filepath.Ext(f.Name))
+			case KindInvalidStatus:
+				// Correcting how the status is spelled isn't a move through
+				// the workflow, so it is forced; history notes what that
+				// skipped.
+				status, _ := normalizeChoice(t.Status, cfg.Statuses)
+				if err := store.ChangeStatus(s, &t, status, true); err != nil {
+					return fixed, fmt.Errorf("fixing %s: %w", f.Name, err)
+				}
+			case KindInvalidPriority:
+				t.Priority, _ = normalizeChoice(t.Priority, cfg.Priorities)
+			case KindDanglingDependency:
+				var kept task.FlowSlice
+				for _, dep := range t.DependsOn {
+					if known[dep] {
+						kept = append(kept, dep)
+					}
+				}
+				t.DependsOn = kept
+			case KindInvalidDue:
+				t.Due, _ = normalizeDue(t.Due)
+			}
+			applied = append(applied, issue)
+		}
+
+		if len(applied) == 0 {
+			continue
+		}
+		if err := s.Update(&t); err != nil {
+			return fixed, fmt.Errorf("fixing %s: %w", f.Name, err)
+		}
+		fixed = append(fixed, applied...)
+	}
+	return fixed, nil
+}
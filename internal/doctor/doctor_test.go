@@ -0,0 +1,161 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+func setupStore(t *testing.T) *store.FilesystemStore {
+	t.Helper()
+	s := &store.FilesystemStore{Dir: filepath.Join(t.TempDir(), ".skeeter")}
+	if err := s.Init("test-project"); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	return s
+}
+
+func kinds(issues []Issue) map[string]int {
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		counts[issue.Kind]++
+	}
+	return counts
+}
+
+func TestCheckCleanTasks(t *testing.T) {
+	s := setupStore(t)
+	files := []store.TaskFile{
+		{Name: "US-001.md", Task: &task.Task{ID: "US-001", Status: "backlog", Priority: "high"}},
+		{Name: "US-002.md", Task: &task.Task{ID: "US-002", Status: "done", DependsOn: []string{"US-001"}, Due: "2026-03-01"}},
+	}
+	if issues := Check(s.GetConfig(), files); len(issues) != 0 {
+		t.Errorf("Check = %v, want no issues", issues)
+	}
+}
+
+func TestCheckFindsProblems(t *testing.T) {
+	s := setupStore(t)
+	files := []store.TaskFile{
+		{Name: "US-001.md", Err: errors.New("missing frontmatter")},
+		{Name: "US-002.md", Task: &task.Task{ID: "US-003", Status: "In Progress"}},
+		{Name: "US-003.md", Task: &task.Task{ID: "US-003", Status: "backlog", Priority: "urgent"}},
+		{Name: "US-004.md", Task: &task.Task{ID: "US-004", Status: "backlog", DependsOn: []string{"US-099"}, Due: "2026/03/01"}},
+	}
+
+	got := kinds(Check(s.GetConfig(), files))
+	want := map[string]int{
+		KindUnparseable:        1,
+		KindIDMismatch:         1,
+		KindDuplicateID:        2,
+		KindInvalidStatus:      1,
+		KindInvalidPriority:    1,
+		KindDanglingDependency: 1,
+		KindInvalidDue:         1,
+	}
+	for kind, n := range want {
+		if got[kind] != n {
+			t.Errorf("%s issues = %d, want %d", kind, got[kind], n)
+		}
+	}
+}
+
+func TestCheckFindsCycles(t *testing.T) {
+	s := setupStore(t)
+	files := []store.TaskFile{
+		{Name: "US-001.md", Task: &task.Task{ID: "US-001", Status: "backlog", DependsOn: []string{"US-002"}}},
+		{Name: "US-002.md", Task: &task.Task{ID: "US-002", Status: "backlog", DependsOn: []string{"US-003"}}},
+		{Name: "US-003.md", Task: &task.Task{ID: "US-003", Status: "backlog", DependsOn: []string{"US-001"}}},
+	}
+
+	issues := Check(s.GetConfig(), files)
+	if len(issues) != 1 || issues[0].Kind != KindCycle {
+		t.Fatalf("Check = %v, want a single cycle", issues)
+	}
+	want := "circular dependency: US-001 -> US-002 -> US-003 -> US-001"
+	if issues[0].Message != want {
+		t.Errorf("message = %q, want %q", issues[0].Message, want)
+	}
+}
+
+func TestFix(t *testing.T) {
+	s := setupStore(t)
+	s.Create(&task.Task{ID: "US-001", Title: "Base", Status: "backlog"})
+
+	// Write a task whose frontmatter needs every mechanical fix.
+	content, _ := task.Marshal(&task.Task{
+		ID:        "US-9",
+		Title:     "Broken",
+		Status:    "Ready_For_Development",
+		Priority:  "HIGH",
+		DependsOn: []string{"US-001", "US-404"},
+		Due:       "Mar 1, 2026",
+	})
+	os.WriteFile(filepath.Join(s.Dir, "tasks", "US-002.md"), []byte(content), 0644)
+
+	files, err := s.Scan()
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	fixed, err := Fix(s, files)
+	if err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if len(fixed) != 5 {
+		t.Errorf("fixed %d issues, want 5: %v", len(fixed), fixed)
+	}
+
+	got, err := s.Get("US-002")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.ID != "US-002" || got.Status != "ready-for-development" || got.Priority != "high" || got.Due != "2026-03-01" {
+		t.Errorf("fixed task = %+v", got)
+	}
+	if len(got.DependsOn) != 1 || got.DependsOn[0] != "US-001" {
+		t.Errorf("depends_on = %v, want [US-001]", got.DependsOn)
+	}
+
+	files, _ = s.Scan()
+	if issues := Check(s.GetConfig(), files); len(issues) != 0 {
+		t.Errorf("issues after Fix = %v", issues)
+	}
+}
+
+func TestFixKeepsDependenciesOnFixedIDs(t *testing.T) {
+	s := setupStore(t)
+
+	// US-003.md says it is US-3; US-004 depends on it by its file name.
+	content, _ := task.Marshal(&task.Task{ID: "US-3", Title: "Renamed", Status: "backlog", Priority: "medium"})
+	os.WriteFile(filepath.Join(s.Dir, "tasks", "US-003.md"), []byte(content), 0644)
+	s.Create(&task.Task{ID: "US-004", Title: "Dependent", Status: "backlog", Priority: "medium", DependsOn: []string{"US-003"}})
+
+	files, err := s.Scan()
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	fixed, err := Fix(s, files)
+	if err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if got := kinds(fixed); got[KindIDMismatch] != 1 || got[KindDanglingDependency] != 0 {
+		t.Errorf("fixed = %v, want only the ID mismatch", fixed)
+	}
+
+	got, err := s.Get("US-004")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(got.DependsOn) != 1 || got.DependsOn[0] != "US-003" {
+		t.Errorf("depends_on = %v, want [US-003]", got.DependsOn)
+	}
+
+	files, _ = s.Scan()
+	if issues := Check(s.GetConfig(), files); len(issues) != 0 {
+		t.Errorf("issues after Fix = %v", issues)
+	}
+}
@@ -0,0 +1,283 @@
+// Package gitinfo names task branches and reads what the local git
+// repository knows about a task: its branch's position relative to the
+// main branch and the commits that mention it. Everything comes from local
+// git, without contacting a remote.
+package gitinfo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/andybarilla/skeeter/internal/commitref"
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// maxSlug bounds how much of a title a branch name carries.
+const maxSlug = 40
+
+// BranchName renders pattern, a text/template, for t. Patterns call id,
+// title, assignee and priority for the task's fields and slug, lower and
+// upper to transform them, as in "{{id}}-{{slug title}}" or
+// "{{lower assignee}}/{{id}}". An empty pattern means
+// config.DefaultBranchPattern.
+func BranchName(pattern string, t *task.Task) (string, error) {
+	if pattern == "" {
+		pattern = config.DefaultBranchPattern
+	}
+	tmpl, err := template.New("branch").Funcs(template.FuncMap{
+		"id":       func() string { return t.ID },
+		"title":    func() string { return t.Title },
+		"assignee": func() string { return t.Assignee },
+		"priority": func() string { return t.Priority },
+		"slug":     Slug,
+		"lower":    strings.ToLower,
+		"upper":    strings.ToUpper,
+	}).Parse(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, nil); err != nil {
+		return "", fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+	}
+	name := strings.Trim(b.String(), "-/")
+	if name == "" {
+		return "", fmt.Errorf("branch pattern %q gives %s an empty name", pattern, t.ID)
+	}
+	return name, nil
+}
+
+// Slug lowercases s and joins its words with hyphens, keeping letters and
+// digits only, cut at a word boundary to keep branch names short.
+func Slug(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9')
+	})
+	slug := ""
+	for _, w := range words {
+		next := w
+		if slug != "" {
+			next = slug + "-" + w
+		}
+		if len(next) > maxSlug && slug != "" {
+			break
+		}
+		slug = next
+	}
+	return slug
+}
+
+// Commit is a commit that mentions a task.
+type Commit struct {
+	SHA     string `json:"sha" yaml:"sha"`
+	Date    string `json:"date" yaml:"date"`
+	Author  string `json:"author" yaml:"author"`
+	Subject string `json:"subject" yaml:"subject"`
+}
+
+// Status is what local git knows about a task.
+type Status struct {
+	// Branch is the task's branch, if it has one, and Exists whether it
+	// is still in the repository.
+	Branch string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Exists bool   `json:"exists" yaml:"exists"`
+	// Main is the branch Ahead, Behind and Merged compare against.
+	Main   string `json:"main,omitempty" yaml:"main,omitempty"`
+	Ahead  int    `json:"ahead" yaml:"ahead"`
+	Behind int    `json:"behind" yaml:"behind"`
+	// Merged is set once every commit on the branch is in main. A branch
+	// that never moved looks the same to git, so one still at the commit
+	// it was created from only counts once main has moved past it.
+	Merged bool `json:"merged" yaml:"merged"`
+	// Commits are the latest commits on any local branch that mention the
+	// task, newest first.
+	Commits []Commit `json:"commits" yaml:"commits"`
+}
+
+// ErrNotRepository is returned by Inspect outside a git work tree.
+var ErrNotRepository = errors.New("not a git repository")
+
+// Inspect reports on t from the git repository in dir, listing at most
+// limit commits. main names the main branch; empty means MainBranch.
+func Inspect(dir string, t *task.Task, prefix, main string, limit int) (*Status, error) {
+	if _, err := git(dir, "rev-parse", "--git-dir"); err != nil {
+		return nil, ErrNotRepository
+	}
+	st := &Status{Branch: t.Branch}
+
+	st.Commits = mentioning(dir, t.ID, prefix, limit)
+
+	if t.Branch == "" || !BranchExists(dir, t.Branch) {
+		return st, nil
+	}
+	st.Exists = true
+	if main == "" {
+		main = MainBranch(dir)
+	}
+	if main == "" || main == t.Branch {
+		return st, nil
+	}
+	st.Main = main
+
+	counts, err := git(dir, "rev-list", "--left-right", "--count", main+"..."+t.Branch)
+	if err != nil {
+		return nil, err
+	}
+	if fields := strings.Fields(counts); len(fields) == 2 {
+		st.Behind, _ = strconv.Atoi(fields[0])
+		st.Ahead, _ = strconv.Atoi(fields[1])
+	}
+	// A branch with nothing main lacks is merged once it has moved since it
+	// was created; one that never moved just hasn't been worked on, however
+	// far main has gone on.
+	if st.Ahead == 0 {
+		tip, _ := git(dir, "rev-parse", t.Branch)
+		st.Merged = tip != createdAt(dir, t.Branch, tip)
+	}
+	return st, nil
+}
+
+// createdAt returns the commit branch was created from, as its reflog
+// remembers it, or tip when the reflog doesn't go back that far.
+func createdAt(dir, branch, tip string) string {
+	out, err := git(dir, "reflog", "show", "--format=%H", "refs/heads/"+branch)
+	if err != nil || out == "" {
+		return tip
+	}
+	lines := strings.Split(out, "\n")
+	return lines[len(lines)-1]
+}
+
+// mentioning returns the latest commits on local branches whose messages
+// refer to id, newest first.
+func mentioning(dir, id, prefix string, limit int) []Commit {
+	// --grep narrows the search cheaply; commitref drops near misses such
+	// as US-0012 for US-001.
+	out, err := git(dir, "log", "--branches", "-i", "-F", "--grep", id,
+		"--date=short", "--format=%H%x00%ad%x00%an%x00%s%x00%B%x1e")
+	commits := []Commit{}
+	if err != nil {
+		// A repository without commits has nothing to show.
+		return commits
+	}
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x00", 5)
+		if len(fields) < 5 {
+			continue
+		}
+		refs := commitref.Parse(fields[4], prefix)
+		if !slices.ContainsFunc(refs, func(r commitref.Ref) bool { return r.ID == id }) {
+			continue
+		}
+		commits = append(commits, Commit{SHA: fields[0], Date: fields[1], Author: fields[2], Subject: fields[3]})
+		if len(commits) == limit {
+			break
+		}
+	}
+	return commits
+}
+
+// MainBranch guesses the repository's main branch: the remote's default
+// branch if origin has one, else main or master, else "".
+func MainBranch(dir string) string {
+	if ref, err := git(dir, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		if name := strings.TrimPrefix(ref, "origin/"); BranchExists(dir, name) {
+			return name
+		}
+	}
+	for _, name := range []string{"main", "master"} {
+		if BranchExists(dir, name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// BranchExists reports whether the local branch name exists.
+func BranchExists(dir, name string) bool {
+	_, err := git(dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+// CommitDate returns the YYYY-MM-DD committer date of ref, which can name
+// a tag, branch or commit.
+func CommitDate(dir, ref string) (string, error) {
+	date, err := git(dir, "log", "-1", "--format=%cs", ref, "--")
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %q: %w", ref, err)
+	}
+	return date, nil
+}
+
+// MovedTo returns the IDs of the task files under tasksDir, a path
+// relative to dir, that a commit in from..to changed to status. An empty
+// from covers all of to's history. The diffs are read for lines setting
+// the frontmatter status, so a file counts whether it was edited into
+// status or added with it.
+func MovedTo(dir, tasksDir, status, from, to string) (map[string]bool, error) {
+	revs := to
+	if from != "" {
+		revs = from + ".." + to
+	}
+	out, err := git(dir, "log", "-p", "--unified=0", "--no-color", "--no-ext-diff", "--no-renames", "--format=", revs, "--", tasksDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading task history in %s: %w", revs, err)
+	}
+	moved := make(map[string]bool)
+	file := ""
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(line, "+++ b/")
+		case strings.HasPrefix(line, "+status:"):
+			value := strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "+status:")), `"'`)
+			if value == status && strings.HasSuffix(file, ".md") {
+				moved[strings.TrimSuffix(path.Base(file), ".md")] = true
+			}
+		}
+	}
+	return moved, nil
+}
+
+// Checkout switches to branch, creating it from the current commit first
+// if it doesn't exist. Without switchTo, it only creates the branch.
+func Checkout(dir, branch string, switchTo bool) error {
+	var args []string
+	switch exists := BranchExists(dir, branch); {
+	case exists && switchTo:
+		args = []string{"switch", branch}
+	case exists:
+		return nil
+	case switchTo:
+		args = []string{"switch", "-c", branch}
+	default:
+		args = []string{"branch", branch}
+	}
+	_, err := git(dir, args...)
+	return err
+}
+
+// git runs git in dir and returns its trimmed output, with stderr in the
+// error.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,150 @@
+package gitinfo
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+func TestBranchName(t *testing.T) {
+	tk := &task.Task{ID: "US-012", Title: "Add the login form (OAuth + SSO)!", Assignee: "Ana"}
+	tests := []struct{ pattern, want string }{
+		{"", "US-012-add-the-login-form-oauth-sso"},
+		{"{{lower assignee}}/{{lower id}}", "ana/us-012"},
+		{"feature/{{id}}", "feature/US-012"},
+	}
+	for _, tt := range tests {
+		got, err := BranchName(tt.pattern, tk)
+		if err != nil || got != tt.want {
+			t.Errorf("BranchName(%q) = %q, %v, want %q", tt.pattern, got, err, tt.want)
+		}
+	}
+	if _, err := BranchName("{{nope}}", tk); err == nil {
+		t.Error("expected an error for an unknown function")
+	}
+}
+
+func TestSlugCutsAtWordBoundary(t *testing.T) {
+	got := Slug("Make the settings page remember the last tab you had open across restarts")
+	if got != "make-the-settings-page-remember-the-last" {
+		t.Errorf("Slug = %q", got)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "Ana")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ana@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Ana")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ana@example.com")
+	run := func(args ...string) {
+		t.Helper()
+		c := exec.Command("git", args...)
+		c.Dir = dir
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	run("commit", "-q", "--allow-empty", "-m", "Initial commit")
+
+	tk := &task.Task{ID: "US-001", Branch: "US-001-login"}
+	if err := Checkout(dir, tk.Branch, true); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	run("commit", "-q", "--allow-empty", "-m", "Start the login form (US-001)")
+	run("commit", "-q", "--allow-empty", "-m", "Unrelated work on US-0010")
+	run("commit", "-q", "--allow-empty", "-m", "Fixes US-001")
+	run("switch", "-q", "main")
+	run("commit", "-q", "--allow-empty", "-m", "Meanwhile on main")
+
+	st, err := Inspect(dir, tk, "US", "", 5)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if !st.Exists || st.Main != "main" || st.Ahead != 3 || st.Behind != 1 || st.Merged {
+		t.Errorf("status = %+v, want 3 ahead, 1 behind main, unmerged", st)
+	}
+	if len(st.Commits) != 2 || st.Commits[0].Subject != "Fixes US-001" || st.Commits[0].Author != "Ana" {
+		t.Errorf("commits = %+v, want the two that mention US-001, newest first", st.Commits)
+	}
+
+	run("merge", "-q", "--no-edit", tk.Branch)
+	if st, _ := Inspect(dir, tk, "US", "", 5); !st.Merged || st.Ahead != 0 {
+		t.Errorf("after merging, status = %+v, want merged", st)
+	}
+
+	// A fresh branch isn't merged, but fast-forwarding main onto it is.
+	fresh := &task.Task{ID: "US-002", Branch: "US-002-logout"}
+	Checkout(dir, fresh.Branch, true)
+	if st, _ := Inspect(dir, fresh, "US", "", 5); st.Merged {
+		t.Errorf("fresh branch status = %+v, want unmerged", st)
+	}
+	run("commit", "-q", "--allow-empty", "-m", "Log out (US-002)")
+	run("switch", "-q", "main")
+	run("merge", "-q", "--ff-only", fresh.Branch)
+	if st, _ := Inspect(dir, fresh, "US", "", 5); !st.Merged {
+		t.Errorf("after a fast-forward, status = %+v, want merged", st)
+	}
+
+	// Main moving on doesn't merge a branch nobody has worked on.
+	untouched := &task.Task{ID: "US-003", Branch: "US-003-profile"}
+	Checkout(dir, untouched.Branch, true)
+	run("switch", "-q", "main")
+	run("commit", "-q", "--allow-empty", "-m", "More on main")
+	if st, _ := Inspect(dir, untouched, "US", "", 5); st.Merged || st.Behind != 1 {
+		t.Errorf("untouched branch behind main, status = %+v, want unmerged", st)
+	}
+
+	if _, err := Inspect(t.TempDir(), tk, "US", "", 5); err != ErrNotRepository {
+		t.Errorf("Inspect outside a repository = %v, want ErrNotRepository", err)
+	}
+}
+
+func TestMovedTo(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "Ana")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ana@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Ana")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ana@example.com")
+	run := func(args ...string) {
+		t.Helper()
+		c := exec.Command("git", args...)
+		c.Dir = dir
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(id, status string) {
+		t.Helper()
+		os.MkdirAll(filepath.Join(dir, ".skeeter", "tasks"), 0755)
+		content := "---\nid: " + id + "\ntitle: Task\nstatus: " + status + "\n---\n"
+		if err := os.WriteFile(filepath.Join(dir, ".skeeter", "tasks", id+".md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	write("US-001", "done")
+	write("US-002", "backlog")
+	run("add", "-A")
+	run("commit", "-q", "-m", "Add tasks")
+	run("tag", "v1")
+	write("US-002", `"done"`)
+	write("US-003", "in-progress")
+	run("add", "-A")
+	run("commit", "-q", "-m", "Finish US-002")
+
+	moved, err := MovedTo(dir, ".skeeter/tasks", "done", "v1", "HEAD")
+	if err != nil {
+		t.Fatalf("MovedTo: %v", err)
+	}
+	if len(moved) != 1 || !moved["US-002"] {
+		t.Errorf("MovedTo(v1..HEAD) = %v, want US-002", moved)
+	}
+	if moved, _ := MovedTo(dir, ".skeeter/tasks", "done", "", "v1"); len(moved) != 1 || !moved["US-001"] {
+		t.Errorf("MovedTo(v1) = %v, want US-001", moved)
+	}
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"slices"
+	"strings"
+)
+
+// Components returns the strongly connected components that contain a
+// cycle: groups of two or more tasks that all reach each other through
+// depends_on, and tasks that depend on themselves. IDs within a component
+// are sorted, and components are sorted by their first ID. It runs in time
+// linear in the size of the graph (Tarjan's algorithm).
+func (g *Graph) Components() [][]string {
+	index := make(map[string]int, len(g.Nodes))
+	low := make(map[string]int, len(g.Nodes))
+	onStack := make(map[string]bool)
+	var stack []string
+	var comps [][]string
+
+	var connect func(id string)
+	connect = func(id string) {
+		index[id] = len(index)
+		low[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, dep := range g.deps[id] {
+			if _, seen := index[dep]; !seen {
+				connect(dep)
+				low[id] = min(low[id], low[dep])
+			} else if onStack[dep] {
+				low[id] = min(low[id], index[dep])
+			}
+		}
+
+		if low[id] != index[id] {
+			return
+		}
+		var comp []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			comp = append(comp, top)
+			if top == id {
+				break
+			}
+		}
+		if len(comp) > 1 || slices.Contains(g.deps[id], id) {
+			slices.Sort(comp)
+			comps = append(comps, comp)
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if _, seen := index[n.ID]; !seen {
+			connect(n.ID)
+		}
+	}
+	slices.SortFunc(comps, func(a, b []string) int {
+		return strings.Compare(a[0], b[0])
+	})
+	return comps
+}
+
+// Cycles returns one cycle per component from Components, starting at the
+// component's smallest ID. Each task in a cycle depends on the next, and
+// the last depends on the first.
+func (g *Graph) Cycles() [][]string {
+	var cycles [][]string
+	for _, comp := range g.Components() {
+		cycles = append(cycles, g.CycleThrough(comp[0]))
+	}
+	return cycles
+}
+
+// CycleThrough returns the shortest cycle that passes through id, in the
+// form Cycles uses, or nil if id is not on a cycle.
+func (g *Graph) CycleThrough(id string) []string {
+	prev := map[string]string{}
+	queue := []string{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, dep := range g.deps[cur] {
+			if dep == id {
+				var path []string
+				for n := cur; n != id; n = prev[n] {
+					path = append(path, n)
+				}
+				path = append(path, id)
+				slices.Reverse(path)
+				return path
+			}
+			if _, seen := prev[dep]; !seen {
+				prev[dep] = cur
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return nil
+}
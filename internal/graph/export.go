@@ -0,0 +1,144 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formats are the export formats, by name.
+var Formats = []string{"dot", "mermaid", "json"}
+
+// Write exports g in format. Nodes in highlight, such as a critical path,
+// are drawn emphasized.
+func Write(w io.Writer, g *Graph, format string, highlight map[string]bool) error {
+	switch format {
+	case "dot":
+		return WriteDOT(w, g, highlight)
+	case "mermaid":
+		return WriteMermaid(w, g, highlight)
+	case "json":
+		return WriteJSON(w, g)
+	}
+	return fmt.Errorf("unknown graph format %q (valid: %s)", format, strings.Join(Formats, ", "))
+}
+
+// WriteDOT writes g as a Graphviz digraph.
+func WriteDOT(w io.Writer, g *Graph, highlight map[string]bool) error {
+	var b strings.Builder
+	b.WriteString("digraph skeeter {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+	for _, n := range g.Nodes {
+		escaped := Node{ID: dotEscape(n.ID), Title: dotEscape(n.Title), Missing: n.Missing}
+		attrs := []string{`label="` + label(escaped, `\n`) + `"`}
+		switch {
+		case n.Missing:
+			attrs = append(attrs, `style="rounded,dashed"`, `color=gray`)
+		case n.Done:
+			attrs = append(attrs, `style="rounded,filled"`, `fillcolor="#d1fae5"`)
+		}
+		if highlight[n.ID] {
+			attrs = append(attrs, `color="#dc2626"`, `penwidth=2`)
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		attrs := ""
+		if highlight[e.From] && highlight[e.To] {
+			attrs = ` [color="#dc2626", penwidth=2]`
+		}
+		fmt.Fprintf(&b, "  %s -> %s%s;\n", dotQuote(e.From), dotQuote(e.To), attrs)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes g as a Mermaid flowchart.
+func WriteMermaid(w io.Writer, g *Graph, highlight map[string]bool) error {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	var done, missing, critical []string
+	for _, n := range g.Nodes {
+		id := mermaidID(n.ID)
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", id, mermaidEscape(label(n, ": ")))
+		switch {
+		case n.Missing:
+			missing = append(missing, id)
+		case n.Done:
+			done = append(done, id)
+		}
+		if highlight[n.ID] {
+			critical = append(critical, id)
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", mermaidID(e.From), mermaidID(e.To))
+	}
+	classes := []struct {
+		name, style string
+		ids         []string
+	}{
+		{"done", "fill:#d1fae5", done},
+		{"missing", "stroke-dasharray:4", missing},
+		{"critical", "stroke:#dc2626,stroke-width:2px", critical},
+	}
+	for _, c := range classes {
+		if len(c.ids) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "  classDef %s %s\n", c.name, c.style)
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(c.ids, ","), c.name)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes g's nodes and edges as JSON.
+func WriteJSON(w io.Writer, g *Graph) error {
+	out := Graph{Nodes: g.Nodes, Edges: g.Edges}
+	if out.Nodes == nil {
+		out.Nodes = []Node{}
+	}
+	if out.Edges == nil {
+		out.Edges = []Edge{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func label(n Node, sep string) string {
+	if n.Missing {
+		return n.ID + sep + "(missing)"
+	}
+	if n.Title == "" {
+		return n.ID
+	}
+	return n.ID + sep + n.Title
+}
+
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// mermaidID turns a task ID into a Mermaid node ID, which may not contain
+// hyphens or other punctuation.
+func mermaidID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
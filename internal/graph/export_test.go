@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+func exportGraph() *Graph {
+	return Build([]task.Task{
+		{ID: "US-001", Title: `Say "hi"`, Status: "done"},
+		{ID: "US-002", Title: "Wire up", Status: "backlog", DependsOn: task.FlowSlice{"US-001", "US-009"}},
+	}, "done")
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, exportGraph(), "dot", map[string]bool{"US-002": true}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"digraph skeeter {",
+		`"US-001" [label="US-001\nSay \"hi\"", style="rounded,filled"`,
+		`"US-009" [label="US-009\n(missing)", style="rounded,dashed"`,
+		`"US-001" -> "US-002";`,
+		`"US-002" [label="US-002\nWire up", color="#dc2626", penwidth=2];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, exportGraph(), "mermaid", nil); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"graph LR",
+		`US_001["US-001: Say #quot;hi#quot;"]`,
+		"US_001 --> US_002",
+		"class US_001 done",
+		"class US_009 missing",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, exportGraph(), "json", nil); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Nodes []Node `json:"nodes"`
+		Edges []Edge `json:"edges"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Nodes) != 3 || len(got.Edges) != 2 {
+		t.Errorf("got %d nodes and %d edges, want 3 and 2", len(got.Nodes), len(got.Edges))
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, exportGraph(), "svg", nil); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
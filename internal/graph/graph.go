@@ -0,0 +1,227 @@
+// Package graph builds the dependency graph of a set of tasks from their
+// depends_on lists, for export and execution planning.
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// Node is one task in the graph. Missing nodes stand for dependencies that
+// name a task that doesn't exist.
+type Node struct {
+	ID       string `json:"id"`
+	Title    string `json:"title,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Priority string `json:"priority,omitempty"`
+	Done     bool   `json:"done"`
+	Missing  bool   `json:"missing,omitempty"`
+}
+
+// Edge points from a dependency to the task that depends on it, the order
+// the work has to happen in.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Graph is a dependency graph. Nodes keep the order of the tasks it was
+// built from, which breaks ties in Order.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+
+	index map[string]int
+	deps  map[string][]string // task → what it depends on
+	users map[string][]string // task → what depends on it
+}
+
+// Build returns the graph of tasks. Tasks in doneStatus are marked done.
+func Build(tasks []task.Task, doneStatus string) *Graph {
+	g := &Graph{
+		index: make(map[string]int),
+		deps:  make(map[string][]string),
+		users: make(map[string][]string),
+	}
+	for _, t := range tasks {
+		g.add(Node{ID: t.ID, Title: t.Title, Status: t.Status, Priority: t.Priority, Done: t.Status == doneStatus})
+	}
+	for _, t := range tasks {
+		for _, dep := range t.DependsOn {
+			if _, ok := g.index[dep]; !ok {
+				g.add(Node{ID: dep, Missing: true})
+			}
+			if slices.Contains(g.deps[t.ID], dep) {
+				continue
+			}
+			g.Edges = append(g.Edges, Edge{From: dep, To: t.ID})
+			g.deps[t.ID] = append(g.deps[t.ID], dep)
+			g.users[dep] = append(g.users[dep], t.ID)
+		}
+	}
+	return g
+}
+
+func (g *Graph) add(n Node) {
+	g.index[n.ID] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, n)
+}
+
+// Node returns the node with id, or nil if it isn't in the graph.
+func (g *Graph) Node(id string) *Node {
+	i, ok := g.index[id]
+	if !ok {
+		return nil
+	}
+	return &g.Nodes[i]
+}
+
+// DependsOn returns the direct dependencies of id.
+func (g *Graph) DependsOn(id string) []string {
+	return g.deps[id]
+}
+
+// Dependents returns the tasks that depend directly on id.
+func (g *Graph) Dependents(id string) []string {
+	return g.users[id]
+}
+
+// Upstream returns id and everything it transitively depends on.
+func (g *Graph) Upstream(id string) map[string]bool {
+	return g.reach(id, g.deps)
+}
+
+// Downstream returns id and everything that transitively depends on it.
+func (g *Graph) Downstream(id string) map[string]bool {
+	return g.reach(id, g.users)
+}
+
+func (g *Graph) reach(id string, next map[string][]string) map[string]bool {
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, n := range next[cur] {
+			if !seen[n] {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return seen
+}
+
+// Subgraph returns the graph restricted to the nodes in keep.
+func (g *Graph) Subgraph(keep map[string]bool) *Graph {
+	sub := &Graph{
+		index: make(map[string]int),
+		deps:  make(map[string][]string),
+		users: make(map[string][]string),
+	}
+	for _, n := range g.Nodes {
+		if keep[n.ID] {
+			sub.add(n)
+		}
+	}
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			sub.Edges = append(sub.Edges, e)
+			sub.deps[e.To] = append(sub.deps[e.To], e.From)
+			sub.users[e.From] = append(sub.users[e.From], e.To)
+		}
+	}
+	return sub
+}
+
+// CycleError reports that tasks depend on each other in a loop, so they
+// have no execution order.
+type CycleError struct {
+	// IDs are the tasks that could not be ordered: those on a cycle and
+	// those waiting on one.
+	IDs []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("circular dependencies among %s", strings.Join(e.IDs, ", "))
+}
+
+// Order returns the nodes in an order where every task comes after what it
+// depends on. Among tasks that are free to go next, the one built first
+// comes first, so building from prioritized tasks gives a prioritized plan.
+func (g *Graph) Order() ([]string, error) {
+	waiting := make(map[string]int, len(g.Nodes))
+	for _, n := range g.Nodes {
+		waiting[n.ID] = len(g.deps[n.ID])
+	}
+
+	order := make([]string, 0, len(g.Nodes))
+	placed := make(map[string]bool, len(g.Nodes))
+	for len(order) < len(g.Nodes) {
+		next := ""
+		for _, n := range g.Nodes {
+			if !placed[n.ID] && waiting[n.ID] == 0 {
+				next = n.ID
+				break
+			}
+		}
+		if next == "" {
+			var stuck []string
+			for _, n := range g.Nodes {
+				if !placed[n.ID] {
+					stuck = append(stuck, n.ID)
+				}
+			}
+			return order, &CycleError{IDs: stuck}
+		}
+		placed[next] = true
+		order = append(order, next)
+		for _, u := range g.users[next] {
+			waiting[u]--
+		}
+	}
+	return order, nil
+}
+
+// CriticalPath returns the longest chain of unfinished tasks, each depending
+// on the one before it: the work that gates the last task in the chain.
+// Missing dependencies count as unfinished. It fails like Order when the
+// graph has a cycle.
+func (g *Graph) CriticalPath() ([]string, error) {
+	order, err := g.Order()
+	if err != nil {
+		return nil, err
+	}
+
+	length := make(map[string]int, len(order))
+	prev := make(map[string]string, len(order))
+	end := ""
+	for _, id := range order {
+		if g.Node(id).Done {
+			continue
+		}
+		length[id] = 1
+		for _, dep := range g.deps[id] {
+			if l, ok := length[dep]; ok && l+1 > length[id] {
+				length[id] = l + 1
+				prev[id] = dep
+			}
+		}
+		if end == "" || length[id] > length[end] {
+			end = id
+		}
+	}
+	if end == "" {
+		return nil, nil
+	}
+
+	var path []string
+	for id := end; id != ""; id = prev[id] {
+		path = append(path, id)
+	}
+	slices.Reverse(path)
+	return path, nil
+}
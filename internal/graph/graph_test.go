@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// testTasks is a small plan:
+//
+//	US-001 (done) ─▶ US-002 ─▶ US-004 ─▶ US-005
+//	                 US-003 ─────────────▶ US-005
+func testTasks() []task.Task {
+	return []task.Task{
+		{ID: "US-001", Status: "done"},
+		{ID: "US-002", Status: "backlog", DependsOn: task.FlowSlice{"US-001"}},
+		{ID: "US-003", Status: "backlog"},
+		{ID: "US-004", Status: "backlog", DependsOn: task.FlowSlice{"US-002"}},
+		{ID: "US-005", Status: "backlog", DependsOn: task.FlowSlice{"US-004", "US-003"}},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	g := Build(testTasks(), "done")
+
+	if len(g.Nodes) != 5 || len(g.Edges) != 4 {
+		t.Fatalf("got %d nodes and %d edges, want 5 and 4", len(g.Nodes), len(g.Edges))
+	}
+	if !g.Node("US-001").Done || g.Node("US-002").Done {
+		t.Error("done flags are wrong")
+	}
+	if got := g.Dependents("US-002"); !slices.Equal(got, []string{"US-004"}) {
+		t.Errorf("Dependents(US-002) = %v", got)
+	}
+	if got := g.DependsOn("US-005"); !slices.Equal(got, []string{"US-004", "US-003"}) {
+		t.Errorf("DependsOn(US-005) = %v", got)
+	}
+}
+
+func TestBuildMissingDependency(t *testing.T) {
+	g := Build([]task.Task{{ID: "US-001", DependsOn: task.FlowSlice{"US-009"}}}, "done")
+	n := g.Node("US-009")
+	if n == nil || !n.Missing {
+		t.Fatalf("missing dependency node = %+v", n)
+	}
+}
+
+func TestUpstreamDownstream(t *testing.T) {
+	g := Build(testTasks(), "done")
+
+	up := g.Upstream("US-004")
+	for _, id := range []string{"US-004", "US-002", "US-001"} {
+		if !up[id] {
+			t.Errorf("Upstream(US-004) is missing %s", id)
+		}
+	}
+	if len(up) != 3 {
+		t.Errorf("Upstream(US-004) = %v, want 3 tasks", up)
+	}
+
+	down := g.Downstream("US-002")
+	if len(down) != 3 || !down["US-005"] {
+		t.Errorf("Downstream(US-002) = %v", down)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	g := Build(testTasks(), "done")
+	order, err := g.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"US-001", "US-002", "US-003", "US-004", "US-005"}
+	if !slices.Equal(order, want) {
+		t.Errorf("Order = %v, want %v", order, want)
+	}
+}
+
+func TestOrderCycle(t *testing.T) {
+	g := Build([]task.Task{
+		{ID: "US-001", DependsOn: task.FlowSlice{"US-002"}},
+		{ID: "US-002", DependsOn: task.FlowSlice{"US-001"}},
+		{ID: "US-003"},
+	}, "done")
+	_, err := g.Order()
+	var cycle *CycleError
+	if !errors.As(err, &cycle) {
+		t.Fatalf("err = %v, want a CycleError", err)
+	}
+	if !slices.Equal(cycle.IDs, []string{"US-001", "US-002"}) {
+		t.Errorf("cycle = %v", cycle.IDs)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	g := Build(testTasks(), "done")
+	path, err := g.CriticalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"US-002", "US-004", "US-005"}
+	if !slices.Equal(path, want) {
+		t.Errorf("CriticalPath = %v, want %v", path, want)
+	}
+}
+
+func TestCriticalPathAllDone(t *testing.T) {
+	g := Build([]task.Task{{ID: "US-001", Status: "done"}}, "done")
+	path, err := g.CriticalPath()
+	if err != nil || path != nil {
+		t.Errorf("CriticalPath = %v, %v; want nothing", path, err)
+	}
+}
+
+func TestSubgraph(t *testing.T) {
+	g := Build(testTasks(), "done").Subgraph(map[string]bool{"US-002": true, "US-004": true})
+	if len(g.Nodes) != 2 || len(g.Edges) != 1 {
+		t.Fatalf("got %d nodes and %d edges, want 2 and 1", len(g.Nodes), len(g.Edges))
+	}
+	if got := g.DependsOn("US-004"); !slices.Equal(got, []string{"US-002"}) {
+		t.Errorf("DependsOn(US-004) = %v", got)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	tasks := []task.Task{
+		{ID: "US-001", DependsOn: task.FlowSlice{"US-002"}},
+		{ID: "US-002", DependsOn: task.FlowSlice{"US-003"}},
+		{ID: "US-003", DependsOn: task.FlowSlice{"US-001", "US-004"}},
+		{ID: "US-004"},
+		{ID: "US-005", DependsOn: task.FlowSlice{"US-005"}},
+		{ID: "US-006", DependsOn: task.FlowSlice{"US-002"}},
+	}
+	g := Build(tasks, "done")
+
+	comps := g.Components()
+	want := [][]string{{"US-001", "US-002", "US-003"}, {"US-005"}}
+	if !slices.EqualFunc(comps, want, slices.Equal) {
+		t.Errorf("Components = %v, want %v", comps, want)
+	}
+	cycles := g.Cycles()
+	if !slices.EqualFunc(cycles, want, slices.Equal) {
+		t.Errorf("Cycles = %v, want %v", cycles, want)
+	}
+	if got := g.CycleThrough("US-003"); !slices.Equal(got, []string{"US-003", "US-001", "US-002"}) {
+		t.Errorf("CycleThrough(US-003) = %v", got)
+	}
+	if got := g.CycleThrough("US-006"); got != nil {
+		t.Errorf("CycleThrough(US-006) = %v, want nil", got)
+	}
+}
+
+func TestCyclesNone(t *testing.T) {
+	if got := Build(testTasks(), "done").Cycles(); len(got) != 0 {
+		t.Errorf("Cycles = %v, want none", got)
+	}
+}
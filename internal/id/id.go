@@ -9,12 +9,21 @@ import (
 )
 
 func Next(tasksDir, prefix string) (string, error) {
+	names, err := Names(tasksDir)
+	if err != nil {
+		return "", err
+	}
+	return NextFromNames(names, prefix)
+}
+
+// Names returns the file names in tasksDir. A missing directory has none.
+func Names(tasksDir string) ([]string, error) {
 	entries, err := os.ReadDir(tasksDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Sprintf("%s-%03d", prefix, 1), nil
+			return nil, nil
 		}
-		return "", err
+		return nil, err
 	}
 
 	var names []string
@@ -23,12 +32,20 @@ func Next(tasksDir, prefix string) (string, error) {
 			names = append(names, e.Name())
 		}
 	}
-
-	return NextFromNames(names, prefix)
+	return names, nil
 }
 
 func NextFromNames(names []string, prefix string) (string, error) {
-	maxNum := 0
+	return format(prefix, maxNumber(numbers(names, prefix))+1), nil
+}
+
+func format(prefix string, num int) string {
+	return fmt.Sprintf("%s-%03d", prefix, num)
+}
+
+// numbers returns the numeric suffixes of names of the form <prefix>-<n>.
+func numbers(names []string, prefix string) []int {
+	var nums []int
 	dashPrefix := prefix + "-"
 
 	for _, name := range names {
@@ -41,10 +58,17 @@ func NextFromNames(names []string, prefix string) (string, error) {
 		if err != nil {
 			continue
 		}
+		nums = append(nums, num)
+	}
+	return nums
+}
+
+func maxNumber(nums []int) int {
+	maxNum := 0
+	for _, num := range nums {
 		if num > maxNum {
 			maxNum = num
 		}
 	}
-
-	return fmt.Sprintf("%s-%03d", prefix, maxNum+1), nil
+	return maxNum
 }
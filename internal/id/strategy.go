@@ -0,0 +1,162 @@
+package id
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Strategies for allocating new task IDs. Sequential IDs are the easiest to
+// read but collide when tasks are created on parallel branches; the other
+// strategies trade some readability for merges that don't clash.
+const (
+	// Sequential takes the highest existing number plus one (US-028).
+	Sequential = "sequential"
+	// Hash appends a short random suffix (US-7K3M9Q).
+	Hash = "hash"
+	// Block hands each author their own reserved range of numbers.
+	Block = "block"
+)
+
+// Strategies lists the valid strategy names.
+var Strategies = []string{Sequential, Hash, Block}
+
+// DefaultBlockSize is the number of IDs reserved per block.
+const DefaultBlockSize = 100
+
+// hashLength is the number of Crockford base32 characters in a hash suffix,
+// giving about a billion possible IDs per prefix.
+const hashLength = 6
+
+// crockford is the Crockford base32 alphabet, which leaves out I, L, O and U
+// so suffixes can't be misread or spell words.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ValidStrategy reports whether strategy is a known strategy name. The empty
+// string means Sequential.
+func ValidStrategy(strategy string) bool {
+	return strategy == "" || slices.Contains(Strategies, strategy)
+}
+
+// HashFromNames returns an ID with a random suffix that no name uses yet.
+func HashFromNames(names []string, prefix string) (string, error) {
+	taken := make(map[string]bool, len(names))
+	for _, name := range names {
+		taken[strings.TrimSuffix(name, filepath.Ext(name))] = true
+	}
+
+	for range 100 {
+		suffix, err := randomSuffix()
+		if err != nil {
+			return "", err
+		}
+		// An all-digit suffix would be mistaken for a sequential number.
+		if _, err := strconv.Atoi(suffix); err == nil {
+			continue
+		}
+		id := prefix + "-" + suffix
+		if !taken[id] {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("could not find an unused %s ID", prefix)
+}
+
+func randomSuffix() (string, error) {
+	max := big.NewInt(int64(len(crockford)))
+	var b strings.Builder
+	for range hashLength {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(crockford[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// Reservation is a block of sequential numbers claimed by one author.
+// Reservations are stored one per line so that concurrent reservations on
+// different branches merge cleanly with git's union merge.
+type Reservation struct {
+	Prefix string
+	Start  int
+	End    int
+	Author string
+}
+
+func (r Reservation) String() string {
+	return fmt.Sprintf("%s %d-%d %s", r.Prefix, r.Start, r.End, r.Author)
+}
+
+// ParseReservations parses reservation lines, ignoring blank lines, comments
+// and lines it can't make sense of.
+func ParseReservations(data string) []Reservation {
+	var reservations []Reservation
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		from, to, ok := strings.Cut(fields[1], "-")
+		if !ok {
+			continue
+		}
+		start, err1 := strconv.Atoi(from)
+		end, err2 := strconv.Atoi(to)
+		if err1 != nil || err2 != nil || start > end {
+			continue
+		}
+		reservations = append(reservations, Reservation{
+			Prefix: fields[0],
+			Start:  start,
+			End:    end,
+			Author: fields[2],
+		})
+	}
+	return reservations
+}
+
+// BlockFromNames returns the next free ID in one of author's reserved blocks.
+// When author has no block with room left, it also returns a new reservation
+// past every existing block and ID, which the caller must record.
+func BlockFromNames(names []string, prefix, author string, size int, reserved []Reservation) (string, *Reservation, error) {
+	if author == "" {
+		return "", nil, fmt.Errorf("block IDs need an author")
+	}
+	if size <= 0 {
+		size = DefaultBlockSize
+	}
+
+	nums := numbers(names, prefix)
+	highest := maxNumber(nums)
+
+	for _, r := range reserved {
+		if r.Prefix != prefix {
+			continue
+		}
+		if r.End > highest {
+			highest = r.End
+		}
+		if r.Author != author {
+			continue
+		}
+		next := r.Start
+		for _, num := range nums {
+			if num >= r.Start && num <= r.End && num >= next {
+				next = num + 1
+			}
+		}
+		if next <= r.End {
+			return format(prefix, next), nil, nil
+		}
+	}
+
+	start := (highest+size-1)/size*size + 1
+	r := &Reservation{Prefix: prefix, Start: start, End: start + size - 1, Author: author}
+	return format(prefix, start), r, nil
+}
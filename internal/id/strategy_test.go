@@ -0,0 +1,111 @@
+package id
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidStrategy(t *testing.T) {
+	for _, s := range []string{"", Sequential, Hash, Block} {
+		if !ValidStrategy(s) {
+			t.Errorf("ValidStrategy(%q) = false, want true", s)
+		}
+	}
+	if ValidStrategy("random") {
+		t.Error("ValidStrategy(random) = true, want false")
+	}
+}
+
+func TestHashFromNames(t *testing.T) {
+	got, err := HashFromNames([]string{"US-001.md"}, "US")
+	if err != nil {
+		t.Fatalf("HashFromNames: %v", err)
+	}
+	suffix, ok := strings.CutPrefix(got, "US-")
+	if !ok || len(suffix) != hashLength {
+		t.Fatalf("got %q, want US- followed by %d characters", got, hashLength)
+	}
+	for _, c := range suffix {
+		if !strings.ContainsRune(crockford, c) {
+			t.Errorf("suffix %q contains %q, not in the Crockford alphabet", suffix, c)
+		}
+	}
+
+	// Hash IDs must not disturb sequential numbering.
+	next, _ := NextFromNames([]string{"US-001.md", got + ".md"}, "US")
+	if next != "US-002" {
+		t.Errorf("NextFromNames with a hash ID = %q, want US-002", next)
+	}
+}
+
+func TestParseReservations(t *testing.T) {
+	data := "# reserved ID blocks\nUS 1-100 alice@example.com\n\nUS 101-200 bob@example.com\ngarbage\nUS 300-250 backwards\n"
+	got := ParseReservations(data)
+	if len(got) != 2 {
+		t.Fatalf("got %d reservations, want 2: %v", len(got), got)
+	}
+	want := Reservation{Prefix: "US", Start: 101, End: 200, Author: "bob@example.com"}
+	if got[1] != want {
+		t.Errorf("got %+v, want %+v", got[1], want)
+	}
+	if got[1].String() != "US 101-200 bob@example.com" {
+		t.Errorf("String() = %q", got[1].String())
+	}
+}
+
+func TestBlockFromNames(t *testing.T) {
+	reserved := []Reservation{
+		{Prefix: "US", Start: 1, End: 10, Author: "alice"},
+		{Prefix: "US", Start: 11, End: 20, Author: "bob"},
+	}
+
+	t.Run("next in own block", func(t *testing.T) {
+		got, r, err := BlockFromNames([]string{"US-001.md", "US-002.md", "US-011.md"}, "US", "alice", 10, reserved)
+		if err != nil {
+			t.Fatalf("BlockFromNames: %v", err)
+		}
+		if got != "US-003" || r != nil {
+			t.Errorf("got %q, %v; want US-003 with no new reservation", got, r)
+		}
+	})
+
+	t.Run("new author reserves past everything", func(t *testing.T) {
+		got, r, err := BlockFromNames([]string{"US-001.md"}, "US", "carol", 10, reserved)
+		if err != nil {
+			t.Fatalf("BlockFromNames: %v", err)
+		}
+		if got != "US-021" {
+			t.Errorf("got %q, want US-021", got)
+		}
+		if r == nil || r.Start != 21 || r.End != 30 || r.Author != "carol" {
+			t.Errorf("reservation = %+v, want US 21-30 carol", r)
+		}
+	})
+
+	t.Run("full block reserves another", func(t *testing.T) {
+		var names []string
+		for i := 11; i <= 20; i++ {
+			names = append(names, format("US", i)+".md")
+		}
+		got, r, err := BlockFromNames(names, "US", "bob", 10, reserved)
+		if err != nil {
+			t.Fatalf("BlockFromNames: %v", err)
+		}
+		if got != "US-021" || r == nil || r.Start != 21 {
+			t.Errorf("got %q, %+v; want US-021 in a new block", got, r)
+		}
+	})
+
+	t.Run("first block starts at one", func(t *testing.T) {
+		got, r, _ := BlockFromNames(nil, "US", "alice", 0, nil)
+		if got != "US-001" || r == nil || r.End != DefaultBlockSize {
+			t.Errorf("got %q, %+v; want US-001 in a block of %d", got, r, DefaultBlockSize)
+		}
+	})
+
+	t.Run("requires author", func(t *testing.T) {
+		if _, _, err := BlockFromNames(nil, "US", "", 10, nil); err == nil {
+			t.Error("expected error without an author")
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andybarilla/skeeter/internal/changelog"
+	"github.com/andybarilla/skeeter/internal/config"
+)
+
+// maxSummaryBody caps how much of each task's body goes into a summary
+// prompt, so a long release doesn't overflow the tool's context.
+const maxSummaryBody = 1500
+
+// SummarizeGroup asks the configured tool for a short paragraph of release
+// notes describing the tasks in g.
+func SummarizeGroup(ctx context.Context, cfg *config.Config, g changelog.Group) (string, error) {
+	tool, err := cfg.ResolveTool()
+	if err != nil {
+		return "", err
+	}
+	return RunCLI(ctx, tool, summarySystemPrompt(cfg), summaryUserContent(g))
+}
+
+func summarySystemPrompt(cfg *config.Config) string {
+	var b strings.Builder
+
+	b.WriteString("You are writing release notes for a software project.\n\n")
+
+	if cfg.Project.Name != "" {
+		fmt.Fprintf(&b, "Project: %s\n\n", cfg.Project.Name)
+	}
+
+	b.WriteString("Guidelines:\n")
+	b.WriteString("- Summarize the completed tasks as one short paragraph of prose for the project's users\n")
+	b.WriteString("- Describe what changed and why it matters, not how it was implemented\n")
+	b.WriteString("- Don't mention task IDs, invent changes, or add headings or lists\n")
+	b.WriteString("- Output ONLY the paragraph, nothing else")
+
+	return b.String()
+}
+
+func summaryUserContent(g changelog.Group) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Section: %s\n\nCompleted tasks:\n", g.Title)
+	for _, e := range g.Entries {
+		fmt.Fprintf(&b, "\n### %s\n", e.Title)
+		body := strings.TrimSpace(e.Body)
+		if len(body) > maxSummaryBody {
+			body = body[:maxSummaryBody] + "..."
+		}
+		if body != "" {
+			b.WriteString(body)
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\nPlease write the release notes paragraph for this section.")
+
+	return b.String()
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/andybarilla/skeeter/internal/changelog"
 	"github.com/andybarilla/skeeter/internal/config"
 	"github.com/andybarilla/skeeter/internal/task"
 )
@@ -214,6 +217,35 @@ func TestRunCLI(t *testing.T) {
 	})
 }
 
+func TestRunCLIStream(t *testing.T) {
+	tool := &config.LLMToolDef{Command: "sh", PrintFlag: "-c"}
+	dir := t.TempDir()
+
+	var mu sync.Mutex
+	lines := make(map[string][]string)
+	onLine := func(stream, line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		lines[stream] = append(lines[stream], line)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// sh -c takes the script from the extra args.
+	if err := RunCLIStream(ctx, tool, dir, "", "from stdin\n", onLine, "cat; pwd; echo oops >&2"); err != nil {
+		t.Fatalf("RunCLIStream: %v", err)
+	}
+
+	want := []string{"from stdin", dir}
+	if !slices.Equal(lines["stdout"], want) {
+		t.Errorf("stdout = %v, want %v", lines["stdout"], want)
+	}
+	if len(lines["stderr"]) != 1 || lines["stderr"][0] != "oops" {
+		t.Errorf("stderr = %v, want [oops]", lines["stderr"])
+	}
+}
+
 func TestBuildArgs(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -431,6 +463,25 @@ func TestEnhanceUserContent(t *testing.T) {
 	})
 }
 
+func TestSummaryUserContent(t *testing.T) {
+	g := changelog.Group{Title: "Fixed", Entries: []changelog.Entry{
+		{ID: "US-002", Title: "Crash on logout", Body: "Logging out twice crashed the app.\n"},
+		{ID: "US-003", Title: "Long one", Body: strings.Repeat("x", maxSummaryBody+100)},
+	}}
+
+	result := summaryUserContent(g)
+
+	if !strings.Contains(result, "Section: Fixed") {
+		t.Error("missing section title")
+	}
+	if !strings.Contains(result, "### Crash on logout\nLogging out twice crashed the app.") {
+		t.Error("missing task title and body")
+	}
+	if strings.Contains(result, strings.Repeat("x", maxSummaryBody+1)) {
+		t.Error("long body was not cut")
+	}
+}
+
 func TestDefaultWorkUserContent(t *testing.T) {
 	cfg := config.Default()
 	tk := &task.Task{
@@ -1,12 +1,15 @@
 package llm
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 
 	"github.com/andybarilla/skeeter/internal/config"
 )
@@ -72,19 +75,48 @@ func RunCLI(ctx context.Context, tool *config.LLMToolDef, systemPrompt, userCont
 	return strings.TrimSpace(stdout.String()), nil
 }
 
-// RunCLIPassthrough invokes the tool with separated system prompt and user content,
-// wiring stdout/stderr to the terminal.
-func RunCLIPassthrough(ctx context.Context, tool *config.LLMToolDef, systemPrompt, userContent string, extraArgs ...string) error {
+// RunCLIStream invokes the tool like RunCLI but delivers output line by
+// line to onLine, tagged "stdout" or "stderr". The tool runs in dir, or the
+// current directory if dir is empty. onLine may be called from two
+// goroutines at once.
+func RunCLIStream(ctx context.Context, tool *config.LLMToolDef, dir, systemPrompt, userContent string, onLine func(stream, line string), extraArgs ...string) error {
 	if tool.Command == "" {
 		return fmt.Errorf("no LLM tool command configured (run: skeeter config set llm.tool claude)")
 	}
 
 	args := buildArgs(tool, systemPrompt, extraArgs)
 	cmd := exec.CommandContext(ctx, tool.Command, args...)
+	cmd.Dir = dir
 	cmd.Stdin = strings.NewReader(buildStdin(tool, systemPrompt, userContent))
 	cmd.Env = cleanLLMEnv()
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
 
-	return cmd.Run()
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for stream, r := range map[string]io.Reader{"stdout": stdout, "stderr": stderr} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scanner := bufio.NewScanner(r)
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				onLine(stream, scanner.Text())
+			}
+			// Keep draining after an oversized line so the tool never blocks.
+			io.Copy(io.Discard, r)
+		}()
+	}
+	// The pipes must be drained before Wait closes them.
+	wg.Wait()
+	return cmd.Wait()
 }
@@ -0,0 +1,217 @@
+// Package merge implements a three-way, field-level merge of task files for
+// use as a git merge driver.
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// Files merges the task files at base, ours and theirs and writes the result
+// to ours, as git expects of a merge driver. It returns a description of each
+// conflict it could not resolve; the merged file is still written, with
+// scalar conflicts settled in favor of ours and body conflicts marked inline.
+func Files(basePath, oursPath, theirsPath string, statuses []string) ([]string, error) {
+	read := func(path string) (string, error) {
+		data, err := os.ReadFile(path)
+		return string(data), err
+	}
+	baseContent, err := read(basePath)
+	if err != nil {
+		return nil, err
+	}
+	oursContent, err := read(oursPath)
+	if err != nil {
+		return nil, err
+	}
+	theirsContent, err := read(theirsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	base, baseErr := task.Parse(baseContent)
+	ours, oursErr := task.Parse(oursContent)
+	theirs, theirsErr := task.Parse(theirsContent)
+	if baseErr != nil || oursErr != nil || theirsErr != nil {
+		// Either both sides added a task under the same ID, which must stay
+		// two tasks for skeeter renumber to split, or the file is not
+		// something we understand. Fall back to git's line merge.
+		merged, conflict, err := Text(baseContent, oursContent, theirsContent)
+		if err != nil {
+			return nil, err
+		}
+		var conflicts []string
+		if conflict && baseErr != nil && oursErr == nil && theirsErr == nil {
+			conflicts = append(conflicts, "both branches added "+ours.ID+"; run 'skeeter renumber' to split them")
+		} else if conflict {
+			conflicts = append(conflicts, "file is not a valid task; merged line by line")
+		}
+		return conflicts, os.WriteFile(oursPath, []byte(merged), 0644)
+	}
+
+	merged, conflicts, err := Tasks(base, ours, theirs, statuses)
+	if err != nil {
+		return nil, err
+	}
+	content, err := task.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	return conflicts, os.WriteFile(oursPath, []byte(content), 0644)
+}
+
+// Tasks merges two descendants of base field by field:
+//
+//   - tags, links, depends_on and the other relations keep additions from
+//     both sides and drop entries either side removed
+//   - status takes whichever side is further along statuses, and history
+//     keeps both sides' entries
+//   - updated takes the newest date
+//   - the body is merged line by line
+//   - other fields take whichever side changed them; if both did, ours wins
+//     and the field is reported as a conflict
+func Tasks(base, ours, theirs *task.Task, statuses []string) (*task.Task, []string, error) {
+	merged := *ours
+	var conflicts []string
+
+	scalar := func(field string, b, o, t string) string {
+		switch {
+		case o == t || t == b:
+			return o
+		case o == b:
+			return t
+		default:
+			conflicts = append(conflicts, fmt.Sprintf("%s: %q vs %q (kept %q)", field, o, t, o))
+			return o
+		}
+	}
+
+	merged.Title = scalar("title", base.Title, ours.Title, theirs.Title)
+	merged.Priority = scalar("priority", base.Priority, ours.Priority, theirs.Priority)
+	merged.Rank = scalar("rank", base.Rank, ours.Rank, theirs.Rank)
+	merged.Assignee = scalar("assignee", base.Assignee, ours.Assignee, theirs.Assignee)
+	merged.Due = scalar("due", base.Due, ours.Due, theirs.Due)
+	merged.Type = scalar("type", base.Type, ours.Type, theirs.Type)
+	merged.Branch = scalar("branch", base.Branch, ours.Branch, theirs.Branch)
+	merged.Created = scalar("created", base.Created, ours.Created, theirs.Created)
+
+	merged.Status = ours.Status
+	if ours.Status != theirs.Status && ours.Status == base.Status {
+		merged.Status = theirs.Status
+	} else if ours.Status != theirs.Status && theirs.Status != base.Status &&
+		slices.Index(statuses, theirs.Status) > slices.Index(statuses, ours.Status) {
+		merged.Status = theirs.Status
+	}
+
+	merged.Updated = max(ours.Updated, theirs.Updated)
+
+	merged.Tags = mergeSet(base.Tags, ours.Tags, theirs.Tags)
+	merged.Links = mergeSet(base.Links, ours.Links, theirs.Links)
+	merged.DependsOn = mergeSet(base.DependsOn, ours.DependsOn, theirs.DependsOn)
+	merged.RelatesTo = mergeSet(base.RelatesTo, ours.RelatesTo, theirs.RelatesTo)
+	merged.Duplicates = mergeSet(base.Duplicates, ours.Duplicates, theirs.Duplicates)
+	merged.SplitFrom = mergeSet(base.SplitFrom, ours.SplitFrom, theirs.SplitFrom)
+	merged.History = mergeHistory(base.History, ours.History, theirs.History)
+
+	body, conflict, err := Text(base.Body, ours.Body, theirs.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if conflict {
+		conflicts = append(conflicts, "body: conflicting edits (marked inline)")
+	}
+	merged.Body = body
+
+	return &merged, conflicts, nil
+}
+
+// mergeSet keeps every entry of ours and theirs except those present in base
+// that either side removed. Order follows ours, then theirs' additions.
+func mergeSet(base, ours, theirs task.FlowSlice) task.FlowSlice {
+	var merged task.FlowSlice
+	keep := func(item string) bool {
+		if slices.Contains(merged, item) {
+			return false
+		}
+		if slices.Contains(base, item) {
+			return slices.Contains(ours, item) && slices.Contains(theirs, item)
+		}
+		return true
+	}
+	for _, item := range ours {
+		if keep(item) {
+			merged = append(merged, item)
+		}
+	}
+	for _, item := range theirs {
+		if keep(item) {
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
+
+// mergeHistory keeps base's entries followed by what each side added,
+// ordered by date. History is only ever appended to.
+func mergeHistory(base, ours, theirs []task.Transition) []task.Transition {
+	merged := slices.Clone(ours)
+	for _, t := range theirs[min(len(base), len(theirs)):] {
+		if !slices.Contains(merged, t) {
+			merged = append(merged, t)
+		}
+	}
+	slices.SortStableFunc(merged, func(a, b task.Transition) int {
+		return strings.Compare(a.Date, b.Date)
+	})
+	return merged
+}
+
+// Text performs a line-based three-way merge with git merge-file, reporting
+// whether conflict markers were left in the result.
+func Text(base, ours, theirs string) (string, bool, error) {
+	if ours == theirs || theirs == base {
+		return ours, false, nil
+	}
+	if ours == base {
+		return theirs, false, nil
+	}
+
+	dir, err := os.MkdirTemp("", "skeeter-merge-")
+	if err != nil {
+		return "", false, err
+	}
+	defer os.RemoveAll(dir)
+
+	args := []string{"merge-file", "-p", "-L", "ours", "-L", "base", "-L", "theirs"}
+	for name, content := range map[string]string{"ours": ours, "base": base, "theirs": theirs} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return "", false, err
+		}
+	}
+	for _, name := range []string{"ours", "base", "theirs"} {
+		args = append(args, filepath.Join(dir, name))
+	}
+
+	cmd := exec.Command("git", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+
+	// merge-file exits with the number of conflicts, or a negative status
+	// on error.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return string(out), true, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("git merge-file: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), false, nil
+}
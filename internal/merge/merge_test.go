@@ -0,0 +1,180 @@
+package merge
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+var statuses = []string{"backlog", "ready-for-development", "in-progress", "done"}
+
+func baseTask() *task.Task {
+	return &task.Task{
+		ID:        "US-001",
+		Title:     "Task",
+		Status:    "backlog",
+		Priority:  "medium",
+		Tags:      task.FlowSlice{"api", "old"},
+		DependsOn: task.FlowSlice{"US-000"},
+		Created:   "2026-01-01",
+		Updated:   "2026-01-01",
+		Body:      "line one\nline two\nline three\n",
+	}
+}
+
+func TestTasksMergesIndependentFields(t *testing.T) {
+	base := baseTask()
+
+	ours := baseTask()
+	ours.Status = "in-progress"
+	ours.Tags = task.FlowSlice{"api", "old", "backend"}
+	ours.Updated = "2026-01-03"
+	ours.Body = "line one changed\nline two\nline three\n"
+
+	theirs := baseTask()
+	theirs.Assignee = "agent-1"
+	theirs.Tags = task.FlowSlice{"api", "urgent"}
+	theirs.DependsOn = task.FlowSlice{"US-000", "US-002"}
+	theirs.RelatesTo = task.FlowSlice{"US-005"}
+	theirs.Updated = "2026-01-02"
+	theirs.Body = "line one\nline two\nline three changed\n"
+
+	merged, conflicts, err := Tasks(base, ours, theirs, statuses)
+	if err != nil {
+		t.Fatalf("Tasks: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("conflicts = %v, want none", conflicts)
+	}
+	if merged.Status != "in-progress" || merged.Assignee != "agent-1" || merged.Updated != "2026-01-03" {
+		t.Errorf("merged = %+v", merged)
+	}
+	if !slices.Equal(merged.Tags, task.FlowSlice{"api", "backend", "urgent"}) {
+		t.Errorf("tags = %v, want [api backend urgent]", merged.Tags)
+	}
+	if !slices.Equal(merged.DependsOn, task.FlowSlice{"US-000", "US-002"}) {
+		t.Errorf("depends_on = %v", merged.DependsOn)
+	}
+	if !slices.Equal(merged.RelatesTo, task.FlowSlice{"US-005"}) {
+		t.Errorf("relates_to = %v", merged.RelatesTo)
+	}
+	if merged.Body != "line one changed\nline two\nline three changed\n" {
+		t.Errorf("body = %q", merged.Body)
+	}
+}
+
+func TestTasksStatusFollowsWorkflow(t *testing.T) {
+	base := baseTask()
+	ours := baseTask()
+	ours.Status = "ready-for-development"
+	theirs := baseTask()
+	theirs.Status = "done"
+
+	merged, conflicts, _ := Tasks(base, ours, theirs, statuses)
+	if merged.Status != "done" {
+		t.Errorf("status = %q, want done", merged.Status)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("conflicts = %v, want none", conflicts)
+	}
+
+	merged, _, _ = Tasks(base, theirs, ours, statuses)
+	if merged.Status != "done" {
+		t.Errorf("status with sides swapped = %q, want done", merged.Status)
+	}
+}
+
+func TestTasksReportsScalarConflicts(t *testing.T) {
+	base := baseTask()
+	ours := baseTask()
+	ours.Assignee = "alice"
+	theirs := baseTask()
+	theirs.Assignee = "bob"
+
+	merged, conflicts, _ := Tasks(base, ours, theirs, statuses)
+	if merged.Assignee != "alice" {
+		t.Errorf("assignee = %q, want ours", merged.Assignee)
+	}
+	if len(conflicts) != 1 || !strings.HasPrefix(conflicts[0], "assignee") {
+		t.Errorf("conflicts = %v, want one assignee conflict", conflicts)
+	}
+}
+
+func TestTextConflict(t *testing.T) {
+	merged, conflict, err := Text("a\n", "b\n", "c\n")
+	if err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+	if !conflict || !strings.Contains(merged, "<<<<<<< ours") {
+		t.Errorf("Text = %q, %v; want conflict markers", merged, conflict)
+	}
+}
+
+func writeTask(t *testing.T, path string, tk *task.Task) {
+	t.Helper()
+	content, err := task.Marshal(tk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	basePath, oursPath, theirsPath := filepath.Join(dir, "O"), filepath.Join(dir, "A"), filepath.Join(dir, "B")
+
+	ours := baseTask()
+	ours.Status = "in-progress"
+	theirs := baseTask()
+	theirs.Tags = append(theirs.Tags, "new")
+	writeTask(t, basePath, baseTask())
+	writeTask(t, oursPath, ours)
+	writeTask(t, theirsPath, theirs)
+
+	conflicts, err := Files(basePath, oursPath, theirsPath, statuses)
+	if err != nil {
+		t.Fatalf("Files: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("conflicts = %v", conflicts)
+	}
+	data, _ := os.ReadFile(oursPath)
+	merged, err := task.Parse(string(data))
+	if err != nil {
+		t.Fatalf("merged file does not parse: %v\n%s", err, data)
+	}
+	if merged.Status != "in-progress" || !slices.Contains(merged.Tags, "new") {
+		t.Errorf("merged = %+v", merged)
+	}
+}
+
+func TestFilesLeavesAddAddCollisionsForRenumber(t *testing.T) {
+	dir := t.TempDir()
+	basePath, oursPath, theirsPath := filepath.Join(dir, "O"), filepath.Join(dir, "A"), filepath.Join(dir, "B")
+
+	ours := baseTask()
+	ours.Title = "Ours"
+	theirs := baseTask()
+	theirs.Title = "Theirs"
+	os.WriteFile(basePath, nil, 0644)
+	writeTask(t, oursPath, ours)
+	writeTask(t, theirsPath, theirs)
+
+	conflicts, err := Files(basePath, oursPath, theirsPath, statuses)
+	if err != nil {
+		t.Fatalf("Files: %v", err)
+	}
+	if len(conflicts) != 1 || !strings.Contains(conflicts[0], "renumber") {
+		t.Errorf("conflicts = %v, want a renumber hint", conflicts)
+	}
+	data, _ := os.ReadFile(oursPath)
+	if !strings.Contains(string(data), "title: Ours") || !strings.Contains(string(data), "title: Theirs") {
+		t.Errorf("merged file should keep both sides:\n%s", data)
+	}
+}
@@ -0,0 +1,67 @@
+// Package rank generates lexicographic sort keys for manually ordering
+// tasks. A key can always be generated between any two others, so moving a
+// task only ever rewrites that task.
+package rank
+
+import (
+	"fmt"
+	"strings"
+)
+
+// digits are the key alphabet, in sort order.
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// Valid reports whether key is a usable rank. Keys never end in "0", which
+// would leave no room below them.
+func Valid(key string) bool {
+	if key == "" || strings.HasSuffix(key, "0") {
+		return false
+	}
+	for _, c := range key {
+		if !strings.ContainsRune(digits, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Between returns a key that sorts after lo and before hi. An empty lo means
+// no lower bound and an empty hi no upper bound, so Between("", "") returns
+// a key in the middle of the range.
+func Between(lo, hi string) (string, error) {
+	for _, key := range []string{lo, hi} {
+		if key != "" && !Valid(key) {
+			return "", fmt.Errorf("invalid rank %q", key)
+		}
+	}
+	if hi != "" && lo >= hi {
+		return "", fmt.Errorf("rank %q does not sort before %q", lo, hi)
+	}
+
+	// Treat the keys as base-36 fractions and walk them digit by digit.
+	// Once the result drops below hi at some digit, hi no longer bounds the
+	// rest of the key.
+	var key []byte
+	bounded := hi != ""
+	for i := 0; ; i++ {
+		l := 0
+		if i < len(lo) {
+			l = strings.IndexByte(digits, lo[i])
+		}
+		h := len(digits)
+		if bounded {
+			h = 0
+			if i < len(hi) {
+				h = strings.IndexByte(digits, hi[i])
+			}
+		}
+
+		switch {
+		case h-l >= 2:
+			return string(append(key, digits[(l+h)/2])), nil
+		case h-l == 1:
+			bounded = false
+		}
+		key = append(key, digits[l])
+	}
+}
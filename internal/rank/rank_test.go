@@ -0,0 +1,81 @@
+package rank
+
+import "testing"
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		lo, hi string
+	}{
+		{"", ""},
+		{"i", ""},
+		{"", "i"},
+		{"a", "b"},
+		{"a", "a1"},
+		{"z", ""},
+		{"zz", ""},
+		{"", "1"},
+		{"", "01"},
+		{"ai", "aj"},
+		{"y", "z"},
+	}
+	for _, tt := range tests {
+		got, err := Between(tt.lo, tt.hi)
+		if err != nil {
+			t.Errorf("Between(%q, %q): %v", tt.lo, tt.hi, err)
+			continue
+		}
+		if !Valid(got) {
+			t.Errorf("Between(%q, %q) = %q, not a valid rank", tt.lo, tt.hi, got)
+		}
+		if got <= tt.lo || (tt.hi != "" && got >= tt.hi) {
+			t.Errorf("Between(%q, %q) = %q, out of order", tt.lo, tt.hi, got)
+		}
+	}
+}
+
+func TestBetweenErrors(t *testing.T) {
+	tests := []struct {
+		lo, hi string
+	}{
+		{"b", "a"},
+		{"a", "a"},
+		{"a0", ""},
+		{"A", ""},
+	}
+	for _, tt := range tests {
+		if got, err := Between(tt.lo, tt.hi); err == nil {
+			t.Errorf("Between(%q, %q) = %q, want error", tt.lo, tt.hi, got)
+		}
+	}
+}
+
+func TestBetweenRepeatedInserts(t *testing.T) {
+	// Inserting at the same spot over and over must keep working and keep
+	// keys short.
+	lo, hi := "a", "b"
+	for i := 0; i < 100; i++ {
+		key, err := Between(lo, hi)
+		if err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+		if key <= lo || key >= hi {
+			t.Fatalf("insert %d: %q not between %q and %q", i, key, lo, hi)
+		}
+		hi = key
+	}
+	if len(hi) > 40 {
+		t.Errorf("key grew to %d characters", len(hi))
+	}
+
+	prev := ""
+	for i := 0; i < 100; i++ {
+		key, err := Between(prev, "")
+		if err != nil {
+			t.Fatalf("append %d: %v", i, err)
+		}
+		if key <= prev {
+			t.Fatalf("append %d: %q not after %q", i, key, prev)
+		}
+		prev = key
+	}
+}
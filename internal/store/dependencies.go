@@ -1,6 +1,9 @@
 package store
 
 import (
+	"slices"
+
+	"github.com/andybarilla/skeeter/internal/graph"
 	"github.com/andybarilla/skeeter/internal/task"
 )
 
@@ -12,6 +15,12 @@ type DependencyStatus struct {
 }
 
 func GetDependencyStatus(s Store, t *task.Task, allTasks []task.Task) *DependencyStatus {
+	return dependencyStatus(s, t, allTasks, backlinks(s, t.ID, allTasks))
+}
+
+// dependencyStatus works out t's dependency status, looking its
+// dependencies up in tasks and taking the tasks it blocks from links.
+func dependencyStatus(s Store, t *task.Task, tasks []task.Task, links map[string][]string) *DependencyStatus {
 	status := &DependencyStatus{
 		AllDependenciesMet: true,
 	}
@@ -19,8 +28,8 @@ func GetDependencyStatus(s Store, t *task.Task, allTasks []task.Task) *Dependenc
 	doneStatus := getDoneStatus(s)
 
 	taskMap := make(map[string]*task.Task)
-	for i := range allTasks {
-		taskMap[allTasks[i].ID] = &allTasks[i]
+	for i := range tasks {
+		taskMap[tasks[i].ID] = &tasks[i]
 	}
 
 	for _, depID := range t.DependsOn {
@@ -32,15 +41,101 @@ func GetDependencyStatus(s Store, t *task.Task, allTasks []task.Task) *Dependenc
 		}
 	}
 
-	for i := range allTasks {
-		for _, depID := range allTasks[i].DependsOn {
-			if depID == t.ID {
-				status.Blocking = append(status.Blocking, allTasks[i].ID)
+	status.Blocking = links["depends_on"]
+	return status
+}
+
+// Relations are a task's informational links to other tasks in both
+// directions: those in its own frontmatter and the backlinks from tasks
+// that name it.
+type Relations struct {
+	// RelatesTo has no direction, so it lists both sides.
+	RelatesTo    []string `json:"relates_to,omitempty" yaml:"relates_to,omitempty"`
+	Duplicates   []string `json:"duplicates,omitempty" yaml:"duplicates,omitempty"`
+	DuplicatedBy []string `json:"duplicated_by,omitempty" yaml:"duplicated_by,omitempty"`
+	SplitFrom    []string `json:"split_from,omitempty" yaml:"split_from,omitempty"`
+	SplitInto    []string `json:"split_into,omitempty" yaml:"split_into,omitempty"`
+}
+
+// GetRelations returns t's relations, computing backlinks from allTasks.
+func GetRelations(t *task.Task, allTasks []task.Task) *Relations {
+	return relations(t, linksFrom(t.ID, allTasks))
+}
+
+func relations(t *task.Task, links map[string][]string) *Relations {
+	r := &Relations{
+		RelatesTo:  slices.Clone(t.RelatesTo),
+		Duplicates: slices.Clone(t.Duplicates),
+		SplitFrom:  slices.Clone(t.SplitFrom),
+	}
+	for _, id := range links["relates_to"] {
+		if id != t.ID && !slices.Contains(r.RelatesTo, id) {
+			r.RelatesTo = append(r.RelatesTo, id)
+		}
+	}
+	for _, id := range links["duplicates"] {
+		if id != t.ID {
+			r.DuplicatedBy = append(r.DuplicatedBy, id)
+		}
+	}
+	for _, id := range links["split_from"] {
+		if id != t.ID {
+			r.SplitInto = append(r.SplitInto, id)
+		}
+	}
+	return r
+}
+
+// GetLinks returns t's dependency status and relations for showing it on
+// its own. A store with a backlink table answers from it and only t's
+// dependencies are read; otherwise every task is listed.
+func GetLinks(s Store, t *task.Task) (*DependencyStatus, *Relations, error) {
+	var tasks []task.Task
+	links, ok := indexedBacklinks(s, t.ID)
+	if ok {
+		for _, id := range t.DependsOn {
+			// A dependency that can't be read counts as unfinished.
+			if dep, err := s.Get(id); err == nil {
+				tasks = append(tasks, *dep)
 			}
 		}
+	} else {
+		var err error
+		if tasks, err = s.List(Filter{}); err != nil {
+			return nil, nil, err
+		}
+		links = linksFrom(t.ID, tasks)
 	}
+	return dependencyStatus(s, t, tasks, links), relations(t, links), nil
+}
 
-	return status
+// backlinks returns the IDs of the tasks naming id by relation kind, from
+// the store's backlink table if it keeps one and otherwise from allTasks.
+func backlinks(s Store, id string, allTasks []task.Task) map[string][]string {
+	if links, ok := indexedBacklinks(s, id); ok {
+		return links
+	}
+	return linksFrom(id, allTasks)
+}
+
+func indexedBacklinks(s Store, id string) (map[string][]string, bool) {
+	if b, ok := s.(Backlinker); ok {
+		return b.Backlinks(id)
+	}
+	return nil, false
+}
+
+// linksFrom collects the backlinks to id by scanning tasks.
+func linksFrom(id string, tasks []task.Task) map[string][]string {
+	links := make(map[string][]string)
+	for i := range tasks {
+		for _, kind := range task.Relations {
+			if slices.Contains(*tasks[i].Relation(kind), id) {
+				links[kind] = append(links[kind], tasks[i].ID)
+			}
+		}
+	}
+	return links
 }
 
 func IsBlocked(s Store, t *task.Task, allTasks []task.Task) bool {
@@ -73,33 +168,35 @@ func getDoneStatus(s Store) string {
 	return "done"
 }
 
+// DetectCircularDependency reports whether saving t would put it on a
+// dependency cycle, returning the cycle starting at t (each task depends on
+// the next, and the last on t), or nil. It reads the board once and checks
+// the graph in memory. A GraphLoader fetches the board in one request;
+// otherwise the store is listed, which on remote stores without one can
+// mean a request per task file.
 func DetectCircularDependency(t *task.Task, s Store) ([]string, error) {
-	visited := make(map[string]bool)
-	path := []string{}
-	return detectCycle(t.ID, t.DependsOn, s, visited, path)
-}
-
-func detectCycle(startID string, dependsOn []string, s Store, visited map[string]bool, path []string) ([]string, error) {
-	for _, depID := range dependsOn {
-		if depID == startID {
-			return append(path, depID), nil
-		}
-
-		if visited[depID] {
-			continue
-		}
-		visited[depID] = true
-
-		depTask, err := s.Get(depID)
-		if err != nil {
-			continue
-		}
-
-		newPath := append(path, depID)
-		if cycle, err := detectCycle(startID, depTask.DependsOn, s, visited, newPath); err == nil && len(cycle) > 0 {
-			return cycle, nil
+	if len(t.DependsOn) == 0 {
+		return nil, nil
+	}
+	var tasks []task.Task
+	var err error
+	if loader, ok := s.(GraphLoader); ok {
+		tasks, err = loader.LoadGraph()
+	} else {
+		tasks, err = s.List(Filter{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	replaced := false
+	for i := range tasks {
+		if tasks[i].ID == t.ID {
+			tasks[i] = *t
+			replaced = true
 		}
 	}
-
-	return nil, nil
+	if !replaced {
+		tasks = append(tasks, *t)
+	}
+	return graph.Build(tasks, "").CycleThrough(t.ID), nil
 }
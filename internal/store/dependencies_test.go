@@ -2,6 +2,7 @@ package store
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/andybarilla/skeeter/internal/config"
@@ -53,6 +54,9 @@ func (m *mockStore) NextID() (string, error) {
 	return "US-999", nil
 }
 func (m *mockStore) GetConfig() *config.Config { return m.config }
+func (m *mockStore) UpdateConfig(fn func(*config.Config) error) error {
+	return fn(m.config)
+}
 func (m *mockStore) LoadTemplate(name string) (string, error) {
 	return "template", nil
 }
@@ -192,3 +196,27 @@ func TestDetectCircularDependency(t *testing.T) {
 		}
 	})
 }
+
+func TestGetRelations(t *testing.T) {
+	tasks := []task.Task{
+		{ID: "US-001", RelatesTo: task.FlowSlice{"US-002"}},
+		{ID: "US-002", RelatesTo: task.FlowSlice{"US-001"}, SplitFrom: task.FlowSlice{"US-001"}},
+		{ID: "US-003", RelatesTo: task.FlowSlice{"US-001"}, Duplicates: task.FlowSlice{"US-001"}},
+	}
+
+	rel := GetRelations(&tasks[0], tasks)
+	if !reflect.DeepEqual(rel.RelatesTo, []string{"US-002", "US-003"}) {
+		t.Errorf("RelatesTo = %v, want [US-002 US-003]", rel.RelatesTo)
+	}
+	if !reflect.DeepEqual(rel.DuplicatedBy, []string{"US-003"}) {
+		t.Errorf("DuplicatedBy = %v, want [US-003]", rel.DuplicatedBy)
+	}
+	if !reflect.DeepEqual(rel.SplitInto, []string{"US-002"}) {
+		t.Errorf("SplitInto = %v, want [US-002]", rel.SplitInto)
+	}
+
+	rel = GetRelations(&tasks[2], tasks)
+	if !reflect.DeepEqual(rel.Duplicates, []string{"US-001"}) || rel.DuplicatedBy != nil {
+		t.Errorf("relations of US-003 = %+v", rel)
+	}
+}
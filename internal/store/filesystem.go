@@ -2,20 +2,42 @@ package store
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/andybarilla/skeeter/internal/config"
 	"github.com/andybarilla/skeeter/internal/id"
 	"github.com/andybarilla/skeeter/internal/task"
+	"github.com/andybarilla/skeeter/internal/webhook"
 )
 
 type FilesystemStore struct {
 	Dir    string
 	Config *config.Config
+	// HookOutput receives the output of lifecycle hooks and webhook
+	// warnings; nil means stderr.
+	HookOutput io.Writer
+
+	// noHooks disables lifecycle hooks, for stores whose files the user
+	// hasn't checked out themselves.
+	noHooks bool
+	// noEvents leaves webhook events to a wrapping store that only knows
+	// a write landed once it has pushed it.
+	noEvents bool
+
+	// mu guards index, which is loaded lazily, and problems, which every
+	// List replaces, so the store can be read from several goroutines.
+	mu       sync.Mutex
+	index    *taskIndex
+	problems []TaskFile
+	// reserved lists files NextID changed that should be committed with the
+	// next task write.
+	reserved []string
 }
 
 func NewFilesystem(dir string) (*FilesystemStore, error) {
@@ -61,16 +83,18 @@ func (s *FilesystemStore) Init(projectName string) error {
 	return s.writeSkeeterMD()
 }
 
-func (s *FilesystemStore) writeDefaultTemplate() error {
-	content := `## Acceptance Criteria
+// defaultTemplate is the body of templates/default.md written on init.
+const defaultTemplate = `## Acceptance Criteria
 
 - [ ]
 
 ## Context
 
 `
+
+func (s *FilesystemStore) writeDefaultTemplate() error {
 	path := filepath.Join(s.templatesDir(), "default.md")
-	return os.WriteFile(path, []byte(content), 0644)
+	return os.WriteFile(path, []byte(defaultTemplate), 0644)
 }
 
 func (s *FilesystemStore) RegenerateSkeeterMD() error {
@@ -78,51 +102,96 @@ func (s *FilesystemStore) RegenerateSkeeterMD() error {
 }
 
 func (s *FilesystemStore) writeSkeeterMD() error {
+	path := filepath.Join(s.Dir, "SKEETER.md")
+	return os.WriteFile(path, []byte(skeeterMDContent(s.Config)), 0644)
+}
+
+// skeeterMDContent renders the agent instructions for the given config.
+func skeeterMDContent(cfg *config.Config) string {
 	readyStatus := "ready-for-development"
 	inProgressStatus := "in-progress"
 	doneStatus := "done"
-	if len(s.Config.Statuses) >= 2 {
-		readyStatus = s.Config.Statuses[1]
+	if len(cfg.Statuses) >= 2 {
+		readyStatus = cfg.Statuses[1]
 	}
-	if len(s.Config.Statuses) >= 3 {
-		inProgressStatus = s.Config.Statuses[2]
+	if len(cfg.Statuses) >= 3 {
+		inProgressStatus = cfg.Statuses[2]
 	}
-	if len(s.Config.Statuses) >= 1 {
-		doneStatus = s.Config.Statuses[len(s.Config.Statuses)-1]
+	if len(cfg.Statuses) >= 1 {
+		doneStatus = cfg.Statuses[len(cfg.Statuses)-1]
 	}
 
-	prefix := s.Config.Project.Prefix
+	prefix := cfg.Project.Prefix
 
-	content := "# Skeeter — Project Tasks\n\n" +
+	return "# Skeeter — Project Tasks\n\n" +
 		"Tasks are markdown files with YAML frontmatter in the `tasks/` subdirectory.\n\n" +
 		"## For Agents: Finding Work\n\n" +
-		"1. Look for tasks where `status: " + readyStatus + "` and `assignee:` is empty\n" +
+		"1. Look for tasks where `status: " + readyStatus + "` and `assignee:` is empty, highest priority first, then lowest `rank`\n" +
 		"2. Check that all tasks in `depends_on` have status: " + doneStatus + "\n" +
 		"3. Set `assignee: <your-name>` and `status: " + inProgressStatus + "` before starting\n" +
 		"4. Use `Acceptance Criteria` as your definition of done\n" +
 		"5. Set `status: " + doneStatus + "` when complete\n\n" +
 		"## Dependencies\n\n" +
-		"Tasks can depend on other tasks using the `depends_on` field. A task is \"blocked\" until all its dependencies are complete.\n\n" +
+		"Tasks can depend on other tasks using the `depends_on` field. A task is \"blocked\" until all its dependencies are complete. " +
+		"Run `skeeter plan-order` for an order that respects dependencies and the critical path of unfinished work.\n\n" +
+		"`relates_to`, `duplicates` and `split_from` link tasks without blocking them; `skeeter show` lists them from both sides. " +
+		"Change any of these lists with `skeeter deps add` and `skeeter deps rm`.\n\n" +
 		"## Frontmatter Fields\n\n" +
 		"| Field      | Description                                              |\n" +
 		"|------------|----------------------------------------------------------|\n" +
 		"| id         | Task identifier (e.g., " + prefix + "-001)                           |\n" +
 		"| title      | Short task title                                         |\n" +
-		"| status     | One of: " + strings.Join(s.Config.Statuses, ", ") + " |\n" +
-		"| priority   | One of: " + strings.Join(s.Config.Priorities, ", ") + " |\n" +
+		"| status     | One of: " + strings.Join(cfg.Statuses, ", ") + " |\n" +
+		"| priority   | One of: " + strings.Join(cfg.Priorities, ", ") + " |\n" +
+		"| rank       | Manual order within a priority; lower sorts first        |\n" +
 		"| assignee   | Who is working on this (empty = available)               |\n" +
+		"| type       | Kind of task, e.g. bug or feature (set by `create -T`)   |\n" +
 		"| tags       | Array of labels                                          |\n" +
 		"| links      | Related URLs                                             |\n" +
+		"| branch     | Git branch for the task, set by `skeeter branch`         |\n" +
 		"| depends_on | Array of task IDs that must be complete first            |\n" +
+		"| relates_to | Array of related task IDs (informational)                |\n" +
+		"| duplicates | Array of task IDs this task duplicates                   |\n" +
+		"| split_from | Array of task IDs this task was split out of             |\n" +
 		"| due        | Due date (format: YYYY-MM-DD)                            |\n" +
 		"| created    | Creation date                                            |\n" +
-		"| updated    | Last modified date                                       |\n"
+		"| updated    | Last modified date                                       |\n" +
+		"| history    | Status changes, appended by skeeter; don't edit          |\n"
+}
 
-	path := filepath.Join(s.Dir, "SKEETER.md")
-	return os.WriteFile(path, []byte(content), 0644)
+// UpdateConfig re-reads config.yaml, applies fn and saves the result,
+// regenerating SKEETER.md to match.
+func (s *FilesystemStore) UpdateConfig(fn func(*config.Config) error) error {
+	cfg, err := config.Load(s.Dir)
+	if err != nil {
+		return err
+	}
+	if err := fn(cfg); err != nil {
+		return err
+	}
+	if err := cfg.Save(s.Dir); err != nil {
+		return err
+	}
+	s.Config = cfg
+	return s.writeSkeeterMD()
 }
 
 func (s *FilesystemStore) List(filter Filter) ([]task.Task, error) {
+	if s.Config.Index {
+		return s.listIndexed(filter)
+	}
+
+	files, err := s.Scan()
+	if err != nil {
+		return nil, err
+	}
+	tasks, problems := tasksFromFiles(files, filter)
+	s.setProblems(problems)
+	return tasks, nil
+}
+
+// Scan reads and parses every task file, including malformed ones.
+func (s *FilesystemStore) Scan() ([]TaskFile, error) {
 	entries, err := os.ReadDir(s.tasksDir())
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -131,29 +200,35 @@ func (s *FilesystemStore) List(filter Filter) ([]task.Task, error) {
 		return nil, err
 	}
 
-	var tasks []task.Task
+	var files []TaskFile
 	for _, e := range entries {
 		if e.IsDir() || filepath.Ext(e.Name()) != ".md" {
 			continue
 		}
 
+		f := TaskFile{Name: e.Name()}
 		data, err := os.ReadFile(filepath.Join(s.tasksDir(), e.Name()))
 		if err != nil {
-			continue
-		}
-
-		t, err := task.Parse(string(data))
-		if err != nil {
-			continue
+			f.Err = err
+		} else {
+			f.Task, f.Err = task.Parse(string(data))
 		}
+		files = append(files, f)
+	}
+	return files, nil
+}
 
-		if !matchesFilter(t, filter) {
-			continue
-		}
+// Problems returns the files the most recent List skipped.
+func (s *FilesystemStore) Problems() []TaskFile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.problems
+}
 
-		tasks = append(tasks, *t)
-	}
-	return tasks, nil
+func (s *FilesystemStore) setProblems(problems []TaskFile) {
+	s.mu.Lock()
+	s.problems = problems
+	s.mu.Unlock()
 }
 
 func matchesFilter(t *task.Task, f Filter) bool {
@@ -182,6 +257,12 @@ func matchesFilter(t *task.Task, f Filter) bool {
 }
 
 func (s *FilesystemStore) Get(taskID string) (*task.Task, error) {
+	if s.Config.Index {
+		if t, ok := s.getIndexed(taskID); ok {
+			return t, nil
+		}
+	}
+
 	data, err := os.ReadFile(s.taskPath(taskID))
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -193,20 +274,55 @@ func (s *FilesystemStore) Get(taskID string) (*task.Task, error) {
 }
 
 func (s *FilesystemStore) Create(t *task.Task) error {
+	if err := applyCreateStatus(s, t); err != nil {
+		return err
+	}
+	if err := s.runHook(HookPreCreate, nil, t); err != nil {
+		return err
+	}
 	content, err := task.Marshal(t)
 	if err != nil {
 		return err
 	}
+	// git does not track empty directories, so a fresh clone may lack tasks/
+	if err := os.MkdirAll(s.tasksDir(), 0755); err != nil {
+		return err
+	}
 	path := s.taskPath(t.ID)
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		return err
 	}
+	s.indexWritten(path, t)
 	s.writeSkeeterMD()
-	return s.autoCommit(fmt.Sprintf("create %s: %s", t.ID, t.Title), path)
+	files := append([]string{path}, s.reserved...)
+	s.reserved = nil
+	if err := s.autoCommit(fmt.Sprintf("create %s: %s", t.ID, t.Title), files...); err != nil {
+		return err
+	}
+	s.sendEvents(nil, t, webhook.EventCreate)
+	return s.runHook(HookPostCreate, nil, t)
 }
 
+// Update saves t. A status change that didn't go through ChangeStatus must
+// follow the workflow from the stored status; see ApplyStatusChange.
 func (s *FilesystemStore) Update(t *task.Task) error {
+	old, _ := s.Get(t.ID)
+	return s.UpdateFrom(old, t)
+}
+
+// UpdateFrom saves t like Update, giving hooks old as the task before the
+// change. It is for callers that changed the task file themselves, such as
+// edit, where the file on disk no longer shows what the task was.
+func (s *FilesystemStore) UpdateFrom(old, t *task.Task) error {
+	if old != nil {
+		if err := ApplyStatusChange(s, t, old, false); err != nil {
+			return err
+		}
+	}
 	t.Updated = time.Now().Format("2006-01-02")
+	if err := s.runHook(HookPreUpdate, old, t); err != nil {
+		return err
+	}
 	content, err := task.Marshal(t)
 	if err != nil {
 		return err
@@ -215,12 +331,121 @@ func (s *FilesystemStore) Update(t *task.Task) error {
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		return err
 	}
+	s.indexWritten(path, t)
 	s.writeSkeeterMD()
-	return s.autoCommit(fmt.Sprintf("update %s: %s", t.ID, t.Title), path)
+	if err := s.autoCommit(fmt.Sprintf("update %s: %s", t.ID, t.Title), path); err != nil {
+		return err
+	}
+	s.sendEvents(old, t, updateEvents(old, t)...)
+	if err := s.runHook(HookPostUpdate, old, t); err != nil {
+		return err
+	}
+	if old != nil && old.Status != t.Status {
+		return s.runHook(HookOnStatusChange, old, t)
+	}
+	return nil
 }
 
 func (s *FilesystemStore) NextID() (string, error) {
-	return id.Next(s.tasksDir(), s.Config.Project.Prefix)
+	prefix := s.Config.Project.Prefix
+	switch s.Config.IDs.Strategy {
+	case id.Hash:
+		names, err := id.Names(s.tasksDir())
+		if err != nil {
+			return "", err
+		}
+		return id.HashFromNames(names, prefix)
+	case id.Block:
+		return s.nextBlockID()
+	default:
+		return id.Next(s.tasksDir(), prefix)
+	}
+}
+
+func (s *FilesystemStore) reservationsPath() string {
+	return filepath.Join(s.Dir, "id-blocks")
+}
+
+// nextBlockID allocates from the author's reserved blocks, appending a new
+// reservation when they run out. The reservations file is marked for git's
+// union merge so blocks reserved on parallel branches merge without conflict.
+func (s *FilesystemStore) nextBlockID() (string, error) {
+	author, err := s.author()
+	if err != nil {
+		return "", err
+	}
+	names, err := id.Names(s.tasksDir())
+	if err != nil {
+		return "", err
+	}
+
+	path := s.reservationsPath()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	next, reservation, err := id.BlockFromNames(names, s.Config.Project.Prefix, author, s.Config.IDs.BlockSize, id.ParseReservations(string(data)))
+	if err != nil || reservation == nil {
+		return next, err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := fmt.Fprintln(f, reservation.String()); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	attributes := filepath.Join(s.Dir, ".gitattributes")
+	if err := AddGitAttribute(attributes, "id-blocks merge=union"); err != nil {
+		return "", err
+	}
+	s.reserved = []string{path, attributes}
+	return next, nil
+}
+
+// AddGitAttribute appends line to the attributes file unless it is present.
+func AddGitAttribute(path, line string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, existing := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(existing) == line {
+			return nil
+		}
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return os.WriteFile(path, []byte(content+line+"\n"), 0644)
+}
+
+// author identifies who block IDs are reserved for: $SKEETER_AUTHOR, then
+// the git user email, then the login name.
+func (s *FilesystemStore) author() (string, error) {
+	if author := os.Getenv("SKEETER_AUTHOR"); author != "" {
+		return author, nil
+	}
+	cmd := exec.Command("git", "config", "user.email")
+	cmd.Dir = filepath.Dir(s.Dir)
+	if out, err := cmd.Output(); err == nil {
+		if email := strings.TrimSpace(string(out)); email != "" {
+			return email, nil
+		}
+	}
+	if user := os.Getenv("USER"); user != "" {
+		return user, nil
+	}
+	return "", fmt.Errorf("cannot tell who to reserve IDs for (set SKEETER_AUTHOR or git user.email)")
 }
 
 func (s *FilesystemStore) GetConfig() *config.Config {
@@ -0,0 +1,395 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/task"
+	"github.com/andybarilla/skeeter/internal/webhook"
+)
+
+// pushAttempts bounds how many times a rejected push is rebased and retried.
+const pushAttempts = 3
+
+// GitStore works against any git remote (GitLab, Gitea, bare repositories)
+// by keeping a shallow clone in the user cache directory. Reads are served
+// from the clone; every write is committed and pushed, rebasing on top of
+// concurrent changes when the push is rejected.
+type GitStore struct {
+	url      string
+	dir      string
+	cloneDir string
+	fs       *FilesystemStore
+	// mu keeps reads off the clone while a sync or write changes it.
+	mu sync.Mutex
+}
+
+// IsGitURL reports whether remote looks like a git URL or path rather than
+// a GitHub owner/repo shorthand.
+func IsGitURL(remote string) bool {
+	return strings.Contains(remote, "://") ||
+		strings.HasPrefix(remote, "git@") ||
+		strings.HasSuffix(remote, ".git") ||
+		filepath.IsAbs(remote)
+}
+
+// NewRemote opens the backend matching the remote format: a GitStore for git
+// URLs and a GitHubStore for owner/repo.
+func NewRemote(remote, dir string) (Store, error) {
+	if IsGitURL(remote) {
+		return NewGit(remote, dir)
+	}
+	return NewGitHub(remote, dir)
+}
+
+// NewRemoteForInit opens a remote backend whose skeeter directory may not
+// exist yet. Only Init is meaningful on the returned store.
+func NewRemoteForInit(remote, dir string) (Store, error) {
+	if IsGitURL(remote) {
+		cacheRoot, err := gitCacheRoot()
+		if err != nil {
+			return nil, err
+		}
+		return newGitClone(remote, dir, cacheRoot)
+	}
+	return newGitHubClient(remote, dir)
+}
+
+func NewGit(url, dir string) (*GitStore, error) {
+	cacheRoot, err := gitCacheRoot()
+	if err != nil {
+		return nil, err
+	}
+	return newGitStore(url, dir, cacheRoot)
+}
+
+func gitCacheRoot() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "skeeter", "git"), nil
+}
+
+func newGitStore(url, dir, cacheRoot string) (*GitStore, error) {
+	s, err := newGitClone(url, dir, cacheRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.Load(s.skeeterDir())
+	if err != nil {
+		return nil, fmt.Errorf("loading remote config: %w", err)
+	}
+	// Hooks in the cached clone would run code nobody checked out, and
+	// webhooks wait until a write has been pushed.
+	s.fs = &FilesystemStore{Dir: s.skeeterDir(), Config: cfg, noHooks: true, noEvents: true}
+
+	return s, nil
+}
+
+// newGitClone syncs the cached clone without requiring skeeter to be
+// initialized in it yet.
+func newGitClone(url, dir, cacheRoot string) (*GitStore, error) {
+	if dir == "" {
+		dir = ".skeeter"
+	}
+
+	sum := sha256.Sum256([]byte(url))
+	s := &GitStore{
+		url:      url,
+		dir:      dir,
+		cloneDir: filepath.Join(cacheRoot, hex.EncodeToString(sum[:8])),
+	}
+
+	if err := s.sync(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *GitStore) skeeterDir() string {
+	return filepath.Join(s.cloneDir, s.dir)
+}
+
+func (s *GitStore) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = s.cloneDir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w\n%s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func isExitCode(err error, code int) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == code
+}
+
+func (s *GitStore) branch() (string, error) {
+	return s.git("symbolic-ref", "--short", "HEAD")
+}
+
+// sync clones the remote on first use and otherwise resets the clone to the
+// remote branch tip, discarding anything left over from a failed push.
+func (s *GitStore) sync() error {
+	if _, err := os.Stat(filepath.Join(s.cloneDir, ".git")); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(s.cloneDir), 0755); err != nil {
+			return err
+		}
+		cmd := exec.Command("git", "clone", "--depth", "1", s.url, s.cloneDir)
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			os.RemoveAll(s.cloneDir)
+			return fmt.Errorf("cloning %s: %w\n%s", s.url, err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	if fetched, err := s.fetch(); err != nil || !fetched {
+		return err
+	}
+	_, err := s.git("reset", "--hard", "FETCH_HEAD")
+	return err
+}
+
+// fetch downloads the remote branch tip into FETCH_HEAD without touching the
+// clone's files, reporting false when the remote is still empty.
+func (s *GitStore) fetch() (bool, error) {
+	branch, err := s.branch()
+	if err != nil {
+		return false, err
+	}
+	if _, err := s.git("fetch", "--depth", "1", "origin", branch); err != nil {
+		// An empty remote has no branch to fetch yet.
+		if _, lerr := s.git("ls-remote", "--exit-code", "origin", branch); isExitCode(lerr, 2) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Refresh pulls the latest remote state into the cached clone.
+func (s *GitStore) Refresh() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.sync(); err != nil {
+		return err
+	}
+	return s.reloadConfig()
+}
+
+// Fingerprint fetches the remote and returns the blob SHA of every task
+// file. The fetch runs under the lock: writes fetch too, and two fetches
+// into a shallow clone fight over shallow.lock and FETCH_HEAD.
+func (s *GitStore) Fingerprint() (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fetched, err := s.fetch()
+	if err != nil {
+		return nil, err
+	}
+	// Only move the clone when the remote has something it lacks.
+	if _, err := s.git("merge-base", "--is-ancestor", "FETCH_HEAD", "HEAD"); fetched && err != nil {
+		if _, err := s.git("reset", "--hard", "FETCH_HEAD"); err != nil {
+			return nil, err
+		}
+		if err := s.reloadConfig(); err != nil {
+			return nil, err
+		}
+	}
+	out, err := s.git("ls-tree", "HEAD", "--", filepath.ToSlash(filepath.Join(s.dir, "tasks"))+"/")
+	if err != nil {
+		return nil, err
+	}
+	prints := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		// <mode> blob <sha>\t<path>
+		meta, path, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 3 || fields[1] != "blob" || filepath.Ext(path) != ".md" {
+			continue
+		}
+		prints[strings.TrimSuffix(filepath.Base(path), ".md")] = fields[2]
+	}
+	return prints, nil
+}
+
+// reloadConfig rereads the config after the clone moved.
+func (s *GitStore) reloadConfig() error {
+	if s.fs == nil {
+		return nil
+	}
+	cfg, err := config.Load(s.skeeterDir())
+	if err != nil {
+		return fmt.Errorf("loading remote config: %w", err)
+	}
+	s.fs.Config = cfg
+	return nil
+}
+
+// commitAndPush commits everything under the skeeter directory and pushes it,
+// rebasing onto the remote and retrying when the push is rejected.
+func (s *GitStore) commitAndPush(message string) error {
+	if _, err := s.git("add", "-A", "--", s.dir); err != nil {
+		return err
+	}
+	// The filesystem store may already have committed when auto_commit is on.
+	if _, err := s.git("diff", "--cached", "--quiet"); err != nil {
+		if _, err := s.git("commit", "-m", "skeeter: "+message); err != nil {
+			return err
+		}
+	}
+
+	branch, err := s.branch()
+	if err != nil {
+		return err
+	}
+
+	// Every rebase is followed by another push, so the last attempt's
+	// rebase isn't wasted.
+	for attempt := 0; ; attempt++ {
+		_, pushErr := s.git("push", "origin", "HEAD:"+branch)
+		if pushErr == nil {
+			return nil
+		}
+		if attempt == pushAttempts {
+			return fmt.Errorf("pushing to %s: %w", s.url, pushErr)
+		}
+		if _, err := s.git("pull", "--rebase", "origin", branch); err != nil {
+			s.git("rebase", "--abort")
+			return fmt.Errorf("rebasing onto %s: %w", s.url, err)
+		}
+	}
+}
+
+func (s *GitStore) Init(projectName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := os.Stat(filepath.Join(s.skeeterDir(), "config.yaml")); err == nil {
+		return fmt.Errorf("skeeter already initialized in %s", s.url)
+	}
+	fs := &FilesystemStore{Dir: s.skeeterDir(), noHooks: true, noEvents: true}
+	if err := fs.Init(projectName); err != nil {
+		return err
+	}
+	s.fs = fs
+	return s.commitAndPush("init " + projectName)
+}
+
+func (s *GitStore) List(filter Filter) ([]task.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.List(filter)
+}
+
+func (s *GitStore) Scan() ([]TaskFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.Scan()
+}
+
+func (s *GitStore) Problems() []TaskFile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.Problems()
+}
+
+func (s *GitStore) Get(id string) (*task.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.Get(id)
+}
+
+func (s *GitStore) Create(t *task.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.sync(); err != nil {
+		return err
+	}
+	if err := s.fs.Create(t); err != nil {
+		return err
+	}
+	if err := s.commitAndPush(fmt.Sprintf("create %s: %s", t.ID, t.Title)); err != nil {
+		return err
+	}
+	sendEvents(s.Outbox(), s.fs.Config, s.fs.hookOutput(), nil, t, webhook.EventCreate)
+	return nil
+}
+
+func (s *GitStore) Update(t *task.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.sync(); err != nil {
+		return err
+	}
+	var old *task.Task
+	if len(s.fs.Config.Webhooks) > 0 {
+		old, _ = s.fs.Get(t.ID)
+	}
+	if err := s.fs.Update(t); err != nil {
+		return err
+	}
+	if err := s.commitAndPush(fmt.Sprintf("update %s: %s", t.ID, t.Title)); err != nil {
+		return err
+	}
+	sendEvents(s.Outbox(), s.fs.Config, s.fs.hookOutput(), old, t, updateEvents(old, t)...)
+	return nil
+}
+
+func (s *GitStore) NextID() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.sync(); err != nil {
+		return "", err
+	}
+	next, err := s.fs.NextID()
+	if err != nil {
+		return "", err
+	}
+	// Push a new block reservation right away; the sync before the next write
+	// would otherwise discard it.
+	if len(s.fs.reserved) > 0 {
+		s.fs.reserved = nil
+		if err := s.commitAndPush("reserve IDs"); err != nil {
+			return "", err
+		}
+	}
+	return next, nil
+}
+
+func (s *GitStore) GetConfig() *config.Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.GetConfig()
+}
+
+func (s *GitStore) UpdateConfig(fn func(*config.Config) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.sync(); err != nil {
+		return err
+	}
+	if err := s.fs.UpdateConfig(fn); err != nil {
+		return err
+	}
+	return s.commitAndPush("update config")
+}
+
+func (s *GitStore) LoadTemplate(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.LoadTemplate(name)
+}
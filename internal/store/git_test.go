@@ -0,0 +1,364 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// setupBareRemote creates a bare repository seeded with an initialized
+// .skeeter directory and returns its file:// URL.
+func setupBareRemote(t *testing.T) string {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@test.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@test.com")
+
+	root := t.TempDir()
+	bare := filepath.Join(root, "remote.git")
+	runGit(t, root, "init", "--bare", "-b", "main", bare)
+
+	work := filepath.Join(root, "work")
+	runGit(t, root, "clone", bare, work)
+	fs := &FilesystemStore{Dir: filepath.Join(work, ".skeeter")}
+	if err := fs.Init("remote-project"); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	runGit(t, work, "add", "-A")
+	runGit(t, work, "commit", "-m", "initial")
+	runGit(t, work, "push", "origin", "HEAD:main")
+
+	return "file://" + bare
+}
+
+func readRemoteTask(t *testing.T, url, id string) *task.Task {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "check")
+	runGit(t, t.TempDir(), "clone", url, dir)
+	content := runGit(t, dir, "show", "HEAD:.skeeter/tasks/"+id+".md")
+	tk, err := task.Parse(content)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return tk
+}
+
+func TestIsGitURL(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   bool
+	}{
+		{"owner/repo", false},
+		{"git@gitlab.example:team/repo.git", true},
+		{"https://gitea.example/team/repo", true},
+		{"file:///srv/repos/x.git", true},
+		{"/srv/repos/x", true},
+		{"team/repo.git", true},
+	}
+	for _, tt := range tests {
+		if got := IsGitURL(tt.remote); got != tt.want {
+			t.Errorf("IsGitURL(%q) = %v, want %v", tt.remote, got, tt.want)
+		}
+	}
+}
+
+func TestGitStoreCreateAndList(t *testing.T) {
+	url := setupBareRemote(t)
+
+	s, err := newGitStore(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitStore: %v", err)
+	}
+	if s.GetConfig().Project.Name != "remote-project" {
+		t.Errorf("project name = %q, want %q", s.GetConfig().Project.Name, "remote-project")
+	}
+
+	id, err := s.NextID()
+	if err != nil {
+		t.Fatalf("NextID: %v", err)
+	}
+	if id != "US-001" {
+		t.Errorf("NextID = %q, want US-001", id)
+	}
+
+	tk := &task.Task{ID: id, Title: "Remote task", Status: "backlog", Created: "2026-01-01", Updated: "2026-01-01"}
+	if err := s.Create(tk); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tasks, err := s.List(Filter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("List returned %d tasks, want 1", len(tasks))
+	}
+
+	pushed := readRemoteTask(t, url, "US-001")
+	if pushed.Title != "Remote task" {
+		t.Errorf("pushed title = %q, want %q", pushed.Title, "Remote task")
+	}
+}
+
+func TestGitStoreRebasesConcurrentWrites(t *testing.T) {
+	url := setupBareRemote(t)
+
+	a, err := newGitStore(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitStore a: %v", err)
+	}
+	b, err := newGitStore(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitStore b: %v", err)
+	}
+
+	if err := a.Create(&task.Task{ID: "US-001", Title: "From A", Status: "backlog"}); err != nil {
+		t.Fatalf("Create a: %v", err)
+	}
+
+	// b's clone is stale; commit directly so the push has to rebase.
+	if err := b.fs.Create(&task.Task{ID: "US-002", Title: "From B", Status: "backlog"}); err != nil {
+		t.Fatalf("fs Create b: %v", err)
+	}
+	if err := b.commitAndPush("create US-002: From B"); err != nil {
+		t.Fatalf("commitAndPush b: %v", err)
+	}
+
+	if got := readRemoteTask(t, url, "US-001"); got.Title != "From A" {
+		t.Errorf("US-001 title = %q, want %q", got.Title, "From A")
+	}
+	if got := readRemoteTask(t, url, "US-002"); got.Title != "From B" {
+		t.Errorf("US-002 title = %q, want %q", got.Title, "From B")
+	}
+
+	if err := a.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	tasks, _ := a.List(Filter{})
+	if len(tasks) != 2 {
+		t.Errorf("after Refresh List returned %d tasks, want 2", len(tasks))
+	}
+}
+
+func TestGitStorePushesAfterLastRebase(t *testing.T) {
+	url := setupBareRemote(t)
+	// Reject as many pushes as there are retries, so only the push after
+	// the final rebase gets through.
+	hook := fmt.Sprintf(`#!/bin/sh
+n=$(cat rejected 2>/dev/null || echo 0)
+if [ "$n" -lt %d ]; then
+	echo $((n + 1)) > rejected
+	echo "busy, try again" >&2
+	exit 1
+fi
+`, pushAttempts)
+	bare := strings.TrimPrefix(url, "file://")
+	if err := os.WriteFile(filepath.Join(bare, "hooks", "pre-receive"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := newGitStore(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitStore: %v", err)
+	}
+	if err := s.Create(&task.Task{ID: "US-001", Title: "Persistent", Status: "backlog"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := readRemoteTask(t, url, "US-001"); got.Title != "Persistent" {
+		t.Errorf("US-001 title = %q, want %q", got.Title, "Persistent")
+	}
+}
+
+func TestGitStoreUpdate(t *testing.T) {
+	url := setupBareRemote(t)
+
+	s, err := newGitStore(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitStore: %v", err)
+	}
+	if err := s.Create(&task.Task{ID: "US-001", Title: "Task", Status: "backlog"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tk, err := s.Get("US-001")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	tk.Status = "done"
+	if err := s.Update(tk); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	if got := readRemoteTask(t, url, "US-001"); got.Status != "done" {
+		t.Errorf("pushed status = %q, want done", got.Status)
+	}
+}
+
+func TestGitStoreMissingConfig(t *testing.T) {
+	root := t.TempDir()
+	bare := filepath.Join(root, "empty.git")
+	runGit(t, root, "init", "--bare", "-b", "main", bare)
+
+	if _, err := newGitStore("file://"+bare, "", t.TempDir()); err == nil {
+		t.Error("expected error for repository without skeeter config")
+	}
+}
+
+func TestGitStoreInit(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@test.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@test.com")
+
+	root := t.TempDir()
+	bare := filepath.Join(root, "empty.git")
+	runGit(t, root, "init", "--bare", "-b", "main", bare)
+	url := "file://" + bare
+
+	s, err := newGitClone(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitClone: %v", err)
+	}
+	if err := s.Init("fresh"); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := s.Init("fresh"); err == nil {
+		t.Error("expected error initializing twice")
+	}
+
+	opened, err := newGitStore(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitStore after init: %v", err)
+	}
+	if opened.GetConfig().Project.Name != "fresh" {
+		t.Errorf("project name = %q, want fresh", opened.GetConfig().Project.Name)
+	}
+}
+
+func TestGitStoreUpdateConfig(t *testing.T) {
+	url := setupBareRemote(t)
+
+	s, err := newGitStore(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitStore: %v", err)
+	}
+	err = s.UpdateConfig(func(cfg *config.Config) error {
+		cfg.Project.Prefix = "TK"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+
+	other, err := newGitStore(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitStore: %v", err)
+	}
+	if other.GetConfig().Project.Prefix != "TK" {
+		t.Errorf("pushed prefix = %q, want TK", other.GetConfig().Project.Prefix)
+	}
+}
+
+// TestGitStoreFingerprintDuringWrites polls the remote while the same store
+// writes to it; both fetch into the clone, so they must take turns.
+func TestGitStoreFingerprintDuringWrites(t *testing.T) {
+	url := setupBareRemote(t)
+
+	s, err := newGitStore(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitStore: %v", err)
+	}
+
+	done := make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if _, err := s.Fingerprint(); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+	for i := 1; i <= 5; i++ {
+		id := fmt.Sprintf("US-%03d", i)
+		if err := s.Create(&task.Task{ID: id, Title: "Task " + id, Status: "backlog"}); err != nil {
+			t.Errorf("Create %s: %v", id, err)
+		}
+	}
+	close(done)
+	if err := <-errs; err != nil {
+		t.Errorf("Fingerprint: %v", err)
+	}
+
+	prints, err := s.Fingerprint()
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	if len(prints) != 5 {
+		t.Errorf("Fingerprint = %v, want 5 tasks", prints)
+	}
+}
+
+func TestGitStoreFingerprint(t *testing.T) {
+	url := setupBareRemote(t)
+
+	a, err := newGitStore(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitStore a: %v", err)
+	}
+	b, err := newGitStore(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitStore b: %v", err)
+	}
+	if err := a.Create(&task.Task{ID: "US-001", Title: "First", Status: "backlog"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	before, err := b.Fingerprint()
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	if len(before) != 1 || before["US-001"] == "" {
+		t.Fatalf("Fingerprint = %v, want US-001", before)
+	}
+
+	tk, _ := a.Get("US-001")
+	tk.Title = "Renamed"
+	if err := a.Update(tk); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	after, err := b.Fingerprint()
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	if after["US-001"] == before["US-001"] {
+		t.Error("fingerprint did not change with the task")
+	}
+	if got, _ := b.Get("US-001"); got.Title != "Renamed" {
+		t.Errorf("clone not brought up to date: title %q", got.Title)
+	}
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,22 +11,30 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/andybarilla/skeeter/internal/config"
 	"github.com/andybarilla/skeeter/internal/id"
 	"github.com/andybarilla/skeeter/internal/task"
+	"github.com/andybarilla/skeeter/internal/webhook"
 	"gopkg.in/yaml.v3"
 )
 
 type GitHubStore struct {
-	owner   string
-	repo    string
-	dir     string
-	token   string
-	client  *http.Client
-	cfg     *config.Config
-	baseURL string
+	owner    string
+	repo     string
+	dir      string
+	token    string
+	client   *http.Client
+	cfg      *config.Config
+	baseURL  string
+	problems []TaskFile
+
+	// blobs caches task file contents by blob SHA, so Scan only downloads
+	// the files that changed since it last ran.
+	mu    sync.Mutex
+	blobs map[string][]byte
 }
 
 type ghContentsResponse struct {
@@ -37,7 +46,32 @@ type ghContentsResponse struct {
 	Type     string `json:"type"`
 }
 
+var (
+	errFileNotFound = errors.New("file not found")
+	errConflict     = errors.New("remote file changed")
+)
+
+// configAttempts bounds the read-modify-write retries in UpdateConfig.
+const configAttempts = 3
+
 func NewGitHub(remote, dir string) (*GitHubStore, error) {
+	s, err := newGitHubClient(remote, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := s.loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading remote config: %w", err)
+	}
+	s.cfg = cfg
+
+	return s, nil
+}
+
+// newGitHubClient returns a store that can talk to the API but has not
+// loaded the remote config yet.
+func newGitHubClient(remote, dir string) (*GitHubStore, error) {
 	owner, repo, found := strings.Cut(remote, "/")
 	if !found {
 		return nil, fmt.Errorf("invalid remote format %q (expected owner/repo)", remote)
@@ -52,21 +86,13 @@ func NewGitHub(remote, dir string) (*GitHubStore, error) {
 		dir = ".skeeter"
 	}
 
-	s := &GitHubStore{
+	return &GitHubStore{
 		owner:  owner,
 		repo:   repo,
 		dir:    dir,
 		token:  token,
 		client: &http.Client{Timeout: 30 * time.Second},
-	}
-
-	cfg, err := s.loadConfig()
-	if err != nil {
-		return nil, fmt.Errorf("loading remote config: %w", err)
-	}
-	s.cfg = cfg
-
-	return s, nil
+	}, nil
 }
 
 func resolveToken() (string, error) {
@@ -85,12 +111,16 @@ func resolveToken() (string, error) {
 	return "", fmt.Errorf("no GitHub token found (install gh CLI or set GITHUB_TOKEN)")
 }
 
-func (s *GitHubStore) contentsURL(path string) string {
+func (s *GitHubStore) repoURL(path string) string {
 	base := s.baseURL
 	if base == "" {
 		base = "https://api.github.com"
 	}
-	return fmt.Sprintf("%s/repos/%s/%s/contents/%s", base, s.owner, s.repo, path)
+	return fmt.Sprintf("%s/repos/%s/%s%s", base, s.owner, s.repo, path)
+}
+
+func (s *GitHubStore) contentsURL(path string) string {
+	return s.repoURL("/contents/" + path)
 }
 
 func (s *GitHubStore) tasksPath() string {
@@ -122,7 +152,7 @@ func (s *GitHubStore) getFileContent(path string) (content []byte, sha string, e
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 404 {
-		return nil, "", fmt.Errorf("file not found: %s", path)
+		return nil, "", fmt.Errorf("%w: %s", errFileNotFound, path)
 	}
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
@@ -162,6 +192,9 @@ func (s *GitHubStore) putFile(path string, content []byte, sha, message string)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == 409 {
+		return fmt.Errorf("%w: %s", errConflict, path)
+	}
 	if resp.StatusCode != 200 && resp.StatusCode != 201 {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, body)
@@ -170,6 +203,101 @@ func (s *GitHubStore) putFile(path string, content []byte, sha, message string)
 	return nil
 }
 
+// doJSON sends payload (if any) as JSON and decodes a 2xx response into out.
+func (s *GitHubStore) doJSON(method, url string, payload, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = strings.NewReader(string(data))
+	}
+
+	resp, err := s.doRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, data)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// commitFiles writes several files to the default branch as a single commit
+// using the git data API. The ref update is not forced, so it fails rather
+// than overwriting commits pushed in the meantime.
+func (s *GitHubStore) commitFiles(files map[string][]byte, message string) error {
+	var repoInfo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := s.doJSON("GET", s.repoURL(""), nil, &repoInfo); err != nil {
+		return fmt.Errorf("reading repository: %w", err)
+	}
+	refPath := "/git/refs/heads/" + repoInfo.DefaultBranch
+
+	var ref struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := s.doJSON("GET", s.repoURL("/git/ref/heads/"+repoInfo.DefaultBranch), nil, &ref); err != nil {
+		return fmt.Errorf("reading branch %s (the repository needs at least one commit): %w", repoInfo.DefaultBranch, err)
+	}
+
+	var parent struct {
+		Tree struct {
+			SHA string `json:"sha"`
+		} `json:"tree"`
+	}
+	if err := s.doJSON("GET", s.repoURL("/git/commits/"+ref.Object.SHA), nil, &parent); err != nil {
+		return fmt.Errorf("reading commit: %w", err)
+	}
+
+	type treeEntry struct {
+		Path    string `json:"path"`
+		Mode    string `json:"mode"`
+		Type    string `json:"type"`
+		Content string `json:"content"`
+	}
+	var entries []treeEntry
+	for path, content := range files {
+		entries = append(entries, treeEntry{Path: path, Mode: "100644", Type: "blob", Content: string(content)})
+	}
+
+	var tree struct {
+		SHA string `json:"sha"`
+	}
+	treeReq := map[string]any{"base_tree": parent.Tree.SHA, "tree": entries}
+	if err := s.doJSON("POST", s.repoURL("/git/trees"), treeReq, &tree); err != nil {
+		return fmt.Errorf("creating tree: %w", err)
+	}
+
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	commitReq := map[string]any{
+		"message": "skeeter: " + message,
+		"tree":    tree.SHA,
+		"parents": []string{ref.Object.SHA},
+	}
+	if err := s.doJSON("POST", s.repoURL("/git/commits"), commitReq, &commit); err != nil {
+		return fmt.Errorf("creating commit: %w", err)
+	}
+
+	refReq := map[string]any{"sha": commit.SHA, "force": false}
+	if err := s.doJSON("PATCH", s.repoURL(refPath), refReq, nil); err != nil {
+		return fmt.Errorf("updating branch %s: %w", repoInfo.DefaultBranch, err)
+	}
+	return nil
+}
+
 func (s *GitHubStore) listDir(path string) ([]ghContentsResponse, error) {
 	resp, err := s.doRequest("GET", s.contentsURL(path), nil)
 	if err != nil {
@@ -211,41 +339,256 @@ func (s *GitHubStore) GetConfig() *config.Config {
 }
 
 func (s *GitHubStore) Init(projectName string) error {
-	return fmt.Errorf("init is not supported for remote repositories — initialize locally and push")
+	configPath := s.dir + "/config.yaml"
+	if _, _, err := s.getFileContent(configPath); err == nil {
+		return fmt.Errorf("skeeter already initialized in %s/%s", s.owner, s.repo)
+	} else if !errors.Is(err, errFileNotFound) {
+		return err
+	}
+
+	cfg := config.Default()
+	cfg.Project.Name = projectName
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		configPath:                      data,
+		s.dir + "/SKEETER.md":           []byte(skeeterMDContent(cfg)),
+		s.dir + "/templates/default.md": []byte(defaultTemplate),
+	}
+	if err := s.commitFiles(files, "init "+projectName); err != nil {
+		return err
+	}
+
+	s.cfg = cfg
+	return nil
+}
+
+// UpdateConfig reads config.yaml, applies fn and writes it back conditioned
+// on the SHA that was read, retrying if someone else changed it first.
+// SKEETER.md is then regenerated to match.
+func (s *GitHubStore) UpdateConfig(fn func(*config.Config) error) error {
+	configPath := s.dir + "/config.yaml"
+
+	var cfg *config.Config
+	for attempt := 0; ; attempt++ {
+		data, sha, err := s.getFileContent(configPath)
+		if err != nil {
+			return err
+		}
+
+		cfg = config.Default()
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return err
+		}
+		if err := fn(cfg); err != nil {
+			return err
+		}
+
+		updated, err := yaml.Marshal(cfg)
+		if err != nil {
+			return err
+		}
+
+		err = s.putFile(configPath, updated, sha, "update config")
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errConflict) || attempt+1 >= configAttempts {
+			return err
+		}
+	}
+	s.cfg = cfg
+
+	mdPath := s.dir + "/SKEETER.md"
+	_, mdSHA, err := s.getFileContent(mdPath)
+	if err != nil && !errors.Is(err, errFileNotFound) {
+		return err
+	}
+	return s.putFile(mdPath, []byte(skeeterMDContent(cfg)), mdSHA, "regenerate SKEETER.md")
 }
 
 func (s *GitHubStore) List(filter Filter) ([]task.Task, error) {
+	files, err := s.Scan()
+	if err != nil {
+		return nil, err
+	}
+	tasks, problems := tasksFromFiles(files, filter)
+	s.problems = problems
+	return tasks, nil
+}
+
+// Scan fetches and parses every task file, including malformed ones.
+func (s *GitHubStore) Scan() ([]TaskFile, error) {
 	entries, err := s.listDir(s.tasksPath())
 	if err != nil {
 		return nil, err
 	}
 
-	var tasks []task.Task
+	var files []TaskFile
 	for _, e := range entries {
 		if e.Type != "file" || filepath.Ext(e.Name) != ".md" {
 			continue
 		}
 
-		data, _, err := s.getFileContent(e.Path)
+		f := TaskFile{Name: e.Name}
+		data, err := s.blob(e)
 		if err != nil {
-			continue
+			f.Err = err
+		} else {
+			f.Task, f.Err = task.Parse(string(data))
 		}
+		files = append(files, f)
+	}
 
-		t, err := task.Parse(string(data))
-		if err != nil {
-			continue
+	return files, nil
+}
+
+// blob returns the contents of the listed file, from the cache when the
+// file hasn't changed.
+func (s *GitHubStore) blob(e ghContentsResponse) ([]byte, error) {
+	s.mu.Lock()
+	data, ok := s.blobs[e.SHA]
+	s.mu.Unlock()
+	if ok && e.SHA != "" {
+		return data, nil
+	}
+
+	data, sha, err := s.getFileContent(e.Path)
+	if err != nil {
+		return nil, err
+	}
+	if sha != "" {
+		s.mu.Lock()
+		if s.blobs == nil {
+			s.blobs = make(map[string][]byte)
 		}
+		s.blobs[sha] = data
+		s.mu.Unlock()
+	}
+	return data, nil
+}
 
-		if !matchesFilter(t, filter) {
-			continue
+// Fingerprint returns the blob SHA of every task file, from one directory
+// listing.
+func (s *GitHubStore) Fingerprint() (map[string]string, error) {
+	entries, err := s.listDir(s.tasksPath())
+	if err != nil {
+		return nil, err
+	}
+	prints := make(map[string]string)
+	for _, e := range entries {
+		if e.Type == "file" && filepath.Ext(e.Name) == ".md" {
+			prints[strings.TrimSuffix(e.Name, ".md")] = e.SHA
 		}
+	}
+	return prints, nil
+}
+
+// graphQuery fetches a directory's entries with the text of each blob.
+const graphQuery = `query($owner: String!, $repo: String!, $expr: String!) {
+  repository(owner: $owner, name: $repo) {
+    object(expression: $expr) {
+      ... on Tree {
+        entries {
+          name
+          type
+          object {
+            ... on Blob { oid text isTruncated }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// graphURL is the GraphQL endpoint that goes with baseURL.
+func (s *GitHubStore) graphURL() string {
+	base := s.baseURL
+	if base == "" {
+		base = "https://api.github.com"
+	}
+	return base + "/graphql"
+}
 
-		tasks = append(tasks, *t)
+// LoadGraph fetches every task on the default branch in one GraphQL
+// request, for checks that need the whole dependency graph. Files GitHub
+// truncates are fetched on their own; files that fail to parse are left out.
+func (s *GitHubStore) LoadGraph() ([]task.Task, error) {
+	req := map[string]any{
+		"query": graphQuery,
+		"variables": map[string]string{
+			"owner": s.owner,
+			"repo":  s.repo,
+			"expr":  "HEAD:" + s.tasksPath(),
+		},
+	}
+	var resp struct {
+		Data struct {
+			Repository struct {
+				Object *struct {
+					Entries []struct {
+						Name   string `json:"name"`
+						Type   string `json:"type"`
+						Object struct {
+							OID         string  `json:"oid"`
+							Text        *string `json:"text"`
+							IsTruncated bool    `json:"isTruncated"`
+						} `json:"object"`
+					} `json:"entries"`
+				} `json:"object"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := s.doJSON("POST", s.graphURL(), req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("GitHub GraphQL error: %s", resp.Errors[0].Message)
+	}
+	if resp.Data.Repository.Object == nil {
+		return nil, nil
 	}
 
+	var tasks []task.Task
+	for _, e := range resp.Data.Repository.Object.Entries {
+		if e.Type != "blob" || filepath.Ext(e.Name) != ".md" {
+			continue
+		}
+		var data []byte
+		if e.Object.Text != nil && !e.Object.IsTruncated {
+			data = []byte(*e.Object.Text)
+			s.mu.Lock()
+			if s.blobs == nil {
+				s.blobs = make(map[string][]byte)
+			}
+			s.blobs[e.Object.OID] = data
+			s.mu.Unlock()
+		} else {
+			var err error
+			data, err = s.blob(ghContentsResponse{Name: e.Name, Path: s.tasksPath() + "/" + e.Name, SHA: e.Object.OID})
+			if err != nil {
+				return nil, err
+			}
+		}
+		if t, err := task.Parse(string(data)); err == nil {
+			tasks = append(tasks, *t)
+		}
+	}
+	sortByID(tasks)
 	return tasks, nil
 }
 
+// Problems returns the files the most recent List skipped.
+func (s *GitHubStore) Problems() []TaskFile {
+	return s.problems
+}
+
 func (s *GitHubStore) Get(taskID string) (*task.Task, error) {
 	data, _, err := s.getFileContent(s.taskFilePath(taskID))
 	if err != nil {
@@ -255,39 +598,58 @@ func (s *GitHubStore) Get(taskID string) (*task.Task, error) {
 }
 
 func (s *GitHubStore) Create(t *task.Task) error {
+	if err := applyCreateStatus(s, t); err != nil {
+		return err
+	}
 	content, err := task.Marshal(t)
 	if err != nil {
 		return err
 	}
 
-	return s.putFile(
+	err = s.putFile(
 		s.taskFilePath(t.ID),
 		[]byte(content),
 		"",
 		fmt.Sprintf("create %s: %s", t.ID, t.Title),
 	)
+	if err != nil {
+		return err
+	}
+	sendEvents(s.Outbox(), s.cfg, os.Stderr, nil, t, webhook.EventCreate)
+	return nil
 }
 
 func (s *GitHubStore) Update(t *task.Task) error {
 	t.Updated = time.Now().Format("2006-01-02")
 
 	// Fetch current SHA for conflict detection
-	_, sha, err := s.getFileContent(s.taskFilePath(t.ID))
+	current, sha, err := s.getFileContent(s.taskFilePath(t.ID))
 	if err != nil {
 		return fmt.Errorf("fetching current version of %s: %w", t.ID, err)
 	}
+	old, _ := task.Parse(string(current))
+	if old != nil {
+		if err := ApplyStatusChange(s, t, old, false); err != nil {
+			return err
+		}
+	}
 
 	content, err := task.Marshal(t)
 	if err != nil {
 		return err
 	}
 
-	return s.putFile(
+	err = s.putFile(
 		s.taskFilePath(t.ID),
 		[]byte(content),
 		sha,
 		fmt.Sprintf("update %s: %s", t.ID, t.Title),
 	)
+	if err != nil {
+		return err
+	}
+	sendEvents(s.Outbox(), s.cfg, os.Stderr, old, t, updateEvents(old, t)...)
+	return nil
 }
 
 func (s *GitHubStore) NextID() (string, error) {
@@ -302,7 +664,14 @@ func (s *GitHubStore) NextID() (string, error) {
 		names = append(names, e.Name)
 	}
 
-	return id.NextFromNames(names, s.cfg.Project.Prefix)
+	switch s.cfg.IDs.Strategy {
+	case id.Hash:
+		return id.HashFromNames(names, s.cfg.Project.Prefix)
+	case id.Block:
+		return "", fmt.Errorf("the block ID strategy is not supported with the GitHub API backend (use a git URL remote)")
+	default:
+		return id.NextFromNames(names, s.cfg.Project.Prefix)
+	}
 }
 
 func (s *GitHubStore) LoadTemplate(name string) (string, error) {
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/andybarilla/skeeter/internal/config"
@@ -137,6 +139,93 @@ func TestGitHubStoreList(t *testing.T) {
 	}
 }
 
+func TestGitHubStoreFingerprintAndBlobCache(t *testing.T) {
+	var fetches atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/contents/.skeeter/tasks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]ghContentsResponse{
+			{Name: "US-001.md", Path: ".skeeter/tasks/US-001.md", Type: "file", SHA: "sha1"},
+		})
+	})
+	mux.HandleFunc("/repos/owner/repo/contents/.skeeter/tasks/US-001.md", func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		content := "---\nid: US-001\ntitle: Cached\nstatus: backlog\npriority: high\n---\n"
+		json.NewEncoder(w).Encode(ghContentsResponse{
+			Name:     "US-001.md",
+			Content:  base64.StdEncoding.EncodeToString([]byte(content)),
+			Encoding: "base64",
+			Type:     "file",
+			SHA:      "sha1",
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := &GitHubStore{owner: "owner", repo: "repo", dir: ".skeeter", token: "fake-token", client: server.Client(), baseURL: server.URL}
+	s.cfg = defaultConfigForTest()
+
+	prints, err := s.Fingerprint()
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	if prints["US-001"] != "sha1" || fetches.Load() != 0 {
+		t.Errorf("Fingerprint = %v after %d file fetches, want the listing's SHA and none", prints, fetches.Load())
+	}
+
+	for range 2 {
+		tasks, err := s.List(Filter{})
+		if err != nil || len(tasks) != 1 || tasks[0].Title != "Cached" {
+			t.Fatalf("List = %v, %v", tasks, err)
+		}
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("unchanged file fetched %d times, want 1", fetches.Load())
+	}
+}
+
+func TestGitHubStoreDetectCycleInOneRequest(t *testing.T) {
+	var requests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var req struct {
+			Variables map[string]string `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Variables["expr"] != "HEAD:.skeeter/tasks" {
+			t.Errorf("expression = %q", req.Variables["expr"])
+		}
+		entry := func(name, text string) map[string]any {
+			return map[string]any{"name": name, "type": "blob", "object": map[string]any{"oid": name, "text": text}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"repository": map[string]any{"object": map[string]any{"entries": []any{
+			entry("US-001.md", "---\nid: US-001\ntitle: First\nstatus: backlog\ndepends_on: [US-002]\n---\n"),
+			entry("US-002.md", "---\nid: US-002\ntitle: Second\nstatus: backlog\n---\n"),
+		}}}}})
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := &GitHubStore{owner: "owner", repo: "repo", dir: ".skeeter", token: "fake-token", client: server.Client(), baseURL: server.URL}
+	s.cfg = defaultConfigForTest()
+
+	cycle, err := DetectCircularDependency(&task.Task{ID: "US-002", DependsOn: task.FlowSlice{"US-001"}}, s)
+	if err != nil {
+		t.Fatalf("DetectCircularDependency: %v", err)
+	}
+	if len(cycle) != 2 || cycle[0] != "US-002" || cycle[1] != "US-001" {
+		t.Errorf("cycle = %v, want [US-002 US-001]", cycle)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("made %d requests, want 1", requests.Load())
+	}
+}
+
 func TestGitHubStoreListWithFilter(t *testing.T) {
 	server := setupGitHubServer()
 	defer server.Close()
@@ -288,12 +377,174 @@ func TestGitHubStoreLoadTemplate(t *testing.T) {
 	}
 }
 
-func TestGitHubStoreInit(t *testing.T) {
-	store := &GitHubStore{}
+func TestGitHubStoreInitAlreadyInitialized(t *testing.T) {
+	server := setupGitHubServer()
+	defer server.Close()
+
+	store := &GitHubStore{
+		owner:   "owner",
+		repo:    "repo",
+		dir:     ".skeeter",
+		token:   "fake-token",
+		client:  server.Client(),
+		baseURL: server.URL,
+	}
 
 	err := store.Init("test")
-	if err == nil {
-		t.Error("expected error for Init on remote store")
+	if err == nil || !strings.Contains(err.Error(), "already initialized") {
+		t.Errorf("Init error = %v, want 'already initialized'", err)
+	}
+}
+
+func TestGitHubStoreInit(t *testing.T) {
+	var tree struct {
+		BaseTree string `json:"base_tree"`
+		Tree     []struct {
+			Path    string `json:"path"`
+			Content string `json:"content"`
+		} `json:"tree"`
+	}
+	var refUpdate map[string]any
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/contents/.skeeter/config.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/repos/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"default_branch": "main"})
+	})
+	mux.HandleFunc("/repos/owner/repo/git/ref/heads/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"object": map[string]string{"sha": "parent-sha"}})
+	})
+	mux.HandleFunc("/repos/owner/repo/git/commits/parent-sha", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"tree": map[string]string{"sha": "base-tree"}})
+	})
+	mux.HandleFunc("/repos/owner/repo/git/trees", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&tree)
+		json.NewEncoder(w).Encode(map[string]string{"sha": "new-tree"})
+	})
+	mux.HandleFunc("/repos/owner/repo/git/commits", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"sha": "new-commit"})
+	})
+	mux.HandleFunc("/repos/owner/repo/git/refs/heads/main", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("ref update method = %s, want PATCH", r.Method)
+		}
+		json.NewDecoder(r.Body).Decode(&refUpdate)
+		json.NewEncoder(w).Encode(map[string]string{})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	store := &GitHubStore{
+		owner:   "owner",
+		repo:    "repo",
+		dir:     ".skeeter",
+		token:   "fake-token",
+		client:  server.Client(),
+		baseURL: server.URL,
+	}
+
+	if err := store.Init("remote-project"); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	if tree.BaseTree != "base-tree" {
+		t.Errorf("base_tree = %q, want base-tree", tree.BaseTree)
+	}
+	paths := map[string]string{}
+	for _, e := range tree.Tree {
+		paths[e.Path] = e.Content
+	}
+	for _, p := range []string{".skeeter/config.yaml", ".skeeter/SKEETER.md", ".skeeter/templates/default.md"} {
+		if _, ok := paths[p]; !ok {
+			t.Errorf("commit missing %s", p)
+		}
+	}
+	if !strings.Contains(paths[".skeeter/config.yaml"], "name: remote-project") {
+		t.Errorf("config.yaml missing project name:\n%s", paths[".skeeter/config.yaml"])
+	}
+	if refUpdate["sha"] != "new-commit" || refUpdate["force"] != false {
+		t.Errorf("ref update = %v, want unforced update to new-commit", refUpdate)
+	}
+	if store.GetConfig().Project.Name != "remote-project" {
+		t.Errorf("Project.Name = %q, want remote-project", store.GetConfig().Project.Name)
+	}
+}
+
+func TestGitHubStoreUpdateConfigRetriesOnConflict(t *testing.T) {
+	configYAML := "project:\n  name: test-project\n  prefix: US\n"
+	puts := 0
+	var written string
+	var mdWritten string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/contents/.skeeter/config.yaml", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(ghContentsResponse{
+				Content: base64.StdEncoding.EncodeToString([]byte(configYAML)),
+				SHA:     "cfg-sha",
+			})
+		case "PUT":
+			puts++
+			if puts == 1 {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			var payload map[string]string
+			json.NewDecoder(r.Body).Decode(&payload)
+			if payload["sha"] != "cfg-sha" {
+				t.Errorf("PUT sha = %q, want cfg-sha", payload["sha"])
+			}
+			data, _ := base64.StdEncoding.DecodeString(payload["content"])
+			written = string(data)
+		}
+	})
+	mux.HandleFunc("/repos/owner/repo/contents/.skeeter/SKEETER.md", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.WriteHeader(http.StatusNotFound)
+		case "PUT":
+			var payload map[string]string
+			json.NewDecoder(r.Body).Decode(&payload)
+			data, _ := base64.StdEncoding.DecodeString(payload["content"])
+			mdWritten = string(data)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	store := &GitHubStore{
+		owner:   "owner",
+		repo:    "repo",
+		dir:     ".skeeter",
+		token:   "fake-token",
+		client:  server.Client(),
+		baseURL: server.URL,
+		cfg:     defaultConfigForTest(),
+	}
+
+	err := store.UpdateConfig(func(cfg *config.Config) error {
+		cfg.Project.Prefix = "TK"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+
+	if puts != 2 {
+		t.Errorf("PUT count = %d, want 2 (one conflict, one retry)", puts)
+	}
+	if !strings.Contains(written, "prefix: TK") {
+		t.Errorf("written config missing new prefix:\n%s", written)
+	}
+	if !strings.Contains(mdWritten, "TK-001") {
+		t.Error("SKEETER.md not regenerated with new prefix")
+	}
+	if store.GetConfig().Project.Prefix != "TK" {
+		t.Errorf("cached prefix = %q, want TK", store.GetConfig().Project.Prefix)
 	}
 }
 
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// The lifecycle hook events. Each names an executable in .skeeter/hooks.
+const (
+	HookPreCreate      = "pre-create"
+	HookPostCreate     = "post-create"
+	HookPreUpdate      = "pre-update"
+	HookPostUpdate     = "post-update"
+	HookOnStatusChange = "on-status-change"
+)
+
+// HookEvents lists the lifecycle hook events in the order they can fire.
+var HookEvents = []string{HookPreCreate, HookPostCreate, HookPreUpdate, HookPostUpdate, HookOnStatusChange}
+
+// hookTimeout bounds how long a single hook may run.
+const hookTimeout = time.Minute
+
+// HookPayload is the JSON a hook reads on stdin. Old is null for creates.
+type HookPayload struct {
+	Event string     `json:"event"`
+	Old   *task.Task `json:"old"`
+	New   *task.Task `json:"new"`
+}
+
+// HookError reports a pre-hook that exited nonzero and so refused a write.
+type HookError struct {
+	Event  string
+	ID     string
+	Output string
+	Err    error
+}
+
+func (e *HookError) Error() string {
+	msg := fmt.Sprintf("%s hook rejected %s (%v)", e.Event, e.ID, e.Err)
+	if e.Output != "" {
+		msg += ": " + e.Output
+	}
+	return msg
+}
+
+func (e *HookError) Unwrap() error { return e.Err }
+
+func (s *FilesystemStore) hooksDir() string {
+	return filepath.Join(s.Dir, "hooks")
+}
+
+// hasHook reports whether an executable hook exists for event. Like git,
+// skeeter ignores hook files that aren't executable.
+func (s *FilesystemStore) hasHook(event string) bool {
+	if s.noHooks {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(s.hooksDir(), event))
+	return err == nil && info.Mode().IsRegular() && info.Mode()&0111 != 0
+}
+
+// hookOutput is where hooks' output goes.
+func (s *FilesystemStore) hookOutput() io.Writer {
+	if s.HookOutput != nil {
+		return s.HookOutput
+	}
+	return os.Stderr
+}
+
+// runHook runs the hook for event, if there is one, from the repository
+// root with the payload on stdin. A failing pre-hook returns a *HookError;
+// a failing post-hook only warns, since the write has already happened.
+func (s *FilesystemStore) runHook(event string, old, t *task.Task) error {
+	if !s.hasHook(event) {
+		return nil
+	}
+	payload, err := json.Marshal(HookPayload{Event: event, Old: old, New: t})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, filepath.Join(s.hooksDir(), event))
+	cmd.Dir = filepath.Dir(s.Dir)
+	cmd.Env = append(os.Environ(),
+		"SKEETER_EVENT="+event,
+		"SKEETER_TASK_ID="+t.ID,
+		"SKEETER_DIR="+s.Dir,
+	)
+	cmd.Stdin = bytes.NewReader(payload)
+	out, err := cmd.CombinedOutput()
+
+	if err != nil && strings.HasPrefix(event, "pre-") {
+		return &HookError{Event: event, ID: t.ID, Output: strings.TrimSpace(string(out)), Err: err}
+	}
+	w := s.hookOutput()
+	w.Write(out)
+	if err != nil {
+		fmt.Fprintf(w, "Warning: %s hook failed for %s: %v\n", event, t.ID, err)
+	}
+	return nil
+}
+
+// hooksFor reports whether any of events has a hook, so callers can skip
+// the work of building a payload nobody reads.
+func (s *FilesystemStore) hooksFor(events ...string) bool {
+	for _, event := range events {
+		if s.hasHook(event) {
+			return true
+		}
+	}
+	return false
+}
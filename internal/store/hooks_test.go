@@ -0,0 +1,97 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+func writeHook(t *testing.T, s *FilesystemStore, event, script string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(s.hooksDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.hooksDir(), event), []byte("#!/bin/sh\n"+script), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPreHookAbortsWrite(t *testing.T) {
+	s := setupTestStore(t)
+	writeHook(t, s, HookPreCreate, "echo 'titles need a verb' >&2\nexit 1\n", 0755)
+
+	err := s.Create(&task.Task{ID: "US-001", Title: "Thing", Status: "backlog"})
+	var he *HookError
+	if !errors.As(err, &he) || he.Output != "titles need a verb" {
+		t.Fatalf("Create = %v, want a HookError with the hook's output", err)
+	}
+	if _, err := os.Stat(s.taskPath("US-001")); !os.IsNotExist(err) {
+		t.Error("rejected task was written")
+	}
+}
+
+func TestPostHooksReceiveOldAndNew(t *testing.T) {
+	s := setupTestStore(t)
+	var out strings.Builder
+	s.HookOutput = &out
+	s.Create(&task.Task{ID: "US-001", Title: "Thing", Status: "backlog"})
+	for _, event := range []string{HookPostUpdate, HookOnStatusChange} {
+		writeHook(t, s, event, "cat > \"$SKEETER_DIR/$SKEETER_EVENT.json\"\necho ran $SKEETER_EVENT for $SKEETER_TASK_ID\n", 0755)
+	}
+
+	tk, _ := s.Get("US-001")
+	tk.Status = "in-progress"
+	if err := s.Update(tk); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	for _, event := range []string{HookPostUpdate, HookOnStatusChange} {
+		data, err := os.ReadFile(filepath.Join(s.Dir, event+".json"))
+		if err != nil {
+			t.Fatalf("%s hook did not run: %v", event, err)
+		}
+		var p HookPayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			t.Fatal(err)
+		}
+		if p.Event != event || p.Old == nil || p.Old.Status != "backlog" || p.New.Status != "in-progress" {
+			t.Errorf("%s payload = %+v", event, p)
+		}
+	}
+	if want := "ran post-update for US-001\nran on-status-change for US-001\n"; out.String() != want {
+		t.Errorf("hook output = %q, want %q", out.String(), want)
+	}
+
+	// No status change, no on-status-change hook.
+	os.Remove(filepath.Join(s.Dir, HookOnStatusChange+".json"))
+	tk.Title = "Renamed"
+	s.Update(tk)
+	if _, err := os.Stat(filepath.Join(s.Dir, HookOnStatusChange+".json")); !os.IsNotExist(err) {
+		t.Error("on-status-change ran without a status change")
+	}
+}
+
+func TestHooksIgnoreNonExecutable(t *testing.T) {
+	s := setupTestStore(t)
+	writeHook(t, s, HookPreCreate, "exit 1\n", 0644)
+	if err := s.Create(&task.Task{ID: "US-001", Title: "Thing", Status: "backlog"}); err != nil {
+		t.Fatalf("Create = %v, want the hook ignored", err)
+	}
+}
+
+func TestFailingPostHookOnlyWarns(t *testing.T) {
+	s := setupTestStore(t)
+	var out strings.Builder
+	s.HookOutput = &out
+	writeHook(t, s, HookPostCreate, "exit 3\n", 0755)
+	if err := s.Create(&task.Task{ID: "US-001", Title: "Thing", Status: "backlog"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !strings.Contains(out.String(), "post-create hook failed for US-001") {
+		t.Errorf("hook output = %q, want a warning", out.String())
+	}
+}
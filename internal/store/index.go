@@ -0,0 +1,435 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// indexVersion is bumped whenever the on-disk index format changes, forcing
+// a full rebuild.
+const indexVersion = 2
+
+// taskIndex caches parsed task files keyed by file name. Entries are
+// invalidated by modification time and size, so files edited behind
+// skeeter's back are re-parsed on the next refresh and everything else is
+// served without reading the file.
+//
+// Alongside the entries it keeps two tables derived from them: Terms maps
+// each lowercase word of a title or body to the files containing it, and
+// Backlinks maps a task ID to the tasks naming it, by relation kind. Both
+// are kept in step by put and remove.
+//
+// Reads refresh the index, so every use, reads included, holds mu from the
+// refresh through to the last lookup and the save.
+type taskIndex struct {
+	mu sync.Mutex
+
+	Version   int                            `json:"version"`
+	Entries   map[string]indexEntry          `json:"entries"`
+	Terms     map[string][]string            `json:"terms"`
+	Backlinks map[string]map[string][]string `json:"backlinks"`
+
+	path      string
+	dirty     bool
+	refreshed bool
+}
+
+type indexEntry struct {
+	ModTime int64     `json:"mod_time"`
+	Size    int64     `json:"size"`
+	Task    task.Task `json:"task"`
+}
+
+func (s *FilesystemStore) cacheDir() string {
+	return filepath.Join(s.Dir, ".cache")
+}
+
+// loadIndex returns the in-memory index, reading it from disk on first use.
+// A missing or unreadable index file yields an empty index.
+func (s *FilesystemStore) loadIndex() *taskIndex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index == nil {
+		s.index = readIndex(filepath.Join(s.cacheDir(), "index.json"))
+	}
+	return s.index
+}
+
+func readIndex(path string) *taskIndex {
+	idx := &taskIndex{
+		Version: indexVersion,
+		path:    path,
+	}
+	idx.reset()
+	if data, err := os.ReadFile(idx.path); err == nil {
+		var onDisk taskIndex
+		if json.Unmarshal(data, &onDisk) == nil && onDisk.Version == indexVersion && onDisk.Entries != nil {
+			if onDisk.Terms != nil && onDisk.Backlinks != nil {
+				idx.Entries, idx.Terms, idx.Backlinks = onDisk.Entries, onDisk.Terms, onDisk.Backlinks
+			} else {
+				for name, e := range onDisk.Entries {
+					idx.put(name, e)
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// reset empties the index.
+func (idx *taskIndex) reset() {
+	idx.Entries = make(map[string]indexEntry)
+	idx.Terms = make(map[string][]string)
+	idx.Backlinks = make(map[string]map[string][]string)
+}
+
+// put stores e under name, replacing whatever the file held before in the
+// entries and in both tables.
+func (idx *taskIndex) put(name string, e indexEntry) {
+	idx.remove(name)
+	idx.Entries[name] = e
+	for _, term := range terms(&e.Task) {
+		idx.Terms[term] = insertSorted(idx.Terms[term], name)
+	}
+	for _, kind := range task.Relations {
+		for _, target := range *e.Task.Relation(kind) {
+			if idx.Backlinks[target] == nil {
+				idx.Backlinks[target] = make(map[string][]string)
+			}
+			idx.Backlinks[target][kind] = insertSorted(idx.Backlinks[target][kind], e.Task.ID)
+		}
+	}
+	idx.dirty = true
+}
+
+// remove drops name from the entries and both tables.
+func (idx *taskIndex) remove(name string) {
+	old, ok := idx.Entries[name]
+	if !ok {
+		return
+	}
+	delete(idx.Entries, name)
+	for _, term := range terms(&old.Task) {
+		if names := deleteSorted(idx.Terms[term], name); len(names) > 0 {
+			idx.Terms[term] = names
+		} else {
+			delete(idx.Terms, term)
+		}
+	}
+	for _, kind := range task.Relations {
+		for _, target := range *old.Task.Relation(kind) {
+			links := idx.Backlinks[target]
+			if ids := deleteSorted(links[kind], old.Task.ID); len(ids) > 0 {
+				links[kind] = ids
+			} else {
+				delete(links, kind)
+			}
+			if len(links) == 0 {
+				delete(idx.Backlinks, target)
+			}
+		}
+	}
+	idx.dirty = true
+}
+
+// terms returns the distinct lowercase words in t's title and body.
+func terms(t *task.Task) []string {
+	words := strings.FieldsFunc(strings.ToLower(t.Title+"\n"+t.Body), isTermSeparator)
+	slices.Sort(words)
+	return slices.Compact(words)
+}
+
+func isTermSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func insertSorted(list []string, s string) []string {
+	i, found := slices.BinarySearch(list, s)
+	if found {
+		return list
+	}
+	return slices.Insert(list, i, s)
+}
+
+func deleteSorted(list []string, s string) []string {
+	i, found := slices.BinarySearch(list, s)
+	if !found {
+		return list
+	}
+	return slices.Delete(list, i, i+1)
+}
+
+// candidates returns the files that may contain query: for every word of
+// the query, those holding a term the word is part of. A word split across
+// terms can't occur inside one, so nothing that matches is left out. ok is
+// false when the query has no words to narrow by.
+func (idx *taskIndex) candidates(query string) (names map[string]bool, ok bool) {
+	words := strings.FieldsFunc(strings.ToLower(query), isTermSeparator)
+	if len(words) == 0 {
+		return nil, false
+	}
+	for i, word := range words {
+		found := make(map[string]bool)
+		for term, files := range idx.Terms {
+			if !strings.Contains(term, word) {
+				continue
+			}
+			for _, name := range files {
+				if i == 0 || names[name] {
+					found[name] = true
+				}
+			}
+		}
+		names = found
+		if len(names) == 0 {
+			break
+		}
+	}
+	return names, true
+}
+
+// refresh brings the index in line with the tasks directory, re-parsing only
+// files whose mtime or size changed and dropping files that were removed.
+// Files that fail to parse are returned as problems and never cached.
+func (idx *taskIndex) refresh(tasksDir string) ([]TaskFile, error) {
+	entries, err := os.ReadDir(tasksDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			if len(idx.Entries) > 0 {
+				idx.reset()
+				idx.dirty = true
+			}
+			idx.refreshed = true
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var problems []TaskFile
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".md" {
+			continue
+		}
+		seen[e.Name()] = true
+
+		info, err := e.Info()
+		if err != nil {
+			problems = append(problems, TaskFile{Name: e.Name(), Err: err})
+			continue
+		}
+		if _, err := idx.update(filepath.Join(tasksDir, e.Name()), info); err != nil {
+			problems = append(problems, TaskFile{Name: e.Name(), Err: err})
+		}
+	}
+
+	for name := range idx.Entries {
+		if !seen[name] {
+			idx.remove(name)
+		}
+	}
+	idx.refreshed = true
+	return problems, nil
+}
+
+// update re-parses path if info shows it changed since it was indexed.
+// Unparseable files are dropped from the index.
+func (idx *taskIndex) update(path string, info os.FileInfo) (indexEntry, error) {
+	name := filepath.Base(path)
+	cached, ok := idx.Entries[name]
+	if ok && cached.ModTime == info.ModTime().UnixNano() && cached.Size == info.Size() {
+		return cached, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		var t *task.Task
+		if t, err = task.Parse(string(data)); err == nil {
+			entry := indexEntry{ModTime: info.ModTime().UnixNano(), Size: info.Size(), Task: *t}
+			idx.put(name, entry)
+			return entry, nil
+		}
+	}
+
+	idx.remove(name)
+	return indexEntry{}, err
+}
+
+// save writes the index atomically if it changed. The cache directory
+// carries its own .gitignore so it never shows up in git status.
+func (idx *taskIndex) save() error {
+	if !idx.dirty {
+		return nil
+	}
+
+	dir := filepath.Dir(idx.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		if err := os.WriteFile(ignore, []byte("*\n"), 0644); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "index-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), idx.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	idx.dirty = false
+	return nil
+}
+
+// refreshIndex brings idx up to date and saves it, recording the files
+// that failed to parse. The caller holds idx.mu.
+func (s *FilesystemStore) refreshIndex(idx *taskIndex) error {
+	problems, err := idx.refresh(s.tasksDir())
+	if err != nil {
+		return err
+	}
+	s.setProblems(problems)
+	// The index is only a cache; failing to persist it must not fail a read.
+	_ = idx.save()
+	return nil
+}
+
+// listIndexed serves List from the index.
+func (s *FilesystemStore) listIndexed(filter Filter) ([]task.Task, error) {
+	idx := s.loadIndex()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if err := s.refreshIndex(idx); err != nil {
+		return nil, err
+	}
+
+	var tasks []task.Task
+	for _, e := range idx.Entries {
+		t := e.Task
+		if matchesFilter(&t, filter) {
+			tasks = append(tasks, t)
+		}
+	}
+	sortByID(tasks)
+	return tasks, nil
+}
+
+// indexWritten records a task skeeter itself just wrote, so the next refresh
+// does not depend on the filesystem's mtime granularity to notice it.
+func (s *FilesystemStore) indexWritten(path string, t *task.Task) {
+	if !s.Config.Index {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	idx := s.loadIndex()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.put(filepath.Base(path), indexEntry{ModTime: info.ModTime().UnixNano(), Size: info.Size(), Task: *t})
+}
+
+func sortByID(tasks []task.Task) {
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+}
+
+// getIndexed serves Get from the index, re-parsing only if the file changed.
+func (s *FilesystemStore) getIndexed(taskID string) (*task.Task, bool) {
+	path := s.taskPath(taskID)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	idx := s.loadIndex()
+	idx.mu.Lock()
+	entry, err := idx.update(path, info)
+	_ = idx.save()
+	idx.mu.Unlock()
+	if err != nil {
+		return nil, false
+	}
+	t := entry.Task
+	return &t, true
+}
+
+// Search returns the tasks matching filter whose title, or unless titleOnly
+// whose body, contains query, ignoring case. With the index enabled only the
+// tasks its term table can't rule out are compared.
+func (s *FilesystemStore) Search(query string, titleOnly bool, filter Filter) ([]task.Task, error) {
+	if !s.Config.Index {
+		tasks, err := s.List(filter)
+		if err != nil {
+			return nil, err
+		}
+		return searchTasks(tasks, query, titleOnly), nil
+	}
+
+	idx := s.loadIndex()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if err := s.refreshIndex(idx); err != nil {
+		return nil, err
+	}
+	names, narrowed := idx.candidates(query)
+	var tasks []task.Task
+	for name, e := range idx.Entries {
+		if narrowed && !names[name] {
+			continue
+		}
+		t := e.Task
+		if matchesFilter(&t, filter) && matchesQuery(&t, query, titleOnly) {
+			tasks = append(tasks, t)
+		}
+	}
+	sortByID(tasks)
+	return tasks, nil
+}
+
+// Backlinks answers from the index's backlink table when the index is
+// enabled. The table is only refreshed on first use; after that it is as
+// current as the last List, Search or write, so callers going through a
+// listing task by task don't rescan the directory for each one.
+func (s *FilesystemStore) Backlinks(id string) (map[string][]string, bool) {
+	if !s.Config.Index {
+		return nil, false
+	}
+	idx := s.loadIndex()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if !idx.refreshed {
+		if err := s.refreshIndex(idx); err != nil {
+			return nil, false
+		}
+	}
+	links := make(map[string][]string, len(idx.Backlinks[id]))
+	for kind, ids := range idx.Backlinks[id] {
+		links[kind] = slices.Clone(ids)
+	}
+	return links, true
+}
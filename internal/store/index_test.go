@@ -0,0 +1,256 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+func setupIndexedStore(t *testing.T) *FilesystemStore {
+	t.Helper()
+	s := setupTestStore(t)
+	s.Config.Index = true
+	return s
+}
+
+func TestIndexedListMatchesFiles(t *testing.T) {
+	s := setupIndexedStore(t)
+
+	s.Create(&task.Task{ID: "US-002", Title: "Second", Status: "backlog", Priority: "low"})
+	s.Create(&task.Task{ID: "US-001", Title: "First", Status: "in-progress", Priority: "high"})
+
+	all, err := s.List(Filter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "US-001" || all[1].ID != "US-002" {
+		t.Fatalf("List = %v, want US-001, US-002", all)
+	}
+
+	high, _ := s.List(Filter{Priority: "high"})
+	if len(high) != 1 || high[0].ID != "US-001" {
+		t.Errorf("List(priority=high) = %v, want [US-001]", high)
+	}
+
+	if _, err := os.Stat(filepath.Join(s.Dir, ".cache", "index.json")); err != nil {
+		t.Errorf("index.json not written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir, ".cache", ".gitignore")); err != nil {
+		t.Errorf("cache .gitignore not written: %v", err)
+	}
+}
+
+func TestIndexPicksUpExternalChanges(t *testing.T) {
+	s := setupIndexedStore(t)
+	s.Create(&task.Task{ID: "US-001", Title: "Original", Status: "backlog"})
+	s.Create(&task.Task{ID: "US-002", Title: "Doomed", Status: "backlog"})
+	s.List(Filter{})
+
+	// Edit and delete files behind the store's back, from a fresh process's
+	// point of view.
+	content, _ := task.Marshal(&task.Task{ID: "US-001", Title: "Edited elsewhere", Status: "done"})
+	path := s.taskPath("US-001")
+	os.WriteFile(path, []byte(content), 0644)
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(path, future, future)
+	os.Remove(s.taskPath("US-002"))
+
+	fresh := &FilesystemStore{Dir: s.Dir, Config: s.Config}
+	all, err := fresh.List(Filter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("List returned %d tasks, want 1", len(all))
+	}
+	if all[0].Title != "Edited elsewhere" || all[0].Status != "done" {
+		t.Errorf("task = %q/%q, want edited values", all[0].Title, all[0].Status)
+	}
+
+	got, err := fresh.Get("US-001")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Title != "Edited elsewhere" {
+		t.Errorf("Get title = %q, want %q", got.Title, "Edited elsewhere")
+	}
+}
+
+func TestIndexServesUnchangedFilesFromCache(t *testing.T) {
+	s := setupIndexedStore(t)
+	s.Create(&task.Task{ID: "US-001", Title: "Cached", Status: "backlog"})
+	s.List(Filter{})
+
+	// Rewrite the cached entry; as long as the file is untouched, the index
+	// is trusted over the file contents.
+	idx := s.loadIndex()
+	entry := idx.Entries["US-001.md"]
+	entry.Task.Title = "From index"
+	idx.Entries["US-001.md"] = entry
+
+	all, _ := s.List(Filter{})
+	if len(all) != 1 || all[0].Title != "From index" {
+		t.Errorf("List = %v, want the cached entry", all)
+	}
+}
+
+func TestIndexRecoversFromCorruptFile(t *testing.T) {
+	s := setupIndexedStore(t)
+	s.Create(&task.Task{ID: "US-001", Title: "Task", Status: "backlog"})
+
+	os.MkdirAll(filepath.Join(s.Dir, ".cache"), 0755)
+	os.WriteFile(filepath.Join(s.Dir, ".cache", "index.json"), []byte("{not json"), 0644)
+
+	fresh := &FilesystemStore{Dir: s.Dir, Config: s.Config}
+	all, err := fresh.List(Filter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 1 {
+		t.Errorf("List returned %d tasks, want 1", len(all))
+	}
+}
+
+func TestIndexedSearchUsesTermTable(t *testing.T) {
+	s := setupIndexedStore(t)
+	s.Create(&task.Task{ID: "US-001", Title: "Fix login", Status: "backlog", Body: "The OAuth flow drops the session."})
+	s.Create(&task.Task{ID: "US-002", Title: "Dark mode", Status: "backlog", Body: "Respect the login screen's theme."})
+	s.Create(&task.Task{ID: "US-003", Title: "Export CSV", Status: "backlog"})
+
+	search := func(query string, titleOnly bool) []string {
+		t.Helper()
+		results, err := Search(s, query, titleOnly, Filter{})
+		if err != nil {
+			t.Fatalf("Search(%q): %v", query, err)
+		}
+		var ids []string
+		for _, r := range results {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	if got := search("LOGIN", false); !slices.Equal(got, []string{"US-001", "US-002"}) {
+		t.Errorf("Search(LOGIN) = %v, want US-001, US-002", got)
+	}
+	if got := search("login", true); !slices.Equal(got, []string{"US-001"}) {
+		t.Errorf("Search(login, title only) = %v, want US-001", got)
+	}
+	if got := search("auth flow", false); !slices.Equal(got, []string{"US-001"}) {
+		t.Errorf("Search(auth flow) = %v, want US-001", got)
+	}
+	if got := search("flow login", false); got != nil {
+		t.Errorf("Search(flow login) = %v, want nothing", got)
+	}
+
+	names, ok := s.loadIndex().candidates("csv")
+	if !ok || len(names) != 1 || !names["US-003.md"] {
+		t.Errorf("candidates(csv) = %v, want only US-003.md", names)
+	}
+
+	s.Update(&task.Task{ID: "US-003", Title: "Export JSON", Status: "backlog"})
+	if got := search("csv", false); got != nil {
+		t.Errorf("Search(csv) after update = %v, want nothing", got)
+	}
+	if _, ok := s.loadIndex().Terms["csv"]; ok {
+		t.Error("term table still holds a word the task no longer has")
+	}
+}
+
+func TestIndexBacklinks(t *testing.T) {
+	s := setupIndexedStore(t)
+	s.Create(&task.Task{ID: "US-001", Title: "Base", Status: "backlog"})
+	s.Create(&task.Task{ID: "US-002", Title: "Needs base", Status: "backlog", DependsOn: task.FlowSlice{"US-001"}})
+	s.Create(&task.Task{ID: "US-003", Title: "Also", Status: "backlog", DependsOn: task.FlowSlice{"US-001"}, Duplicates: task.FlowSlice{"US-001"}})
+
+	// No task list is passed in, so Blocking can only come from the index.
+	fresh := &FilesystemStore{Dir: s.Dir, Config: s.Config}
+	base, _ := fresh.Get("US-001")
+	if got := GetDependencyStatus(fresh, base, nil).Blocking; !slices.Equal(got, []string{"US-002", "US-003"}) {
+		t.Errorf("Blocking = %v, want US-002, US-003", got)
+	}
+
+	deps, rel, err := GetLinks(fresh, base)
+	if err != nil {
+		t.Fatalf("GetLinks: %v", err)
+	}
+	if !slices.Equal(deps.Blocking, []string{"US-002", "US-003"}) || !slices.Equal(rel.DuplicatedBy, []string{"US-003"}) {
+		t.Errorf("GetLinks = %+v, %+v", deps, rel)
+	}
+
+	dependent, _ := fresh.Get("US-002")
+	if deps, _, _ := GetLinks(fresh, dependent); !slices.Equal(deps.BlockedBy, []string{"US-001"}) {
+		t.Errorf("BlockedBy = %v, want US-001", deps.BlockedBy)
+	}
+
+	os.Remove(fresh.taskPath("US-003"))
+	fresh.List(Filter{})
+	if got := GetDependencyStatus(fresh, base, nil).Blocking; !slices.Equal(got, []string{"US-002"}) {
+		t.Errorf("Blocking after delete = %v, want US-002", got)
+	}
+}
+
+func TestIndexedGetSavesIndex(t *testing.T) {
+	s := setupIndexedStore(t)
+	s.Create(&task.Task{ID: "US-001", Title: "Original", Status: "backlog"})
+	s.List(Filter{})
+
+	content, _ := task.Marshal(&task.Task{ID: "US-001", Title: "Edited elsewhere", Status: "backlog"})
+	path := s.taskPath("US-001")
+	os.WriteFile(path, []byte(content), 0644)
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(path, future, future)
+
+	fresh := &FilesystemStore{Dir: s.Dir, Config: s.Config}
+	if _, err := fresh.Get("US-001"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.Dir, ".cache", "index.json"))
+	if err != nil {
+		t.Fatalf("reading index: %v", err)
+	}
+	if !strings.Contains(string(data), "Edited elsewhere") {
+		t.Error("Get re-parsed the file but didn't save the index")
+	}
+}
+
+// TestIndexConcurrentUse reads and writes an indexed store from several
+// goroutines at once, as the desktop app does; run it with -race.
+func TestIndexConcurrentUse(t *testing.T) {
+	s := setupIndexedStore(t)
+	for i := 1; i <= 5; i++ {
+		s.Create(&task.Task{ID: fmt.Sprintf("US-%03d", i), Title: "Task", Status: "backlog"})
+	}
+	fresh := &FilesystemStore{Dir: s.Dir, Config: s.Config}
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				if _, err := fresh.List(Filter{}); err != nil {
+					t.Errorf("List: %v", err)
+					return
+				}
+				fresh.Get("US-001")
+				fresh.Search("task", false, Filter{})
+				fresh.Backlinks("US-001")
+				fresh.Problems()
+				if i == 0 {
+					tk, _ := fresh.Get("US-002")
+					fresh.Update(tk)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
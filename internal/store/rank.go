@@ -0,0 +1,129 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/rank"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// SortTasks orders tasks by priority, then by manual rank, then by ID.
+// Ranked tasks come before unranked ones of the same priority.
+func SortTasks(tasks []task.Task, cfg *config.Config) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return lessTask(&tasks[i], &tasks[j], cfg)
+	})
+}
+
+func lessTask(a, b *task.Task, cfg *config.Config) bool {
+	ra, rb := cfg.PriorityRank(a.Priority), cfg.PriorityRank(b.Priority)
+	if ra != rb {
+		return ra < rb
+	}
+	if a.Rank != b.Rank {
+		if a.Rank == "" || b.Rank == "" {
+			return b.Rank == ""
+		}
+		return a.Rank < b.Rank
+	}
+	return a.ID < b.ID
+}
+
+// Reorder moves task id so that it sorts just before beforeID or just after
+// afterID among the tasks sharing its status and priority. Either may be
+// empty; if both are given, beforeID wins unless it belongs to another
+// priority, as happens when a card is dropped at the edge of its group.
+//
+// Only the moved task is rewritten, except the first time an unranked
+// neighbor is involved: the unranked tasks up to it are then ranked in their
+// current order so that there is a place to put the moved task.
+func Reorder(s Store, id, beforeID, afterID string) (*task.Task, error) {
+	if beforeID == "" && afterID == "" {
+		return nil, fmt.Errorf("nothing to rank %s against", id)
+	}
+	if id == beforeID || id == afterID {
+		return nil, fmt.Errorf("cannot rank %s relative to itself", id)
+	}
+
+	all, err := s.List(Filter{})
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(all, func(t task.Task) bool { return t.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("task %s not found", id)
+	}
+	t := all[i]
+
+	var group []task.Task
+	for _, other := range all {
+		if other.ID != t.ID && other.Status == t.Status && other.Priority == t.Priority {
+			group = append(group, other)
+		}
+	}
+	cfg := s.GetConfig()
+	SortTasks(group, cfg)
+
+	indexOf := func(id string) int {
+		return slices.IndexFunc(group, func(t task.Task) bool { return t.ID == id })
+	}
+	pos := -1
+	if beforeID != "" {
+		pos = indexOf(beforeID)
+	}
+	if pos < 0 && afterID != "" {
+		if j := indexOf(afterID); j >= 0 {
+			pos = j + 1
+		}
+	}
+	if pos < 0 {
+		neighbor := beforeID
+		if neighbor == "" {
+			neighbor = afterID
+		}
+		return nil, fmt.Errorf("%s is not a %s task in %s like %s", neighbor, t.Priority, t.Status, t.ID)
+	}
+
+	// Rank the unranked neighbors we need to sort against. Ranked tasks
+	// come first, so these are a run starting at the first unranked task.
+	last := min(pos, len(group)-1)
+	prev := ""
+	var ranked []*task.Task
+	for j := 0; j <= last; j++ {
+		if group[j].Rank == "" {
+			key, err := rank.Between(prev, "")
+			if err != nil {
+				return nil, err
+			}
+			group[j].Rank = key
+			ranked = append(ranked, &group[j])
+		}
+		prev = group[j].Rank
+	}
+
+	lo, hi := "", ""
+	if pos > 0 {
+		lo = group[pos-1].Rank
+	}
+	if pos < len(group) {
+		hi = group[pos].Rank
+	}
+	key, err := rank.Between(lo, hi)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range ranked {
+		if err := s.Update(n); err != nil {
+			return nil, fmt.Errorf("ranking %s: %w", n.ID, err)
+		}
+	}
+	t.Rank = key
+	if err := s.Update(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
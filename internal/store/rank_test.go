@@ -0,0 +1,101 @@
+package store
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+func boardOrder(t *testing.T, s Store, status string) []string {
+	t.Helper()
+	tasks, err := s.List(Filter{Status: status})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	SortTasks(tasks, s.GetConfig())
+	var ids []string
+	for _, tk := range tasks {
+		ids = append(ids, tk.ID)
+	}
+	return ids
+}
+
+func TestSortTasks(t *testing.T) {
+	s := setupTestStore(t)
+	tasks := []task.Task{
+		{ID: "US-001", Priority: "low"},
+		{ID: "US-002", Priority: "high"},
+		{ID: "US-003", Priority: "high", Rank: "m"},
+		{ID: "US-004", Priority: "high", Rank: "c"},
+	}
+	SortTasks(tasks, s.GetConfig())
+
+	want := []string{"US-004", "US-003", "US-002", "US-001"}
+	for i, id := range want {
+		if tasks[i].ID != id {
+			t.Fatalf("order = %v, want %v", tasks, want)
+		}
+	}
+}
+
+func TestReorder(t *testing.T) {
+	s := setupTestStore(t)
+	for _, id := range []string{"US-001", "US-002", "US-003", "US-004"} {
+		s.Create(&task.Task{ID: id, Title: id, Status: "backlog", Priority: "medium"})
+	}
+	s.Create(&task.Task{ID: "US-005", Title: "Other priority", Status: "backlog", Priority: "high"})
+
+	if _, err := Reorder(s, "US-004", "US-002", ""); err != nil {
+		t.Fatalf("Reorder: %v", err)
+	}
+	want := []string{"US-005", "US-001", "US-004", "US-002", "US-003"}
+	if got := boardOrder(t, s, "backlog"); !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+
+	// Tasks past the insertion point keep no rank.
+	if tk, _ := s.Get("US-003"); tk.Rank != "" {
+		t.Errorf("US-003 rank = %q, want none", tk.Rank)
+	}
+
+	if _, err := Reorder(s, "US-001", "", "US-003"); err != nil {
+		t.Fatalf("Reorder after: %v", err)
+	}
+	want = []string{"US-005", "US-004", "US-002", "US-003", "US-001"}
+	if got := boardOrder(t, s, "backlog"); !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+
+	// A before neighbor of another priority falls back to after, as when a
+	// card is dropped at the bottom of its priority group.
+	if _, err := Reorder(s, "US-004", "US-005", "US-001"); err != nil {
+		t.Fatalf("Reorder at edge: %v", err)
+	}
+	want = []string{"US-005", "US-002", "US-003", "US-001", "US-004"}
+	if got := boardOrder(t, s, "backlog"); !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestReorderErrors(t *testing.T) {
+	s := setupTestStore(t)
+	s.Create(&task.Task{ID: "US-001", Title: "One", Status: "backlog", Priority: "medium"})
+	s.Create(&task.Task{ID: "US-002", Title: "Two", Status: "backlog", Priority: "high"})
+	s.Create(&task.Task{ID: "US-003", Title: "Three", Status: "in-progress", Priority: "medium"})
+
+	tests := []struct {
+		name, id, before, after string
+	}{
+		{"no neighbor", "US-001", "", ""},
+		{"itself", "US-001", "US-001", ""},
+		{"other priority", "US-001", "US-002", ""},
+		{"other status", "US-001", "US-003", ""},
+		{"missing task", "US-009", "US-001", ""},
+	}
+	for _, tt := range tests {
+		if _, err := Reorder(s, tt.id, tt.before, tt.after); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// Renamed records a task that was given a new ID.
+type Renamed struct {
+	OldID string `json:"old_id"`
+	NewID string `json:"new_id"`
+	Title string `json:"title"`
+}
+
+// Renumber moves task oldID to newID and rewrites every depends_on and other
+// relation reference to it, committing the result as one change when auto_commit is on.
+func (s *FilesystemStore) Renumber(oldID, newID string) error {
+	if oldID == newID {
+		return fmt.Errorf("task %s already has that ID", oldID)
+	}
+	if _, err := os.Stat(s.taskPath(newID)); err == nil {
+		return fmt.Errorf("task %s already exists", newID)
+	}
+
+	t, err := s.Get(oldID)
+	if err != nil {
+		return err
+	}
+
+	files, err := s.Scan()
+	if err != nil {
+		return err
+	}
+
+	t.ID = newID
+	if err := s.writeTask(t); err != nil {
+		return err
+	}
+	if err := os.Remove(s.taskPath(oldID)); err != nil {
+		return err
+	}
+	changed := []string{s.taskPath(oldID), s.taskPath(newID)}
+
+	for _, f := range files {
+		if f.Task == nil || f.Task.ID == oldID || !renameReferences(f.Task, oldID, newID) {
+			continue
+		}
+		if err := s.writeTask(f.Task); err != nil {
+			return err
+		}
+		changed = append(changed, s.taskPath(f.Task.ID))
+	}
+
+	s.writeSkeeterMD()
+	// newID may have come from a freshly reserved ID block.
+	changed = append(changed, s.reserved...)
+	s.reserved = nil
+	return s.autoCommit(fmt.Sprintf("renumber %s to %s", oldID, newID), changed...)
+}
+
+// writeTask saves t to its file without touching the updated date.
+func (s *FilesystemStore) writeTask(t *task.Task) error {
+	content, err := task.Marshal(t)
+	if err != nil {
+		return err
+	}
+	path := s.taskPath(t.ID)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+	s.indexWritten(path, t)
+	return nil
+}
+
+// ResolveCollisions untangles task files that two branches created under the
+// same ID. git leaves such an add/add collision as one file with conflict
+// markers; the "ours" side keeps the ID and the "theirs" side is saved under
+// a newly allocated one. References to the old ID that came from the
+// merged-in branch are rewritten to follow its task. Conflicts where both
+// sides edited the same existing task are left alone. Nothing is staged or
+// committed, since a merge is usually still in progress.
+func (s *FilesystemStore) ResolveCollisions() ([]Renamed, error) {
+	files, err := s.Scan()
+	if err != nil {
+		return nil, err
+	}
+
+	var renamed []Renamed
+	var moved []*task.Task
+	collided := make(map[string]bool)
+	for _, f := range files {
+		if f.Err == nil {
+			continue
+		}
+		path := filepath.Join(s.tasksDir(), f.Name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		oursContent, theirsContent, ok := splitConflict(string(data))
+		if !ok || s.hasMergeBase(path) {
+			continue
+		}
+		ours, err := task.Parse(oursContent)
+		if err != nil {
+			continue
+		}
+		theirs, err := task.Parse(theirsContent)
+		if err != nil {
+			continue
+		}
+
+		if err := s.writeTask(ours); err != nil {
+			return renamed, err
+		}
+		newID, err := s.NextID()
+		if err != nil {
+			return renamed, err
+		}
+		oldID := theirs.ID
+		theirs.ID = newID
+		if err := s.writeTask(theirs); err != nil {
+			return renamed, err
+		}
+		renamed = append(renamed, Renamed{OldID: oldID, NewID: newID, Title: theirs.Title})
+		moved = append(moved, theirs)
+		collided[f.Name] = true
+	}
+
+	if len(renamed) == 0 {
+		return nil, nil
+	}
+	if err := s.renameMergedInReferences(renamed, moved, collided); err != nil {
+		return renamed, err
+	}
+	s.writeSkeeterMD()
+	return renamed, nil
+}
+
+// renameMergedInReferences points the merged-in side's references to
+// renamed IDs at their new ones: those in the moved tasks, and those the
+// merged-in branch added to the other tasks it changed, as told by the
+// version on HEAD not having them. Without a merge in progress only the
+// moved tasks are rewritten.
+func (s *FilesystemStore) renameMergedInReferences(renamed []Renamed, moved []*task.Task, collided map[string]bool) error {
+	// rename rewrites t's references except those already on head, which
+	// stay with the task that kept the ID.
+	rename := func(t, head *task.Task) bool {
+		changed := false
+		for _, r := range renamed {
+			if head != nil && references(head, r.OldID) {
+				continue
+			}
+			if renameReferences(t, r.OldID, r.NewID) {
+				changed = true
+			}
+		}
+		return changed
+	}
+
+	for _, t := range moved {
+		if rename(t, nil) {
+			if err := s.writeTask(t); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range s.mergedInFiles() {
+		if collided[name] {
+			continue
+		}
+		t, err := s.Get(strings.TrimSuffix(name, ".md"))
+		if err != nil {
+			continue
+		}
+		var head *task.Task
+		if content, err := s.git("show", "HEAD:./"+filepath.Join(filepath.Base(s.Dir), "tasks", name)); err == nil {
+			head, _ = task.Parse(content)
+		}
+		if rename(t, head) {
+			if err := s.writeTask(t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mergedInFiles returns the names of the task files the branch being merged
+// changed since it forked, or nil when no merge is in progress.
+func (s *FilesystemStore) mergedInFiles() []string {
+	out, err := s.git("diff", "--name-only", "--relative", "HEAD...MERGE_HEAD", "--", filepath.Join(filepath.Base(s.Dir), "tasks"))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if filepath.Ext(line) == ".md" {
+			names = append(names, filepath.Base(line))
+		}
+	}
+	return names
+}
+
+// git runs a git command in the directory holding the store and returns its
+// output.
+func (s *FilesystemStore) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = filepath.Dir(s.Dir)
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// hasMergeBase reports whether git has a common ancestor version of the
+// conflicted file, meaning both branches edited one existing task rather than
+// each adding their own.
+func (s *FilesystemStore) hasMergeBase(path string) bool {
+	cmd := exec.Command("git", "ls-files", "--unmerged", "--", path)
+	cmd.Dir = filepath.Dir(s.Dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		// <mode> <object> <stage>\t<path>; stage 1 is the common ancestor.
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[2] == "1" {
+			return true
+		}
+	}
+	return false
+}
+
+// splitConflict separates a file with git conflict markers into its two
+// sides. Base sections from the diff3 conflict style are dropped.
+func splitConflict(content string) (ours, theirs string, ok bool) {
+	const (
+		both = iota
+		inOurs
+		inBase
+		inTheirs
+	)
+	var o, t strings.Builder
+	state := both
+	for _, line := range strings.SplitAfter(content, "\n") {
+		switch {
+		case strings.HasPrefix(line, "<<<<<<<") && state == both:
+			state = inOurs
+			ok = true
+		case strings.HasPrefix(line, "|||||||") && state == inOurs:
+			state = inBase
+		case strings.HasPrefix(line, "=======") && (state == inOurs || state == inBase):
+			state = inTheirs
+		case strings.HasPrefix(line, ">>>>>>>") && state == inTheirs:
+			state = both
+		case state == both:
+			o.WriteString(line)
+			t.WriteString(line)
+		case state == inOurs:
+			o.WriteString(line)
+		case state == inTheirs:
+			t.WriteString(line)
+		}
+	}
+	if state != both {
+		return "", "", false
+	}
+	return o.String(), t.String(), ok
+}
+
+// references reports whether any of t's relations point at id.
+func references(t *task.Task, id string) bool {
+	for _, kind := range task.Relations {
+		if slices.Contains(*t.Relation(kind), id) {
+			return true
+		}
+	}
+	return false
+}
+
+// renameReferences points t's depends_on and other relations at newID
+// instead of oldID, reporting whether anything changed.
+func renameReferences(t *task.Task, oldID, newID string) bool {
+	changed := false
+	for _, kind := range task.Relations {
+		ids := t.Relation(kind)
+		for i, id := range *ids {
+			if id == oldID {
+				(*ids)[i] = newID
+				changed = true
+			}
+		}
+	}
+	return changed
+}
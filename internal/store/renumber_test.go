@@ -0,0 +1,258 @@
+package store
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/id"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+func TestRenumberRewritesDependencies(t *testing.T) {
+	s := setupTestStore(t)
+	s.Create(&task.Task{ID: "US-001", Title: "Base", Status: "backlog"})
+	s.Create(&task.Task{ID: "US-002", Title: "Dependent", Status: "backlog", DependsOn: []string{"US-001"}})
+	s.Create(&task.Task{ID: "US-003", Title: "Unrelated", Status: "backlog"})
+	s.Create(&task.Task{ID: "US-004", Title: "Duplicate", Status: "backlog", Duplicates: []string{"US-001"}})
+
+	if err := s.Renumber("US-001", "US-010"); err != nil {
+		t.Fatalf("Renumber: %v", err)
+	}
+
+	if _, err := os.Stat(s.taskPath("US-001")); !os.IsNotExist(err) {
+		t.Error("old task file still exists")
+	}
+	moved, err := s.Get("US-010")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if moved.Title != "Base" {
+		t.Errorf("moved title = %q, want Base", moved.Title)
+	}
+	dep, _ := s.Get("US-002")
+	if len(dep.DependsOn) != 1 || dep.DependsOn[0] != "US-010" {
+		t.Errorf("depends_on = %v, want [US-010]", dep.DependsOn)
+	}
+	dup, _ := s.Get("US-004")
+	if len(dup.Duplicates) != 1 || dup.Duplicates[0] != "US-010" {
+		t.Errorf("duplicates = %v, want [US-010]", dup.Duplicates)
+	}
+}
+
+func TestRenumberCommitsReservedBlock(t *testing.T) {
+	t.Setenv("SKEETER_AUTHOR", "alice@example.com")
+	s, dir := setupTestStoreWithGit(t)
+	s.Config.AutoCommit = true
+	s.Config.IDs = config.IDConfig{Strategy: id.Block, BlockSize: 10}
+	s.Create(&task.Task{ID: "US-001", Title: "First", Status: "backlog"})
+
+	// A new author's first ID reserves a block of their own.
+	t.Setenv("SKEETER_AUTHOR", "bob@example.com")
+	newID, err := s.NextID()
+	if err != nil {
+		t.Fatalf("NextID: %v", err)
+	}
+	if err := s.Renumber("US-001", newID); err != nil {
+		t.Fatalf("Renumber: %v", err)
+	}
+
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git status: %v\n%s", err, out)
+	}
+	if len(strings.TrimSpace(string(out))) > 0 {
+		t.Errorf("renumber left changes uncommitted:\n%s", out)
+	}
+}
+
+func TestRenumberRefusesExistingID(t *testing.T) {
+	s := setupTestStore(t)
+	s.Create(&task.Task{ID: "US-001", Title: "One", Status: "backlog"})
+	s.Create(&task.Task{ID: "US-002", Title: "Two", Status: "backlog"})
+
+	if err := s.Renumber("US-001", "US-002"); err == nil {
+		t.Error("expected error renumbering onto an existing task")
+	}
+}
+
+func TestResolveCollisionsAfterMerge(t *testing.T) {
+	s, dir := setupTestStoreWithGit(t)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_MERGE_AUTOEDIT=no")
+		// A conflicting merge exits nonzero; that's the point.
+		if out, err := cmd.CombinedOutput(); err != nil && args[0] != "merge" {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	git("checkout", "-q", "-b", "feature")
+	s.Create(&task.Task{ID: "US-001", Title: "From feature", Status: "backlog", Created: "2026-01-02"})
+	git("add", "-A")
+	git("commit", "-q", "-m", "feature task")
+
+	git("checkout", "-q", "-")
+	s.Create(&task.Task{ID: "US-001", Title: "From main", Status: "backlog", Created: "2026-01-01"})
+	git("add", "-A")
+	git("commit", "-q", "-m", "main task")
+	git("merge", "feature")
+
+	renamed, err := s.ResolveCollisions()
+	if err != nil {
+		t.Fatalf("ResolveCollisions: %v", err)
+	}
+	if len(renamed) != 1 || renamed[0].OldID != "US-001" || renamed[0].NewID != "US-002" {
+		t.Fatalf("renamed = %+v, want US-001 -> US-002", renamed)
+	}
+
+	ours, err := s.Get("US-001")
+	if err != nil || ours.Title != "From main" {
+		t.Errorf("US-001 = %v, %v; want the task from main", ours, err)
+	}
+	theirs, err := s.Get("US-002")
+	if err != nil || theirs.Title != "From feature" || theirs.ID != "US-002" {
+		t.Errorf("US-002 = %v, %v; want the task from feature", theirs, err)
+	}
+}
+
+func TestResolveCollisionsRewritesMergedInReferences(t *testing.T) {
+	s, dir := setupTestStoreWithGit(t)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_MERGE_AUTOEDIT=no")
+		if out, err := cmd.CombinedOutput(); err != nil && args[0] != "merge" {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	s.Create(&task.Task{ID: "US-001", Title: "Shared", Status: "backlog"})
+	git("add", "-A")
+	git("commit", "-q", "-m", "shared task")
+
+	git("checkout", "-q", "-b", "feature")
+	s.Create(&task.Task{ID: "US-002", Title: "From feature", Status: "backlog"})
+	s.Create(&task.Task{ID: "US-003", Title: "Feature follow-up", Status: "backlog", DependsOn: []string{"US-002"}})
+	git("add", "-A")
+	git("commit", "-q", "-m", "feature tasks")
+
+	git("checkout", "-q", "-")
+	s.Create(&task.Task{ID: "US-002", Title: "From main", Status: "backlog"})
+	shared, _ := s.Get("US-001")
+	shared.DependsOn = []string{"US-002"}
+	s.writeTask(shared)
+	git("add", "-A")
+	git("commit", "-q", "-m", "main task")
+	git("merge", "feature")
+
+	renamed, err := s.ResolveCollisions()
+	if err != nil {
+		t.Fatalf("ResolveCollisions: %v", err)
+	}
+	if len(renamed) != 1 || renamed[0].OldID != "US-002" {
+		t.Fatalf("renamed = %+v, want US-002 moved", renamed)
+	}
+	newID := renamed[0].NewID
+
+	// The feature branch's reference follows its task; main's stays.
+	followUp, err := s.Get("US-003")
+	if err != nil {
+		t.Fatalf("Get US-003: %v", err)
+	}
+	if len(followUp.DependsOn) != 1 || followUp.DependsOn[0] != newID {
+		t.Errorf("US-003 depends_on = %v, want [%s]", followUp.DependsOn, newID)
+	}
+	shared, _ = s.Get("US-001")
+	if len(shared.DependsOn) != 1 || shared.DependsOn[0] != "US-002" {
+		t.Errorf("US-001 depends_on = %v, want [US-002]", shared.DependsOn)
+	}
+}
+
+func TestSplitConflict(t *testing.T) {
+	content := "---\nid: US-001\n<<<<<<< HEAD\ntitle: Ours\n||||||| base\ntitle: Base\n=======\ntitle: Theirs\n>>>>>>> feature\n---\n"
+	ours, theirs, ok := splitConflict(content)
+	if !ok {
+		t.Fatal("expected conflict to be found")
+	}
+	if ours != "---\nid: US-001\ntitle: Ours\n---\n" {
+		t.Errorf("ours = %q", ours)
+	}
+	if theirs != "---\nid: US-001\ntitle: Theirs\n---\n" {
+		t.Errorf("theirs = %q", theirs)
+	}
+
+	if _, _, ok := splitConflict("---\nid: US-001\n---\n"); ok {
+		t.Error("expected no conflict in a clean file")
+	}
+}
+
+func TestNextIDBlockStrategy(t *testing.T) {
+	t.Setenv("SKEETER_AUTHOR", "alice@example.com")
+	s := setupTestStore(t)
+	s.Config.IDs = config.IDConfig{Strategy: id.Block, BlockSize: 10}
+
+	first, err := s.NextID()
+	if err != nil {
+		t.Fatalf("NextID: %v", err)
+	}
+	if first != "US-001" {
+		t.Errorf("NextID = %q, want US-001", first)
+	}
+	s.Create(&task.Task{ID: first, Title: "First", Status: "backlog"})
+
+	data, _ := os.ReadFile(filepath.Join(s.Dir, "id-blocks"))
+	if strings.TrimSpace(string(data)) != "US 1-10 alice@example.com" {
+		t.Errorf("id-blocks = %q", data)
+	}
+	attrs, _ := os.ReadFile(filepath.Join(s.Dir, ".gitattributes"))
+	if !strings.Contains(string(attrs), "id-blocks merge=union") {
+		t.Errorf(".gitattributes = %q, want a union merge rule", attrs)
+	}
+
+	t.Setenv("SKEETER_AUTHOR", "bob@example.com")
+	second, _ := s.NextID()
+	if second != "US-011" {
+		t.Errorf("NextID for a second author = %q, want US-011", second)
+	}
+}
+
+func TestNextIDBlockStrategyAddsAttribute(t *testing.T) {
+	t.Setenv("SKEETER_AUTHOR", "alice@example.com")
+	s := setupTestStore(t)
+	s.Config.IDs = config.IDConfig{Strategy: id.Block, BlockSize: 10}
+
+	// An attributes file from the merge driver is already there.
+	attributes := filepath.Join(s.Dir, ".gitattributes")
+	os.WriteFile(attributes, []byte("tasks/*.md merge=skeeter\n"), 0644)
+
+	for range 2 {
+		if _, err := s.NextID(); err != nil {
+			t.Fatalf("NextID: %v", err)
+		}
+		os.Remove(filepath.Join(s.Dir, "id-blocks"))
+	}
+	data, _ := os.ReadFile(attributes)
+	if string(data) != "tasks/*.md merge=skeeter\nid-blocks merge=union\n" {
+		t.Errorf(".gitattributes = %q", data)
+	}
+}
+
+func TestNextIDHashStrategy(t *testing.T) {
+	s := setupTestStore(t)
+	s.Config.IDs.Strategy = id.Hash
+
+	got, err := s.NextID()
+	if err != nil {
+		t.Fatalf("NextID: %v", err)
+	}
+	if !strings.HasPrefix(got, "US-") || len(got) != len("US-")+6 {
+		t.Errorf("NextID = %q, want a hash ID", got)
+	}
+}
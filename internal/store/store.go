@@ -1,6 +1,8 @@
 package store
 
 import (
+	"strings"
+
 	"github.com/andybarilla/skeeter/internal/config"
 	"github.com/andybarilla/skeeter/internal/task"
 )
@@ -20,5 +22,123 @@ type Store interface {
 	Update(t *task.Task) error
 	NextID() (string, error)
 	GetConfig() *config.Config
+	UpdateConfig(fn func(*config.Config) error) error
 	LoadTemplate(name string) (string, error)
 }
+
+// TaskFile is one file in the tasks directory. Task is nil when the file
+// could not be read or parsed, in which case Err says why.
+type TaskFile struct {
+	Name string
+	Task *task.Task
+	Err  error
+}
+
+// Scanner is implemented by stores that can enumerate task files including
+// the ones List skips because they are malformed.
+type Scanner interface {
+	Scan() ([]TaskFile, error)
+}
+
+// ProblemReporter is implemented by stores that remember which files the
+// most recent List call skipped.
+type ProblemReporter interface {
+	Problems() []TaskFile
+}
+
+// Fingerprinter is implemented by remote stores that can tell which tasks
+// changed without downloading every one.
+type Fingerprinter interface {
+	// Fingerprint brings the store up to date with the remote and maps each
+	// task ID to a value that changes whenever the task file does.
+	Fingerprint() (map[string]string, error)
+}
+
+// Fingerprint returns the fingerprints of the tasks in s, listing and
+// hashing every task when s has no cheaper way.
+func Fingerprint(s Store) (map[string]string, error) {
+	if f, ok := s.(Fingerprinter); ok {
+		return f.Fingerprint()
+	}
+	if r, ok := s.(interface{ Refresh() error }); ok {
+		if err := r.Refresh(); err != nil {
+			return nil, err
+		}
+	}
+	tasks, err := s.List(Filter{})
+	if err != nil {
+		return nil, err
+	}
+	prints := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		prints[t.ID] = task.Version(&t)
+	}
+	return prints, nil
+}
+
+// GraphLoader is implemented by remote stores that can fetch every task in
+// a single request, for checks that need the whole dependency graph.
+type GraphLoader interface {
+	LoadGraph() ([]task.Task, error)
+}
+
+// Searcher is implemented by stores that can search without comparing
+// every task.
+type Searcher interface {
+	Search(query string, titleOnly bool, filter Filter) ([]task.Task, error)
+}
+
+// Search returns the tasks in s matching filter whose title, or unless
+// titleOnly whose body, contains query, ignoring case.
+func Search(s Store, query string, titleOnly bool, filter Filter) ([]task.Task, error) {
+	if searcher, ok := s.(Searcher); ok {
+		return searcher.Search(query, titleOnly, filter)
+	}
+	tasks, err := s.List(filter)
+	if err != nil {
+		return nil, err
+	}
+	return searchTasks(tasks, query, titleOnly), nil
+}
+
+func searchTasks(tasks []task.Task, query string, titleOnly bool) []task.Task {
+	var results []task.Task
+	for i := range tasks {
+		if matchesQuery(&tasks[i], query, titleOnly) {
+			results = append(results, tasks[i])
+		}
+	}
+	return results
+}
+
+func matchesQuery(t *task.Task, query string, titleOnly bool) bool {
+	query = strings.ToLower(query)
+	if strings.Contains(strings.ToLower(t.Title), query) {
+		return true
+	}
+	return !titleOnly && strings.Contains(strings.ToLower(t.Body), query)
+}
+
+// Backlinker is implemented by stores that keep a table of which tasks name
+// which, so finding what points at a task doesn't need every task.
+type Backlinker interface {
+	// Backlinks maps each relation kind to the IDs of the tasks whose
+	// frontmatter names id under it. It reports false when the store has no
+	// table to answer from.
+	Backlinks(id string) (map[string][]string, bool)
+}
+
+// tasksFromFiles returns the parsed tasks matching filter along with the
+// files that failed to parse.
+func tasksFromFiles(files []TaskFile, filter Filter) (tasks []task.Task, problems []TaskFile) {
+	for _, f := range files {
+		if f.Err != nil {
+			problems = append(problems, f)
+			continue
+		}
+		if matchesFilter(f.Task, filter) {
+			tasks = append(tasks, *f.Task)
+		}
+	}
+	return tasks, problems
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/task"
+	"github.com/andybarilla/skeeter/internal/webhook"
+)
+
+// writeFlushTimeout bounds the one delivery attempt a write makes per
+// endpoint, so an unreachable endpoint can't hold up every write. What
+// doesn't go through waits for the next write or 'skeeter webhooks flush',
+// which retries with backoff.
+const writeFlushTimeout = 2 * time.Second
+
+// OutboxOwner is implemented by stores that queue webhook deliveries.
+type OutboxOwner interface {
+	Outbox() *webhook.Outbox
+}
+
+// Outbox returns the store's queue of undelivered webhook events.
+func (s *FilesystemStore) Outbox() *webhook.Outbox {
+	return webhook.New(filepath.Join(s.Dir, ".outbox"))
+}
+
+// Outbox returns the queue of webhook events sent from the cached clone.
+func (s *GitStore) Outbox() *webhook.Outbox {
+	return s.fs.Outbox()
+}
+
+// Outbox returns the queue of webhook events for the repository, kept in
+// the user cache directory since there is no local checkout.
+func (s *GitHubStore) Outbox() *webhook.Outbox {
+	root, err := os.UserCacheDir()
+	if err != nil {
+		root = os.TempDir()
+	}
+	sum := sha256.Sum256([]byte(s.owner + "/" + s.repo + "/" + s.dir))
+	return webhook.New(filepath.Join(root, "skeeter", "github", hex.EncodeToString(sum[:8]), "outbox"))
+}
+
+func (s *FilesystemStore) sendEvents(old, t *task.Task, events ...string) {
+	if s.noEvents {
+		return
+	}
+	sendEvents(s.Outbox(), s.Config, s.hookOutput(), old, t, events...)
+}
+
+// updateEvents returns the events a change from old to t sends.
+func updateEvents(old, t *task.Task) []string {
+	if old != nil && old.Status != t.Status {
+		return []string{webhook.EventUpdate, webhook.EventStatusChange}
+	}
+	return []string{webhook.EventUpdate}
+}
+
+// sendEvents queues events on t for the configured webhooks, then makes
+// one quick attempt to deliver everything pending. The task is already
+// saved, so problems only warn on w; undelivered events stay queued.
+func sendEvents(outbox *webhook.Outbox, cfg *config.Config, w io.Writer, old, t *task.Task, events ...string) {
+	hooks := cfg.Webhooks
+	if len(hooks) == 0 {
+		return
+	}
+	for _, event := range events {
+		if err := outbox.Enqueue(hooks, webhook.NewPayload(event, cfg.Project.Name, old, t)); err != nil {
+			fmt.Fprintf(w, "Warning: queuing %s webhook for %s: %v\n", event, t.ID, err)
+			return
+		}
+	}
+	outbox.Attempts = 1
+	outbox.Client = &http.Client{Timeout: writeFlushTimeout}
+	left, err := outbox.Flush(hooks)
+	if err != nil {
+		fmt.Fprintf(w, "Warning: sending webhooks: %v\n", err)
+		return
+	}
+	if len(left) > 0 {
+		fmt.Fprintf(w, "Warning: %d webhook deliveries pending (%s); run 'skeeter webhooks flush' to retry\n",
+			len(left), left[0].LastError)
+	}
+}
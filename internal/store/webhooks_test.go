@@ -0,0 +1,150 @@
+package store
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/task"
+	"github.com/andybarilla/skeeter/internal/webhook"
+)
+
+func TestWritesSendWebhooks(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var p webhook.Payload
+		json.Unmarshal(body, &p)
+		mu.Lock()
+		events = append(events, p.Event+":"+p.Task.Status)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	s := setupTestStore(t)
+	s.Config.Webhooks = []config.Webhook{{URL: srv.URL}}
+	s.Create(&task.Task{ID: "US-001", Title: "Thing", Status: "backlog"})
+	tk, _ := s.Get("US-001")
+	tk.Status = "in-progress"
+	s.Update(tk)
+
+	want := []string{"create:backlog", "update:in-progress", "status-change:in-progress"}
+	if !slices.Equal(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestUndeliveredWebhooksStayQueued(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	s := setupTestStore(t)
+	var out strings.Builder
+	s.HookOutput = &out
+	s.Config.Webhooks = []config.Webhook{{URL: srv.URL}}
+	if err := s.Create(&task.Task{ID: "US-001", Title: "Thing", Status: "backlog"}); err != nil {
+		t.Fatalf("Create = %v, want webhook failures to only warn", err)
+	}
+
+	pending, _ := s.Outbox().Pending()
+	if len(pending) != 1 || pending[0].Event != webhook.EventCreate {
+		t.Errorf("pending = %+v, want the create event", pending)
+	}
+	if !strings.Contains(out.String(), "1 webhook deliveries pending") {
+		t.Errorf("output = %q, want a warning", out.String())
+	}
+}
+
+// webhookRecorder is an endpoint that records the events it receives and
+// answers with status.
+func webhookRecorder(t *testing.T, status int) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var events []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var p webhook.Payload
+		json.Unmarshal(body, &p)
+		mu.Lock()
+		events = append(events, p.Event)
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(events)
+	}
+}
+
+func TestWritesTryWebhooksOnce(t *testing.T) {
+	srv, events := webhookRecorder(t, http.StatusInternalServerError)
+	s := setupTestStore(t)
+	s.HookOutput = io.Discard
+	s.Config.Webhooks = []config.Webhook{{URL: srv.URL}}
+	s.Create(&task.Task{ID: "US-001", Title: "Thing", Status: "backlog"})
+
+	if got := events(); len(got) != 1 {
+		t.Errorf("endpoint hit %d times, want one attempt per write", len(got))
+	}
+}
+
+func TestGitStoreSendsWebhooksAfterPush(t *testing.T) {
+	srv, events := webhookRecorder(t, http.StatusOK)
+	url := setupBareRemote(t)
+	s, err := newGitStore(url, "", t.TempDir())
+	if err != nil {
+		t.Fatalf("newGitStore: %v", err)
+	}
+	s.fs.HookOutput = io.Discard
+	s.fs.Config.Webhooks = []config.Webhook{{URL: srv.URL}}
+
+	hook := filepath.Join(strings.TrimPrefix(url, "file://"), "hooks", "pre-receive")
+	os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0755)
+	if err := s.Create(&task.Task{ID: "US-001", Title: "Rejected", Status: "backlog"}); err == nil {
+		t.Fatal("Create succeeded with the push rejected")
+	}
+	if got := events(); len(got) != 0 {
+		t.Errorf("events = %v, want none for a write that never landed", got)
+	}
+
+	os.Remove(hook)
+	if err := s.Create(&task.Task{ID: "US-001", Title: "Accepted", Status: "backlog"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := events(); !slices.Equal(got, []string{webhook.EventCreate}) {
+		t.Errorf("events = %v, want the create", got)
+	}
+}
+
+func TestGitHubStoreSendsWebhooks(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	srv, events := webhookRecorder(t, http.StatusOK)
+	server := setupGitHubServer()
+	defer server.Close()
+
+	s := &GitHubStore{owner: "owner", repo: "repo", dir: ".skeeter", token: "fake-token", client: server.Client(), baseURL: server.URL}
+	s.cfg = defaultConfigForTest()
+	s.cfg.Webhooks = []config.Webhook{{URL: srv.URL}}
+
+	tk, _ := s.Get("US-001")
+	tk.Status = "in-progress"
+	if err := s.Update(tk); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	want := []string{webhook.EventUpdate, webhook.EventStatusChange}
+	if got := events(); !slices.Equal(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
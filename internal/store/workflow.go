@@ -0,0 +1,248 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// TransitionError reports the workflow rules a status change breaks.
+type TransitionError struct {
+	ID         string   `json:"id"`
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Violations []string `json:"violations"`
+}
+
+func (e *TransitionError) Error() string {
+	id := e.ID
+	if id == "" {
+		id = "new task"
+	}
+	return fmt.Sprintf("cannot move %s from %s to %s: %s", id, e.From, e.To, strings.Join(e.Violations, "; "))
+}
+
+// ChangeStatus moves t to status, enforcing the workflow in s's config: the
+// move must be one of the allowed transitions and t must pass the guards of
+// status. When it doesn't, ChangeStatus returns a *TransitionError and
+// leaves t alone, unless force is set, in which case the move goes ahead
+// and the overridden rules are recorded with it. Every move is appended to
+// t's history. The caller saves t.
+//
+// Stores apply the same rules to any save that changes a task's status, so
+// force here is the way to override them.
+func ChangeStatus(s Store, t *task.Task, status string, force bool) error {
+	cfg := s.GetConfig()
+	if !cfg.ValidStatus(status) {
+		return fmt.Errorf("invalid status %q (valid: %s)", status, strings.Join(cfg.Statuses, ", "))
+	}
+	if status == t.Status {
+		return nil
+	}
+
+	violations, err := CheckTransition(s, t, status)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 && !force {
+		return &TransitionError{ID: t.ID, From: t.Status, To: status, Violations: violations}
+	}
+
+	t.History = append(t.History, task.Transition{
+		Date:   time.Now().Format("2006-01-02"),
+		From:   t.Status,
+		To:     status,
+		Forced: strings.Join(violations, "; "),
+	})
+	t.Status = status
+	return nil
+}
+
+// AssignError is returned when assigning a task would take its new
+// assignee past the per-assignee WIP limit.
+type AssignError struct {
+	ID         string   `json:"id"`
+	Assignee   string   `json:"assignee"`
+	Violations []string `json:"violations"`
+}
+
+func (e *AssignError) Error() string {
+	return fmt.Sprintf("cannot assign %s to %s: %s", e.ID, e.Assignee, strings.Join(e.Violations, "; "))
+}
+
+// ChangeAssignee assigns t to assignee. While t is in flight the new
+// assignee must be under the per-assignee WIP limit; when not, it returns
+// an *AssignError and leaves t alone, unless force is set. The caller
+// saves t.
+func ChangeAssignee(s Store, t *task.Task, assignee string, force bool) error {
+	if assignee == t.Assignee {
+		return nil
+	}
+	cfg := s.GetConfig()
+	if !force && assignee != "" && cfg.WIPLimits.PerAssignee > 0 && cfg.InFlight(t.Status) {
+		allTasks, err := s.List(Filter{})
+		if err != nil {
+			return err
+		}
+		assigned := *t
+		assigned.Assignee = assignee
+		if v := assigneeViolation(cfg, &assigned, t.Status, allTasks); v != "" {
+			return &AssignError{ID: t.ID, Assignee: assignee, Violations: []string{v}}
+		}
+	}
+	t.Assignee = assignee
+	return nil
+}
+
+// ApplyStatusChange enforces the workflow on t, whose status was changed
+// from old's by other means than ChangeStatus, such as editing the file, and
+// records the move in its history. old is the task as stored. A move
+// ChangeStatus already made is left as it is, since it followed the rules or
+// was forced: it is the one entry t's history has beyond old's, and goes
+// from old's status to t's.
+func ApplyStatusChange(s Store, t, old *task.Task, force bool) error {
+	if old.Status == t.Status {
+		return nil
+	}
+	if n := len(t.History); n == len(old.History)+1 && t.History[n-1].From == old.Status && t.History[n-1].To == t.Status {
+		return nil
+	}
+	moved := *t
+	moved.Status = old.Status
+	if err := ChangeStatus(s, &moved, t.Status, force); err != nil {
+		return err
+	}
+	t.History = moved.History
+	return nil
+}
+
+// applyCreateStatus enforces the workflow on a task created past the first
+// status, as a move from it.
+func applyCreateStatus(s Store, t *task.Task) error {
+	cfg := s.GetConfig()
+	if len(cfg.Statuses) == 0 {
+		return nil
+	}
+	return ApplyStatusChange(s, t, &task.Task{Status: cfg.Statuses[0]}, false)
+}
+
+// CheckTransition returns the workflow rules and WIP limits moving t to
+// status would break, or nil if the move is allowed.
+func CheckTransition(s Store, t *task.Task, status string) ([]string, error) {
+	cfg := s.GetConfig()
+	var violations []string
+	if ok, allowed := cfg.CanTransition(t.Status, status); !ok {
+		violations = append(violations, fmt.Sprintf("%s can only move to %s", t.Status, strings.Join(allowed, ", ")))
+	}
+
+	var allTasks []task.Task
+	listed := false
+	list := func() ([]task.Task, error) {
+		if listed {
+			return allTasks, nil
+		}
+		var err error
+		allTasks, err = s.List(Filter{})
+		listed = err == nil
+		return allTasks, err
+	}
+
+	for _, guard := range cfg.Workflow.Guards[status] {
+		switch guard {
+		case config.GuardNotBlocked:
+			allTasks, err := list()
+			if err != nil {
+				return nil, err
+			}
+			if blockedBy := GetDependencyStatus(s, t, allTasks).BlockedBy; len(blockedBy) > 0 {
+				violations = append(violations, fmt.Sprintf("%s requires finished dependencies (waiting on %s)", status, strings.Join(blockedBy, ", ")))
+			}
+		case config.GuardAssignee:
+			if t.Assignee == "" {
+				violations = append(violations, fmt.Sprintf("%s requires an assignee", status))
+			}
+		case config.GuardCriteriaChecked:
+			if open := OpenCriteria(t.Body); len(open) > 0 {
+				violations = append(violations, fmt.Sprintf("%s requires every acceptance criterion checked (open: %s)", status, strings.Join(open, ", ")))
+			}
+		default:
+			return nil, fmt.Errorf("unknown guard %q for %s in config.yaml (valid: %s)", guard, status, strings.Join(config.Guards, ", "))
+		}
+	}
+
+	limits := cfg.WIPLimits
+	if limits.Statuses[status] > 0 || (limits.PerAssignee > 0 && t.Assignee != "" && cfg.InFlight(status)) {
+		allTasks, err := list()
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, WIPViolations(cfg, t, status, allTasks)...)
+	}
+	return violations, nil
+}
+
+// WIPViolations returns the WIP limits moving t to status would exceed,
+// counting the other tasks in allTasks.
+func WIPViolations(cfg *config.Config, t *task.Task, status string, allTasks []task.Task) []string {
+	inStatus := 0
+	for _, other := range allTasks {
+		if other.ID != t.ID && other.Status == status {
+			inStatus++
+		}
+	}
+
+	var violations []string
+	if limit := cfg.WIPLimits.Statuses[status]; limit > 0 && inStatus >= limit {
+		violations = append(violations, fmt.Sprintf("%s is at its WIP limit (%d of %d)", status, inStatus, limit))
+	}
+	if v := assigneeViolation(cfg, t, status, allTasks); v != "" {
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// assigneeViolation returns the per-assignee WIP limit t's assignee would
+// exceed holding t in status, or "".
+func assigneeViolation(cfg *config.Config, t *task.Task, status string, allTasks []task.Task) string {
+	limit := cfg.WIPLimits.PerAssignee
+	if limit <= 0 || t.Assignee == "" || !cfg.InFlight(status) {
+		return ""
+	}
+	held := 0
+	for _, other := range allTasks {
+		if other.ID != t.ID && other.Assignee == t.Assignee && cfg.InFlight(other.Status) {
+			held++
+		}
+	}
+	if held < limit {
+		return ""
+	}
+	return fmt.Sprintf("%s already has %d tasks in flight (limit %d per assignee)", t.Assignee, held, limit)
+}
+
+// OpenCriteria returns the unticked checkboxes under the body's Acceptance
+// Criteria heading. Empty placeholder boxes, as in the default template,
+// don't count.
+func OpenCriteria(body string) []string {
+	var open []string
+	inCriteria := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			inCriteria = strings.EqualFold(strings.TrimSpace(strings.TrimLeft(trimmed, "#")), "Acceptance Criteria")
+			continue
+		}
+		if !inCriteria {
+			continue
+		}
+		for _, box := range []string{"- [ ]", "* [ ]"} {
+			if item, ok := strings.CutPrefix(trimmed, box); ok && strings.TrimSpace(item) != "" {
+				open = append(open, strings.TrimSpace(item))
+			}
+		}
+	}
+	return open
+}
@@ -0,0 +1,267 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+func setupWorkflowStore(t *testing.T) *FilesystemStore {
+	t.Helper()
+	s := setupTestStore(t)
+	err := s.UpdateConfig(func(cfg *config.Config) error {
+		cfg.Workflow = config.WorkflowConfig{
+			Transitions: map[string][]string{
+				"backlog":               {"ready-for-development"},
+				"ready-for-development": {"in-progress", "backlog"},
+				"in-progress":           {"done", "ready-for-development"},
+			},
+			Guards: map[string][]string{
+				"in-progress": {config.GuardNotBlocked, config.GuardAssignee},
+				"done":        {config.GuardCriteriaChecked},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	return s
+}
+
+func TestChangeStatusEnforcesTransitions(t *testing.T) {
+	s := setupWorkflowStore(t)
+	tk := &task.Task{ID: "US-001", Status: "backlog"}
+
+	err := ChangeStatus(s, tk, "done", false)
+	var te *TransitionError
+	if !errors.As(err, &te) {
+		t.Fatalf("ChangeStatus backlog -> done = %v, want a TransitionError", err)
+	}
+	if tk.Status != "backlog" || len(tk.History) != 0 {
+		t.Errorf("rejected move changed the task: %+v", tk)
+	}
+	if !strings.Contains(err.Error(), "backlog can only move to ready-for-development") {
+		t.Errorf("error = %q", err)
+	}
+
+	if err := ChangeStatus(s, tk, "ready-for-development", false); err != nil {
+		t.Fatalf("allowed move: %v", err)
+	}
+	if len(tk.History) != 1 || tk.History[0].From != "backlog" || tk.History[0].Forced != "" {
+		t.Errorf("history = %+v", tk.History)
+	}
+}
+
+func TestChangeStatusGuards(t *testing.T) {
+	s := setupWorkflowStore(t)
+	s.Create(&task.Task{ID: "US-001", Title: "Dependency", Status: "backlog"})
+	tk := &task.Task{ID: "US-002", Status: "ready-for-development", DependsOn: task.FlowSlice{"US-001"}}
+
+	var te *TransitionError
+	if err := ChangeStatus(s, tk, "in-progress", false); !errors.As(err, &te) {
+		t.Fatalf("ChangeStatus = %v, want a TransitionError", err)
+	}
+	if len(te.Violations) != 2 {
+		t.Errorf("violations = %v, want blocked and unassigned", te.Violations)
+	}
+
+	tk.Status = "in-progress"
+	tk.Body = "## Acceptance Criteria\n\n- [x] Works\n- [ ] Documented\n- [ ]\n"
+	if err := ChangeStatus(s, tk, "done", false); !errors.As(err, &te) ||
+		!strings.Contains(te.Violations[0], "open: Documented") {
+		t.Fatalf("ChangeStatus to done = %v", err)
+	}
+}
+
+func TestChangeStatusForce(t *testing.T) {
+	s := setupWorkflowStore(t)
+	tk := &task.Task{ID: "US-001", Status: "backlog"}
+
+	if err := ChangeStatus(s, tk, "in-progress", true); err != nil {
+		t.Fatalf("forced move: %v", err)
+	}
+	if tk.Status != "in-progress" {
+		t.Errorf("status = %q, want in-progress", tk.Status)
+	}
+	if len(tk.History) != 1 || !strings.Contains(tk.History[0].Forced, "requires an assignee") {
+		t.Errorf("history = %+v, want the overridden rules", tk.History)
+	}
+}
+
+func TestChangeStatusWithoutWorkflow(t *testing.T) {
+	s := setupTestStore(t)
+	tk := &task.Task{ID: "US-001", Status: "backlog"}
+
+	if err := ChangeStatus(s, tk, "done", false); err != nil {
+		t.Fatalf("ChangeStatus: %v", err)
+	}
+	if err := ChangeStatus(s, tk, "nope", false); err == nil {
+		t.Error("expected an error for an invalid status")
+	}
+}
+
+func TestUpdateEnforcesWorkflow(t *testing.T) {
+	s := setupWorkflowStore(t)
+	tk := &task.Task{ID: "US-001", Title: "Task", Status: "backlog", Priority: "medium"}
+	if err := s.Create(tk); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Setting the field directly skips ChangeStatus but not the store.
+	tk.Status = "done"
+	var te *TransitionError
+	if err := s.Update(tk); !errors.As(err, &te) || te.From != "backlog" {
+		t.Fatalf("Update backlog -> done = %v, want a TransitionError", err)
+	}
+	if got, _ := s.Get("US-001"); got.Status != "backlog" {
+		t.Errorf("rejected update saved status %q", got.Status)
+	}
+
+	tk.Status = "ready-for-development"
+	if err := s.Update(tk); err != nil {
+		t.Fatalf("allowed update: %v", err)
+	}
+	got, _ := s.Get("US-001")
+	if len(got.History) != 1 || got.History[0].From != "backlog" || got.History[0].To != "ready-for-development" {
+		t.Errorf("history = %+v", got.History)
+	}
+
+	// A forced ChangeStatus is the way past the rules.
+	if err := ChangeStatus(s, got, "done", true); err != nil {
+		t.Fatalf("ChangeStatus force: %v", err)
+	}
+	if err := s.Update(got); err != nil {
+		t.Fatalf("Update after forced move: %v", err)
+	}
+	if got, _ := s.Get("US-001"); got.Status != "done" || len(got.History) != 2 || got.History[1].Forced == "" {
+		t.Errorf("after forced move: status %q, history %+v", got.Status, got.History)
+	}
+}
+
+func TestUpdateEnforcesRepeatedMove(t *testing.T) {
+	s := setupWorkflowStore(t)
+	tk := &task.Task{ID: "US-001", Title: "Task", Status: "backlog", Priority: "medium"}
+	if err := s.Create(tk); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := ChangeStatus(s, tk, "in-progress", true); err != nil {
+		t.Fatalf("ChangeStatus force: %v", err)
+	}
+	if err := s.Update(tk); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	// Put the file back in the backlog by hand, history and all.
+	tk.Status = "backlog"
+	content, err := task.Marshal(tk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.taskPath(tk.ID), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The history still ends with backlog -> in-progress, but this move is
+	// a new one and must follow the rules.
+	tk.Status = "in-progress"
+	var te *TransitionError
+	if err := s.Update(tk); !errors.As(err, &te) {
+		t.Fatalf("Update backlog -> in-progress again = %v, want a TransitionError", err)
+	}
+	if got, _ := s.Get("US-001"); got.Status != "backlog" {
+		t.Errorf("rejected update saved status %q", got.Status)
+	}
+}
+
+func TestCreateEnforcesWorkflow(t *testing.T) {
+	s := setupWorkflowStore(t)
+
+	tk := &task.Task{ID: "US-001", Title: "Task", Status: "in-progress", Priority: "medium"}
+	var te *TransitionError
+	if err := s.Create(tk); !errors.As(err, &te) || te.From != "backlog" {
+		t.Fatalf("Create in-progress = %v, want a TransitionError", err)
+	}
+
+	tk.Status = "ready-for-development"
+	if err := s.Create(tk); err != nil {
+		t.Fatalf("Create ready-for-development: %v", err)
+	}
+	if len(tk.History) != 1 || tk.History[0].From != "backlog" {
+		t.Errorf("history = %+v", tk.History)
+	}
+}
+
+func TestOpenCriteria(t *testing.T) {
+	body := "## Acceptance Criteria\n\n- [x] One\n- [ ] Two\n* [ ] Three\n- [ ]\n\n## Context\n\n- [ ] Not a criterion\n"
+	if got := OpenCriteria(body); !slices.Equal(got, []string{"Two", "Three"}) {
+		t.Errorf("OpenCriteria = %v, want [Two Three]", got)
+	}
+}
+
+func TestChangeStatusWIPLimits(t *testing.T) {
+	s := setupTestStore(t)
+	s.UpdateConfig(func(cfg *config.Config) error {
+		cfg.WIPLimits = config.WIPLimits{PerAssignee: 1, Statuses: map[string]int{"in-progress": 2}}
+		return nil
+	})
+	s.Create(&task.Task{ID: "US-001", Status: "in-progress", Assignee: "alice"})
+	s.Create(&task.Task{ID: "US-002", Status: "in-progress", Assignee: "bob"})
+
+	var te *TransitionError
+	tk := &task.Task{ID: "US-003", Status: "ready-for-development", Assignee: "carol"}
+	if err := ChangeStatus(s, tk, "in-progress", false); !errors.As(err, &te) ||
+		!slices.Equal(te.Violations, []string{"in-progress is at its WIP limit (2 of 2)"}) {
+		t.Fatalf("ChangeStatus into a full column = %v", err)
+	}
+
+	// alice already holds US-001; a second task in flight exceeds her limit
+	// even in a column with room.
+	tk = &task.Task{ID: "US-004", Status: "backlog", Assignee: "alice"}
+	if err := ChangeStatus(s, tk, "ready-for-development", false); !errors.As(err, &te) ||
+		!strings.Contains(te.Violations[0], "alice already has 1 tasks in flight") {
+		t.Fatalf("ChangeStatus past the per-assignee limit = %v", err)
+	}
+
+	// Moving a task within the flight, or out of it, is fine.
+	first, _ := s.Get("US-001")
+	if err := ChangeStatus(s, first, "done", false); err != nil {
+		t.Errorf("ChangeStatus to done: %v", err)
+	}
+}
+
+func TestChangeAssigneeWIPLimit(t *testing.T) {
+	s := setupTestStore(t)
+	s.UpdateConfig(func(cfg *config.Config) error {
+		cfg.WIPLimits = config.WIPLimits{PerAssignee: 1}
+		return nil
+	})
+	s.Create(&task.Task{ID: "US-001", Status: "in-progress", Assignee: "alice"})
+	s.Create(&task.Task{ID: "US-002", Status: "in-progress", Assignee: "bob"})
+	s.Create(&task.Task{ID: "US-003", Status: "backlog"})
+
+	var ae *AssignError
+	second, _ := s.Get("US-002")
+	if err := ChangeAssignee(s, second, "alice", false); !errors.As(err, &ae) ||
+		!strings.Contains(ae.Violations[0], "alice already has 1 tasks in flight") {
+		t.Fatalf("ChangeAssignee past the limit = %v", err)
+	}
+	if second.Assignee != "bob" {
+		t.Errorf("refused ChangeAssignee left assignee %q, want bob", second.Assignee)
+	}
+
+	if err := ChangeAssignee(s, second, "alice", true); err != nil || second.Assignee != "alice" {
+		t.Errorf("forced ChangeAssignee = %v, assignee %q", err, second.Assignee)
+	}
+
+	// A task in the backlog isn't in flight, so it doesn't count.
+	third, _ := s.Get("US-003")
+	if err := ChangeAssignee(s, third, "alice", false); err != nil {
+		t.Errorf("ChangeAssignee on a backlog task: %v", err)
+	}
+}
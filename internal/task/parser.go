@@ -1,6 +1,8 @@
 package task
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 
@@ -42,3 +44,16 @@ func Marshal(t *Task) (string, error) {
 	buf.WriteString(t.Body)
 	return buf.String(), nil
 }
+
+// Version returns a token that changes whenever any stored field of t does.
+// Compare the versions of two reads of a task to tell whether it was edited
+// in between.
+func Version(t *Task) string {
+	content, err := Marshal(t)
+	if err != nil {
+		// Marshal only fails on types yaml can't encode, which Task has none of.
+		return ""
+	}
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:8])
+}
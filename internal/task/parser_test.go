@@ -1,6 +1,7 @@
 package task
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -85,3 +86,54 @@ updated: "2026-01-01"
 		t.Errorf("ID = %q, want %q", tk.ID, "US-001")
 	}
 }
+
+func TestVersion(t *testing.T) {
+	a := &Task{ID: "US-001", Title: "One", Status: "backlog", Body: "Body\n"}
+	b := *a
+
+	if Version(a) != Version(&b) {
+		t.Error("identical tasks have different versions")
+	}
+
+	b.Version = "stale"
+	if Version(a) != Version(&b) {
+		t.Error("version depends on the Version field itself")
+	}
+
+	b.Body = "Edited\n"
+	if Version(a) == Version(&b) {
+		t.Error("editing the body did not change the version")
+	}
+
+	b = *a
+	b.Tags = FlowSlice{"x"}
+	if Version(a) == Version(&b) {
+		t.Error("adding a tag did not change the version")
+	}
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	in := &Task{
+		ID:     "US-001",
+		Title:  "Test",
+		Status: "done",
+		History: []Transition{
+			{Date: "2026-01-02", From: "backlog", To: "in-progress"},
+			{Date: "2026-01-03", From: "in-progress", To: "done", Forced: "done requires an assignee, really: yes"},
+		},
+	}
+	content, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.Contains(content, "  - {date: 2026-01-02, from: backlog, to: in-progress}\n") {
+		t.Errorf("history is not written one entry per line:\n%s", content)
+	}
+	out, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !reflect.DeepEqual(out.History, in.History) {
+		t.Errorf("History = %+v, want %+v", out.History, in.History)
+	}
+}
@@ -22,16 +22,73 @@ func (f FlowSlice) MarshalYAML() (any, error) {
 }
 
 type Task struct {
-	ID        string    `yaml:"id" json:"id"`
-	Title     string    `yaml:"title" json:"title"`
-	Status    string    `yaml:"status" json:"status"`
-	Priority  string    `yaml:"priority" json:"priority"`
-	Assignee  string    `yaml:"assignee,omitempty" json:"assignee"`
-	Tags      FlowSlice `yaml:"tags,omitempty" json:"tags"`
-	Links     FlowSlice `yaml:"links,omitempty" json:"links"`
-	DependsOn FlowSlice `yaml:"depends_on,omitempty" json:"depends_on"`
-	Due       string    `yaml:"due,omitempty" json:"due"`
-	Created   string    `yaml:"created" json:"created"`
-	Updated   string    `yaml:"updated" json:"updated"`
-	Body      string    `yaml:"-" json:"body"`
+	ID         string       `yaml:"id" json:"id"`
+	Title      string       `yaml:"title" json:"title"`
+	Status     string       `yaml:"status" json:"status"`
+	Priority   string       `yaml:"priority" json:"priority"`
+	Rank       string       `yaml:"rank,omitempty" json:"rank"`
+	Assignee   string       `yaml:"assignee,omitempty" json:"assignee"`
+	Type       string       `yaml:"type,omitempty" json:"type,omitempty"`
+	Tags       FlowSlice    `yaml:"tags,omitempty" json:"tags"`
+	Links      FlowSlice    `yaml:"links,omitempty" json:"links"`
+	Branch     string       `yaml:"branch,omitempty" json:"branch,omitempty"`
+	DependsOn  FlowSlice    `yaml:"depends_on,omitempty" json:"depends_on"`
+	RelatesTo  FlowSlice    `yaml:"relates_to,omitempty" json:"relates_to,omitempty"`
+	Duplicates FlowSlice    `yaml:"duplicates,omitempty" json:"duplicates,omitempty"`
+	SplitFrom  FlowSlice    `yaml:"split_from,omitempty" json:"split_from,omitempty"`
+	Due        string       `yaml:"due,omitempty" json:"due"`
+	Created    string       `yaml:"created" json:"created"`
+	Updated    string       `yaml:"updated" json:"updated"`
+	History    []Transition `yaml:"history,omitempty" json:"history,omitempty"`
+	Body       string       `yaml:"-" json:"body"`
+	// Version identifies the content the task was read with, for callers
+	// that need to detect concurrent edits. It is never stored; see
+	// Version.
+	Version string `yaml:"-" json:"version,omitempty"`
+}
+
+// Transition records one status change in a task's history.
+type Transition struct {
+	Date string `yaml:"date" json:"date"`
+	From string `yaml:"from" json:"from"`
+	To   string `yaml:"to" json:"to"`
+	// Forced lists the workflow rules the change overrode.
+	Forced string `yaml:"forced,omitempty" json:"forced,omitempty"`
+}
+
+// MarshalYAML writes a transition on one line: {date: ..., from: ..., to: ...}
+func (t Transition) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+	add := func(key, value string) {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: value})
+	}
+	add("date", t.Date)
+	add("from", t.From)
+	add("to", t.To)
+	if t.Forced != "" {
+		add("forced", t.Forced)
+	}
+	return node, nil
+}
+
+// Relations are the frontmatter fields that point from a task to other
+// tasks. Only depends_on blocks; the others are informational.
+var Relations = []string{"depends_on", "relates_to", "duplicates", "split_from"}
+
+// Relation returns the list t keeps for the relation kind, one of
+// Relations, or nil for an unknown kind.
+func (t *Task) Relation(kind string) *FlowSlice {
+	switch kind {
+	case "depends_on":
+		return &t.DependsOn
+	case "relates_to":
+		return &t.RelatesTo
+	case "duplicates":
+		return &t.Duplicates
+	case "split_from":
+		return &t.SplitFrom
+	}
+	return nil
 }
@@ -0,0 +1,271 @@
+// Package tui is a full-screen terminal kanban board over a store.
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// Board is the state of the terminal board: the tasks grouped into the
+// workflow's columns, the selection and the filter. It knows nothing about
+// the terminal, so it can be driven and rendered in tests.
+type Board struct {
+	cfg     *config.Config
+	tasks   []task.Task
+	columns [][]task.Task
+
+	col, row int
+	// offsets is how far each column is scrolled.
+	offsets []int
+
+	// Filter narrows the board to tasks whose ID, title, assignee,
+	// priority or tags contain it, ignoring case.
+	Filter string
+	// Message is shown in the footer until the next key.
+	Message string
+	// Help shows the key bindings instead of the board.
+	Help bool
+}
+
+// NewBoard returns an empty board with a column per status in cfg.
+func NewBoard(cfg *config.Config) *Board {
+	b := &Board{cfg: cfg}
+	b.Load(nil)
+	return b
+}
+
+// Load replaces the tasks on the board, keeping the selected task selected
+// when it is still there.
+func (b *Board) Load(tasks []task.Task) {
+	selected := b.Selected()
+	b.tasks = tasks
+	b.regroup()
+	if selected != nil {
+		b.Select(selected.ID)
+	}
+}
+
+// SetFilter changes the filter and regroups the board.
+func (b *Board) SetFilter(filter string) {
+	selected := b.Selected()
+	b.Filter = filter
+	b.regroup()
+	if selected != nil {
+		b.Select(selected.ID)
+	}
+}
+
+func (b *Board) regroup() {
+	b.columns = make([][]task.Task, len(b.cfg.Statuses))
+	b.offsets = make([]int, len(b.cfg.Statuses))
+	index := make(map[string]int, len(b.cfg.Statuses))
+	for i, status := range b.cfg.Statuses {
+		index[status] = i
+	}
+	for _, t := range b.tasks {
+		i, ok := index[t.Status]
+		if !ok || !b.matches(&t) {
+			continue
+		}
+		b.columns[i] = append(b.columns[i], t)
+	}
+	for _, col := range b.columns {
+		store.SortTasks(col, b.cfg)
+	}
+	b.clamp()
+}
+
+func (b *Board) matches(t *task.Task) bool {
+	if b.Filter == "" {
+		return true
+	}
+	needle := strings.ToLower(b.Filter)
+	fields := append([]string{t.ID, t.Title, t.Assignee, t.Priority}, t.Tags...)
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Board) clamp() {
+	if len(b.columns) == 0 {
+		b.col, b.row = 0, 0
+		return
+	}
+	b.col = max(0, min(b.col, len(b.columns)-1))
+	b.row = max(0, min(b.row, len(b.columns[b.col])-1))
+}
+
+// Selected returns the selected task, or nil if its column is empty.
+func (b *Board) Selected() *task.Task {
+	if b.col >= len(b.columns) || b.row >= len(b.columns[b.col]) {
+		return nil
+	}
+	t := b.columns[b.col][b.row]
+	return &t
+}
+
+// Select moves the selection to the task with id, if it is on the board.
+func (b *Board) Select(id string) {
+	for c, col := range b.columns {
+		for r, t := range col {
+			if t.ID == id {
+				b.col, b.row = c, r
+				return
+			}
+		}
+	}
+}
+
+// Up, Down, Left and Right move the selection. Moving across columns keeps
+// the row where the new column is long enough.
+func (b *Board) Up()    { b.row--; b.clamp() }
+func (b *Board) Down()  { b.row++; b.clamp() }
+func (b *Board) Left()  { b.col--; b.clamp() }
+func (b *Board) Right() { b.col++; b.clamp() }
+
+// Neighbor returns the status delta columns away from the selected task's,
+// or "" at either end of the workflow.
+func (b *Board) Neighbor(delta int) string {
+	i := b.col + delta
+	if i < 0 || i >= len(b.cfg.Statuses) {
+		return ""
+	}
+	return b.cfg.Statuses[i]
+}
+
+// Render draws the board into a width × height screen. Lines are separated
+// by "\r\n" because the terminal is in raw mode.
+func (b *Board) Render(width, height int) string {
+	if b.Help {
+		return strings.Join(fitLines(helpLines, width, height), "\r\n")
+	}
+
+	lines := make([]string, 0, height)
+	title := b.cfg.Project.Name
+	if title == "" {
+		title = "skeeter"
+	}
+	lines = append(lines, bold+fit(title, width)+reset)
+
+	bodyHeight := max(0, height-3)
+	lines = append(lines, b.renderColumns(width, bodyHeight)...)
+
+	footer := "←→↑↓ select  H/L move  e edit  / filter  r refresh  ? help  q quit"
+	if b.Filter != "" {
+		footer = fmt.Sprintf("filter: %s  (esc clears)  %s", b.Filter, footer)
+	}
+	lines = append(lines, "")
+	if b.Message != "" {
+		lines[len(lines)-1] = fit(b.Message, width)
+	}
+	lines = append(lines, dim+fit(footer, width)+reset)
+	return strings.Join(lines, "\r\n")
+}
+
+func (b *Board) renderColumns(width, height int) []string {
+	n := len(b.columns)
+	lines := make([]string, height)
+	if n == 0 || height == 0 {
+		return lines
+	}
+	colWidth := max(8, (width-(n-1))/n)
+	cardRows := max(0, height-2)
+
+	for c, col := range b.columns {
+		// Keep the selected card in view.
+		if c == b.col {
+			if b.row < b.offsets[c] {
+				b.offsets[c] = b.row
+			}
+			if cardRows > 0 && b.row >= b.offsets[c]+cardRows {
+				b.offsets[c] = b.row - cardRows + 1
+			}
+		}
+
+		cells := make([]string, height)
+		header := fmt.Sprintf("%s (%d)", b.cfg.Statuses[c], len(col))
+		cells[0] = bold + pad(header, colWidth) + reset
+		if height > 1 {
+			cells[1] = strings.Repeat("─", colWidth)
+		}
+		for i := 0; i < cardRows; i++ {
+			r := b.offsets[c] + i
+			if r >= len(col) {
+				cells[i+2] = pad("", colWidth)
+				continue
+			}
+			t := col[r]
+			label := t.ID + " " + t.Title
+			if t.Assignee != "" {
+				label += " @" + t.Assignee
+			}
+			card := pad(label, colWidth)
+			if c == b.col && r == b.row {
+				card = reverse + card + reset
+			}
+			cells[i+2] = card
+		}
+
+		for i := range lines {
+			if c > 0 {
+				lines[i] += "│"
+			}
+			lines[i] += cells[i]
+		}
+	}
+	return lines
+}
+
+var helpLines = []string{
+	"Keys",
+	"",
+	"  ←/h  →/l     previous / next column",
+	"  ↑/k  ↓/j     previous / next task",
+	"  H  L         move the task to the previous / next status",
+	"  e  enter     open the task in $EDITOR",
+	"  /            filter by ID, title, assignee, priority or tag",
+	"  esc          clear the filter",
+	"  r            reload the board",
+	"  ?            toggle this help",
+	"  q  ctrl-c    quit",
+}
+
+const (
+	bold    = "\x1b[1m"
+	dim     = "\x1b[2m"
+	reverse = "\x1b[7m"
+	reset   = "\x1b[0m"
+)
+
+// fit truncates s to width runes, marking the cut with an ellipsis.
+func fit(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(r[:max(0, width)])
+	}
+	return string(r[:width-1]) + "…"
+}
+
+// pad fits s to exactly width runes.
+func pad(s string, width int) string {
+	s = fit(s, width)
+	return s + strings.Repeat(" ", width-len([]rune(s)))
+}
+
+func fitLines(lines []string, width, height int) []string {
+	out := make([]string, 0, height)
+	for i := 0; i < len(lines) && i < height; i++ {
+		out = append(out, fit(lines[i], width))
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+func testBoard() *Board {
+	cfg := config.Default()
+	cfg.Project.Name = "Demo"
+	b := NewBoard(cfg)
+	b.Load([]task.Task{
+		{ID: "US-001", Title: "Login page", Status: cfg.Statuses[0], Priority: "low"},
+		{ID: "US-002", Title: "Signup", Status: cfg.Statuses[0], Priority: "high", Assignee: "ana"},
+		{ID: "US-003", Title: "Billing", Status: cfg.Statuses[1], Priority: "medium", Tags: task.FlowSlice{"payments"}},
+	})
+	return b
+}
+
+func TestBoardNavigation(t *testing.T) {
+	b := testBoard()
+
+	if got := b.Selected().ID; got != "US-002" {
+		t.Fatalf("initial selection = %s, want the highest priority task US-002", got)
+	}
+	b.Down()
+	if got := b.Selected().ID; got != "US-001" {
+		t.Fatalf("after Down = %s, want US-001", got)
+	}
+	b.Down()
+	if got := b.Selected().ID; got != "US-001" {
+		t.Fatalf("Down past the end = %s, want US-001", got)
+	}
+	b.Right()
+	if got := b.Selected().ID; got != "US-003" {
+		t.Fatalf("after Right = %s, want US-003", got)
+	}
+	b.Right()
+	if b.Selected() != nil {
+		t.Fatalf("empty column selected %s", b.Selected().ID)
+	}
+	if got := b.Neighbor(-1); got != b.cfg.Statuses[1] {
+		t.Fatalf("Neighbor(-1) = %q, want %q", got, b.cfg.Statuses[1])
+	}
+}
+
+func TestBoardNeighborAtEnds(t *testing.T) {
+	b := testBoard()
+	if got := b.Neighbor(-1); got != "" {
+		t.Fatalf("Neighbor(-1) in the first column = %q, want none", got)
+	}
+	for range b.cfg.Statuses {
+		b.Right()
+	}
+	if got := b.Neighbor(1); got != "" {
+		t.Fatalf("Neighbor(1) in the last column = %q, want none", got)
+	}
+}
+
+func TestBoardLoadKeepsSelection(t *testing.T) {
+	b := testBoard()
+	b.Select("US-003")
+
+	cfg := b.cfg
+	b.Load([]task.Task{
+		{ID: "US-001", Title: "Login page", Status: cfg.Statuses[0], Priority: "low"},
+		{ID: "US-003", Title: "Billing", Status: cfg.Statuses[2], Priority: "medium"},
+	})
+	if got := b.Selected().ID; got != "US-003" {
+		t.Fatalf("selection after reload = %s, want US-003 in its new column", got)
+	}
+}
+
+func TestBoardFilter(t *testing.T) {
+	b := testBoard()
+
+	b.SetFilter("PAY")
+	if got := len(b.columns[0]); got != 0 {
+		t.Fatalf("first column has %d tasks, want none matching the tag", got)
+	}
+	if got := len(b.columns[1]); got != 1 {
+		t.Fatalf("second column has %d tasks, want US-003", got)
+	}
+
+	b.SetFilter("ana")
+	if got := len(b.columns[0]); got != 1 || b.columns[0][0].ID != "US-002" {
+		t.Fatalf("assignee filter = %v, want US-002", b.columns[0])
+	}
+
+	b.SetFilter("")
+	if got := len(b.columns[0]); got != 2 {
+		t.Fatalf("cleared filter shows %d tasks, want 2", got)
+	}
+}
+
+func TestBoardRender(t *testing.T) {
+	b := testBoard()
+	out := b.Render(120, 10)
+
+	lines := strings.Split(out, "\r\n")
+	if len(lines) != 10 {
+		t.Fatalf("rendered %d lines, want 10", len(lines))
+	}
+	for _, want := range []string{"Demo", "US-002 Signup @ana", "US-003 Billing", b.cfg.Statuses[0] + " (2)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render is missing %q:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(out, reverse+"US-002") {
+		t.Errorf("selected task is not highlighted:\n%s", out)
+	}
+
+	b.Help = true
+	if out := b.Render(120, 10); !strings.Contains(out, "$EDITOR") {
+		t.Errorf("help is missing the edit key:\n%s", out)
+	}
+}
+
+func TestFit(t *testing.T) {
+	if got := fit("kanban", 4); got != "kan…" {
+		t.Errorf("fit = %q, want kan…", got)
+	}
+	if got := pad("ab", 4); got != "ab  " {
+		t.Errorf("pad = %q, want %q", got, "ab  ")
+	}
+}
@@ -0,0 +1,66 @@
+package tui
+
+import "unicode/utf8"
+
+// Key is one key press read from the terminal: a printable rune, or one of
+// the named keys below.
+type Key string
+
+const (
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyEnter     Key = "enter"
+	KeyEsc       Key = "esc"
+	KeyBackspace Key = "backspace"
+	KeyCtrlC     Key = "ctrl-c"
+)
+
+// parseKeys splits a chunk of raw terminal input into keys. Escape
+// sequences arrive whole in a single read, so a lone ESC is the Escape key.
+// Unknown sequences are dropped.
+func parseKeys(in []byte) []Key {
+	var keys []Key
+	for len(in) > 0 {
+		switch {
+		case in[0] == 0x1b && len(in) >= 3 && (in[1] == '[' || in[1] == 'O'):
+			// The final byte names the key; parameters before it, as in
+			// ESC [ 1 ; 2 C for shift-right, are ignored.
+			n := 2
+			for n < len(in)-1 && (in[n] < 0x40 || in[n] > 0x7e) {
+				n++
+			}
+			switch in[n] {
+			case 'A':
+				keys = append(keys, KeyUp)
+			case 'B':
+				keys = append(keys, KeyDown)
+			case 'C':
+				keys = append(keys, KeyRight)
+			case 'D':
+				keys = append(keys, KeyLeft)
+			}
+			in = in[n+1:]
+		case in[0] == 0x1b:
+			keys = append(keys, KeyEsc)
+			in = in[1:]
+		case in[0] == '\r' || in[0] == '\n':
+			keys = append(keys, KeyEnter)
+			in = in[1:]
+		case in[0] == 0x7f || in[0] == 0x08:
+			keys = append(keys, KeyBackspace)
+			in = in[1:]
+		case in[0] == 0x03:
+			keys = append(keys, KeyCtrlC)
+			in = in[1:]
+		case in[0] < 0x20:
+			in = in[1:]
+		default:
+			r, size := utf8.DecodeRune(in)
+			keys = append(keys, Key(string(r)))
+			in = in[size:]
+		}
+	}
+	return keys
+}
@@ -0,0 +1,26 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Key
+	}{
+		{"q", []Key{"q"}},
+		{"\x1b[A\x1b[B\x1b[C\x1b[D", []Key{KeyUp, KeyDown, KeyRight, KeyLeft}},
+		{"\x1bOA", []Key{KeyUp}},
+		{"\x1b", []Key{KeyEsc}},
+		{"\x1b[1;2Cx", []Key{KeyRight, "x"}},
+		{"\r\x7f\x03", []Key{KeyEnter, KeyBackspace, KeyCtrlC}},
+		{"é", []Key{"é"}},
+	}
+	for _, tt := range tests {
+		if got := parseKeys([]byte(tt.in)); !slices.Equal(got, tt.want) {
+			t.Errorf("parseKeys(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/store"
+	"github.com/andybarilla/skeeter/internal/watch"
+	"golang.org/x/term"
+)
+
+// Options configure Run.
+type Options struct {
+	// Edit opens the task with the given ID in the user's editor. The board
+	// hands the terminal over while it runs.
+	Edit func(id string) error
+	// PollInterval is how often stores without filesystem events are
+	// checked for changes.
+	PollInterval time.Duration
+}
+
+// resizeInterval is how often the terminal size is checked.
+const resizeInterval = 250 * time.Millisecond
+
+// Run shows the board for s full screen on the terminal until the user quits
+// or ctx is cancelled. The board reloads whenever tasks change, whether from
+// the board itself, an agent or a teammate's push.
+func Run(ctx context.Context, s store.Store, in, out *os.File, opts Options) error {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("the board needs an interactive terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	enter := func() { fmt.Fprint(out, "\x1b[?1049h\x1b[?25l") }
+	leave := func() { fmt.Fprint(out, "\x1b[?25h\x1b[?1049l") }
+	enter()
+	defer func() {
+		leave()
+		term.Restore(fd, state)
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	b := NewBoard(s.GetConfig())
+	reload := func() {
+		tasks, err := s.List(store.Filter{})
+		if err != nil {
+			b.Message = err.Error()
+			return
+		}
+		b.Load(tasks)
+	}
+	reload()
+
+	width, height, _ := term.GetSize(int(out.Fd()))
+	draw := func() {
+		fmt.Fprint(out, "\x1b[H\x1b[2J"+b.Render(width, height))
+	}
+
+	changes := make(chan struct{}, 1)
+	go watchStore(ctx, s, opts.PollInterval, func([]string) {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	})
+
+	// The reader only reads when asked, so it leaves stdin alone while an
+	// editor has the terminal.
+	keys := make(chan []Key)
+	want := make(chan struct{}, 1)
+	go func() {
+		buf := make([]byte, 64)
+		for range want {
+			n, err := in.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- parseKeys(buf[:n])
+		}
+	}()
+	defer close(want)
+	want <- struct{}{}
+
+	resize := time.NewTicker(resizeInterval)
+	defer resize.Stop()
+
+	filtering := false
+	draw()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changes:
+			reload()
+		case <-resize.C:
+			w, h, _ := term.GetSize(int(out.Fd()))
+			if w == width && h == height {
+				continue
+			}
+			width, height = w, h
+		case batch, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, k := range batch {
+				b.Message = ""
+				if filtering {
+					filtering = editFilter(b, k)
+					continue
+				}
+				switch k {
+				case "q", KeyCtrlC:
+					return nil
+				case "h", KeyLeft:
+					b.Left()
+				case "l", KeyRight:
+					b.Right()
+				case "k", KeyUp:
+					b.Up()
+				case "j", KeyDown:
+					b.Down()
+				case "H":
+					move(s, b, -1)
+					reload()
+				case "L":
+					move(s, b, 1)
+					reload()
+				case "e", KeyEnter:
+					t := b.Selected()
+					if t == nil || opts.Edit == nil {
+						continue
+					}
+					leave()
+					term.Restore(fd, state)
+					err := opts.Edit(t.ID)
+					state, _ = term.MakeRaw(fd)
+					enter()
+					if err != nil {
+						b.Message = err.Error()
+					} else {
+						b.Message = "Updated " + t.ID
+					}
+					reload()
+				case "/":
+					filtering = true
+				case KeyEsc:
+					b.SetFilter("")
+				case "r":
+					if err := refresh(s); err != nil {
+						b.Message = err.Error()
+					}
+					reload()
+				case "?":
+					b.Help = !b.Help
+				}
+			}
+			if filtering {
+				b.Message = "/" + b.Filter
+			}
+			want <- struct{}{}
+		}
+		draw()
+	}
+}
+
+// editFilter applies a key typed while filtering and reports whether the
+// user is still typing.
+func editFilter(b *Board, k Key) bool {
+	switch k {
+	case KeyEnter:
+		return false
+	case KeyEsc, KeyCtrlC:
+		b.SetFilter("")
+		return false
+	case KeyBackspace:
+		if r := []rune(b.Filter); len(r) > 0 {
+			b.SetFilter(string(r[:len(r)-1]))
+		}
+	case KeyUp, KeyDown, KeyLeft, KeyRight:
+	default:
+		b.SetFilter(b.Filter + string(k))
+	}
+	return true
+}
+
+// move moves the selected task delta columns along the workflow.
+func move(s store.Store, b *Board, delta int) {
+	selected := b.Selected()
+	status := b.Neighbor(delta)
+	if selected == nil || status == "" {
+		return
+	}
+	t, err := s.Get(selected.ID)
+	if err != nil {
+		b.Message = err.Error()
+		return
+	}
+	old := t.Status
+	if err := store.ChangeStatus(s, t, status, false); err != nil {
+		b.Message = err.Error()
+		return
+	}
+	if err := s.Update(t); err != nil {
+		b.Message = err.Error()
+		return
+	}
+	b.Message = fmt.Sprintf("%s: %s -> %s", t.ID, old, status)
+	b.Select(t.ID)
+}
+
+// refresh pulls the latest tasks for stores that keep a local copy.
+func refresh(s store.Store) error {
+	if r, ok := s.(interface{ Refresh() error }); ok {
+		return r.Refresh()
+	}
+	return nil
+}
+
+// watchStore notifies about task changes in s until ctx is cancelled: from
+// filesystem events for a local store, or by polling a remote one.
+func watchStore(ctx context.Context, s store.Store, interval time.Duration, notify func(ids []string)) {
+	if fs, ok := s.(*store.FilesystemStore); ok {
+		watch.Dir(ctx, filepath.Join(fs.Dir, "tasks"), notify)
+		return
+	}
+	watch.Poll(ctx, interval, func() (watch.Snapshot, error) {
+		prints, err := store.Fingerprint(s)
+		return watch.Snapshot(prints), err
+	}, notify)
+}
@@ -0,0 +1,148 @@
+// Package watch reports which tasks changed, either from filesystem events
+// on a tasks directory or by polling a snapshot of a remote store.
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Debounce is how long Dir waits for a burst of file events to settle
+// before notifying. Editors and git often touch a file several times in a
+// row.
+var Debounce = 200 * time.Millisecond
+
+// PollInterval is how often Dir checks the directory when filesystem events
+// are unavailable.
+var PollInterval = 2 * time.Second
+
+// Snapshot maps task IDs to a fingerprint that changes whenever the task does.
+type Snapshot map[string]string
+
+// Dir watches a tasks directory and calls notify with the sorted IDs of task
+// files that were created, changed or removed. It falls back to polling
+// when the platform can't deliver filesystem events. Dir blocks until ctx is
+// cancelled.
+func Dir(ctx context.Context, dir string, notify func(ids []string)) {
+	w, err := fsnotify.NewWatcher()
+	if err == nil {
+		err = w.Add(dir)
+	}
+	if err != nil {
+		if w != nil {
+			w.Close()
+		}
+		Poll(ctx, PollInterval, func() (Snapshot, error) { return files(dir) }, notify)
+		return
+	}
+	defer w.Close()
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(Debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case event, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			name := filepath.Base(event.Name)
+			if filepath.Ext(name) != ".md" || strings.HasPrefix(name, ".") {
+				continue
+			}
+			pending[strings.TrimSuffix(name, ".md")] = true
+			timer.Reset(Debounce)
+		case <-w.Errors:
+			// Overflows and similar errors lose events; report nothing
+			// rather than guessing.
+		case <-timer.C:
+			notify(sortedKeys(pending))
+			pending = make(map[string]bool)
+		}
+	}
+}
+
+// Poll calls snapshot every interval and notifies with the sorted IDs whose
+// fingerprint changed, appeared or disappeared since the previous call.
+// Failed snapshots are skipped. Poll blocks until ctx is cancelled.
+func Poll(ctx context.Context, interval time.Duration, snapshot func() (Snapshot, error), notify func(ids []string)) {
+	prev, _ := snapshot()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		next, err := snapshot()
+		if err != nil {
+			continue
+		}
+		if prev != nil {
+			if changed := Diff(prev, next); len(changed) > 0 {
+				notify(changed)
+			}
+		}
+		prev = next
+	}
+}
+
+// Diff returns the sorted IDs that differ between two snapshots.
+func Diff(prev, next Snapshot) []string {
+	changed := make(map[string]bool)
+	for id, fp := range next {
+		if prev[id] != fp {
+			changed[id] = true
+		}
+	}
+	for id := range prev {
+		if _, ok := next[id]; !ok {
+			changed[id] = true
+		}
+	}
+	return sortedKeys(changed)
+}
+
+// files fingerprints the task files in dir by modification time and size.
+func files(dir string) (Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	snap := make(Snapshot, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".md" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		snap[strings.TrimSuffix(e.Name(), ".md")] = strconv.FormatInt(info.ModTime().UnixNano(), 10) + ":" + strconv.FormatInt(info.Size(), 10)
+	}
+	return snap, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,73 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDirReportsChangedTasks(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "US-001.md"), []byte("one"), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got := make(chan []string, 10)
+	go Dir(ctx, dir, func(ids []string) { got <- ids })
+
+	// Give the watcher a moment to start before touching files.
+	time.Sleep(50 * time.Millisecond)
+	os.WriteFile(filepath.Join(dir, "US-001.md"), []byte("changed"), 0644)
+	os.WriteFile(filepath.Join(dir, "US-002.md"), []byte("new"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644)
+
+	select {
+	case ids := <-got:
+		if !slices.Equal(ids, []string{"US-001", "US-002"}) {
+			t.Errorf("ids = %v, want [US-001 US-002] in one batch", ids)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change notification")
+	}
+}
+
+func TestPoll(t *testing.T) {
+	snapshots := []Snapshot{
+		{"US-001": "a", "US-002": "b"},
+		{"US-001": "a", "US-002": "b"},
+		{"US-001": "changed", "US-003": "c"},
+	}
+	calls := 0
+	snapshot := func() (Snapshot, error) {
+		snap := snapshots[min(calls, len(snapshots)-1)]
+		calls++
+		return snap, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got := make(chan []string, 10)
+	go Poll(ctx, 10*time.Millisecond, snapshot, func(ids []string) { got <- ids })
+
+	select {
+	case ids := <-got:
+		if !slices.Equal(ids, []string{"US-001", "US-002", "US-003"}) {
+			t.Errorf("ids = %v, want [US-001 US-002 US-003]", ids)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change notification")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	prev := Snapshot{"US-001": "a", "US-002": "b"}
+	if changed := Diff(prev, Snapshot{"US-001": "a", "US-002": "b"}); len(changed) != 0 {
+		t.Errorf("Diff of equal snapshots = %v", changed)
+	}
+	if changed := Diff(prev, Snapshot{"US-002": "x"}); !slices.Equal(changed, []string{"US-001", "US-002"}) {
+		t.Errorf("Diff = %v, want [US-001 US-002]", changed)
+	}
+}
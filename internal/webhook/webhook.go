@@ -0,0 +1,303 @@
+// Package webhook delivers task events to the HTTP endpoints configured
+// under webhooks in config.yaml. Every event is written to an outbox
+// directory before it is sent and removed once the endpoint accepts it, so
+// a crash or an unreachable endpoint delays delivery instead of losing it.
+// Delivery is at least once; receivers can use the delivery ID to drop
+// repeats.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// The events a webhook can subscribe to.
+const (
+	EventCreate       = "create"
+	EventUpdate       = "update"
+	EventStatusChange = "status-change"
+)
+
+// Events lists every event name webhooks accept.
+var Events = []string{EventCreate, EventUpdate, EventStatusChange}
+
+// Request headers. The signature is "sha256=" followed by the hex HMAC of
+// the body, keyed with the webhook's secret.
+const (
+	SignatureHeader = "X-Skeeter-Signature"
+	EventHeader     = "X-Skeeter-Event"
+	DeliveryHeader  = "X-Skeeter-Delivery"
+)
+
+// Payload is the JSON body POSTed for an event. Previous is the task
+// before an update, and absent for creates.
+type Payload struct {
+	ID       string     `json:"id"`
+	Event    string     `json:"event"`
+	Time     string     `json:"time"`
+	Project  string     `json:"project"`
+	Task     *task.Task `json:"task"`
+	Previous *task.Task `json:"previous,omitempty"`
+}
+
+// NewPayload returns the payload for event on t, with a fresh delivery ID.
+func NewPayload(event, project string, old, t *task.Task) Payload {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return Payload{
+		ID:       hex.EncodeToString(b),
+		Event:    event,
+		Time:     time.Now().UTC().Format(time.RFC3339),
+		Project:  project,
+		Task:     t,
+		Previous: old,
+	}
+}
+
+// Delivery is one payload waiting in the outbox for one endpoint.
+type Delivery struct {
+	ID        string          `json:"id" yaml:"id"`
+	URL       string          `json:"url" yaml:"url"`
+	Event     string          `json:"event" yaml:"event"`
+	Created   string          `json:"created" yaml:"created"`
+	Attempts  int             `json:"attempts" yaml:"attempts"`
+	LastError string          `json:"last_error,omitempty" yaml:"last_error,omitempty"`
+	Body      json.RawMessage `json:"body" yaml:"-"`
+
+	file string
+}
+
+// Outbox holds undelivered events as one JSON file per delivery.
+type Outbox struct {
+	Dir string
+	// Client sends the requests; nil means a client with a 10s timeout.
+	Client *http.Client
+	// Attempts is how many times Flush tries a delivery before leaving it
+	// for the next flush; zero means 3.
+	Attempts int
+	// Backoff is the wait before the first retry, doubling after each;
+	// zero means 250ms.
+	Backoff time.Duration
+}
+
+// New returns an outbox in dir with the default client and retry policy.
+func New(dir string) *Outbox {
+	return &Outbox{Dir: dir}
+}
+
+// Enqueue writes a delivery of p for each webhook subscribed to its event.
+func (o *Outbox) Enqueue(hooks []config.Webhook, p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err := o.ensureDir(); err != nil {
+		return err
+	}
+	for i, hook := range hooks {
+		if !hook.Wants(p.Event) {
+			continue
+		}
+		d := Delivery{ID: p.ID, URL: hook.URL, Event: p.Event, Created: p.Time, Body: body}
+		// Names sort in the order events happened.
+		d.file = filepath.Join(o.Dir, fmt.Sprintf("%020d-%s-%d.json", time.Now().UnixNano(), p.ID, i))
+		if err := o.write(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pending returns the deliveries in the outbox, oldest first.
+func (o *Outbox) Pending() ([]Delivery, error) {
+	entries, err := os.ReadDir(o.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pending []Delivery
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(o.Dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var d Delivery
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		d.file = path
+		pending = append(pending, d)
+	}
+	return pending, nil
+}
+
+// Flush tries to send every pending delivery, oldest first, and returns
+// the ones still pending. Once a delivery to an endpoint fails, later ones
+// to the same endpoint wait, so each endpoint sees events in order.
+// Deliveries to endpoints no longer in hooks are dropped.
+func (o *Outbox) Flush(hooks []config.Webhook) ([]Delivery, error) {
+	pending, err := o.Pending()
+	if err != nil {
+		return nil, err
+	}
+	stalled := map[string]bool{}
+	var left []Delivery
+	for _, d := range pending {
+		i := slices.IndexFunc(hooks, func(h config.Webhook) bool { return h.URL == d.URL })
+		if i < 0 {
+			if err := os.Remove(d.file); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if stalled[d.URL] {
+			left = append(left, d)
+			continue
+		}
+		if err := o.deliver(hooks[i], &d); err != nil {
+			stalled[d.URL] = true
+			d.LastError = err.Error()
+			if err := o.write(d); err != nil {
+				return nil, err
+			}
+			left = append(left, d)
+			continue
+		}
+		if err := os.Remove(d.file); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// Clear drops every pending delivery and returns how many there were.
+func (o *Outbox) Clear() (int, error) {
+	pending, err := o.Pending()
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range pending {
+		if err := os.Remove(d.file); err != nil {
+			return 0, err
+		}
+	}
+	return len(pending), nil
+}
+
+// deliver POSTs d to hook, retrying with backoff, and counts the attempts.
+func (o *Outbox) deliver(hook config.Webhook, d *Delivery) error {
+	attempts, backoff := o.Attempts, o.Backoff
+	if attempts <= 0 {
+		attempts = 3
+	}
+	if backoff <= 0 {
+		backoff = 250 * time.Millisecond
+	}
+
+	var err error
+	for i := range attempts {
+		if i > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		d.Attempts++
+		if err = o.post(hook, d); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (o *Outbox) post(hook config.Webhook, d *Delivery) error {
+	req, err := http.NewRequest(http.MethodPost, d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "skeeter")
+	req.Header.Set(EventHeader, d.Event)
+	req.Header.Set(DeliveryHeader, d.ID)
+	if key := hook.SigningKey(); key != "" {
+		req.Header.Set(SignatureHeader, Sign(key, d.Body))
+	}
+
+	client := o.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", d.URL, resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the signature header value for body under key.
+func Sign(key string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// write saves d atomically, so a crash never leaves half a delivery.
+func (o *Outbox) write(d Delivery) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(o.Dir, "delivery-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), d.file); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// ensureDir creates the outbox with its own .gitignore, so pending events
+// never show up in git status.
+func (o *Outbox) ensureDir() error {
+	if err := os.MkdirAll(o.Dir, 0755); err != nil {
+		return err
+	}
+	ignore := filepath.Join(o.Dir, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		return os.WriteFile(ignore, []byte("*\n"), 0644)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andybarilla/skeeter/internal/config"
+	"github.com/andybarilla/skeeter/internal/task"
+)
+
+// receiver records the requests it accepts and fails the first failures.
+type receiver struct {
+	mu       sync.Mutex
+	failures int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures > 0 {
+		r.failures--
+		http.Error(w, "down", http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := io.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+}
+
+func testOutbox(t *testing.T) *Outbox {
+	return &Outbox{Dir: filepath.Join(t.TempDir(), ".outbox"), Attempts: 2, Backoff: time.Millisecond}
+}
+
+func TestFlushDeliversSignedEvents(t *testing.T) {
+	rec := &receiver{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+	t.Setenv("HOOK_SECRET", "s3cret")
+	hooks := []config.Webhook{{URL: srv.URL, Secret: "$HOOK_SECRET"}}
+
+	o := testOutbox(t)
+	tk := &task.Task{ID: "US-001", Title: "Thing", Status: "backlog"}
+	if err := o.Enqueue(hooks, NewPayload(EventCreate, "demo", nil, tk)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	left, err := o.Flush(hooks)
+	if err != nil || len(left) != 0 {
+		t.Fatalf("Flush = %v, %v", left, err)
+	}
+
+	if len(rec.requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(rec.requests))
+	}
+	req, body := rec.requests[0], rec.bodies[0]
+	if got := req.Header.Get(SignatureHeader); got != Sign("s3cret", body) {
+		t.Errorf("signature = %q, want the HMAC of the body", got)
+	}
+	if req.Header.Get(EventHeader) != EventCreate {
+		t.Errorf("event header = %q", req.Header.Get(EventHeader))
+	}
+	var p Payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Event != EventCreate || p.Project != "demo" || p.Task.ID != "US-001" || p.Previous != nil {
+		t.Errorf("payload = %+v", p)
+	}
+	if pending, _ := o.Pending(); len(pending) != 0 {
+		t.Errorf("outbox still holds %d deliveries", len(pending))
+	}
+}
+
+func TestFlushKeepsFailedDeliveriesInOrder(t *testing.T) {
+	rec := &receiver{failures: 2}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+	hooks := []config.Webhook{{URL: srv.URL}}
+
+	o := testOutbox(t)
+	for _, title := range []string{"First", "Second"} {
+		tk := &task.Task{ID: "US-001", Title: title}
+		o.Enqueue(hooks, NewPayload(EventUpdate, "demo", nil, tk))
+	}
+
+	left, err := o.Flush(hooks)
+	if err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(left) != 2 || left[0].Attempts != 2 || left[0].LastError == "" || left[1].Attempts != 0 {
+		t.Fatalf("left = %+v, want both pending, only the first tried", left)
+	}
+
+	// A later flush, say after a restart, picks up where this one stopped.
+	left, err = New(o.Dir).Flush(hooks)
+	if err != nil || len(left) != 0 {
+		t.Fatalf("second Flush = %v, %v", left, err)
+	}
+	var titles []string
+	for _, body := range rec.bodies {
+		var p Payload
+		json.Unmarshal(body, &p)
+		titles = append(titles, p.Task.Title)
+	}
+	if len(titles) != 2 || titles[0] != "First" || titles[1] != "Second" {
+		t.Errorf("delivered %v, want First then Second", titles)
+	}
+}
+
+func TestEnqueueFiltersEvents(t *testing.T) {
+	o := testOutbox(t)
+	hooks := []config.Webhook{
+		{URL: "http://example.invalid/status", Events: []string{EventStatusChange}},
+		{URL: "http://example.invalid/all"},
+	}
+	o.Enqueue(hooks, NewPayload(EventUpdate, "demo", nil, &task.Task{ID: "US-001"}))
+
+	pending, err := o.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].URL != "http://example.invalid/all" {
+		t.Fatalf("pending = %+v, want only the unfiltered endpoint", pending)
+	}
+
+	// Endpoints removed from the config lose their deliveries.
+	if left, err := o.Flush(nil); err != nil || len(left) != 0 {
+		t.Errorf("Flush = %v, %v, want the delivery dropped", left, err)
+	}
+}